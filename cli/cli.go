@@ -0,0 +1,902 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/KharpukhaevV/postui/converter"
+	"github.com/KharpukhaevV/postui/httpclient"
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// Коды завершения
+const (
+	ExitOK            = 0
+	ExitError         = 1 // Ошибка выполнения запроса или чтения конфигурации
+	ExitUsage         = 2 // Неверные аргументы командной строки
+	ExitStatusFailure = 3 // Код ответа попал в диапазон --fail-on
+	ExitAssertFailure = 4 // Не пройдена проверка ответа
+)
+
+const usage = `Использование:
+  postui                      запуск интерфейса
+  postui <файл.http>          запуск интерфейса с коллекцией из файла .http / .rest
+  postui list [-f <файл>]     список сохраненных запросов
+  postui run [флаги] <имя>    выполнить сохраненный запрос (имя или путь "Папка/Запрос")
+  postui runner [флаги] [папка]
+                              выполнить по порядку запросы папки или всей коллекции
+  postui load [флаги] <имя>   нагрузочный тест сохраненного запроса
+  postui send [флаги] <URL>   выполнить произвольный запрос
+  postui import curl [--name <имя>] '<команда curl>'
+                              сохранить запрос из команды curl
+  postui export curl [-e <имя>] <имя>
+                              вывести сохраненный запрос как команду curl
+  postui import postman [--env <окружение>] <файл>
+                              импортировать коллекцию Postman v2.1 в отдельную папку
+  postui import openapi [--env <окружение>] <файл>
+                              создать запросы из спецификации OpenAPI 3 / Swagger 2
+  postui import har <файл>    сохранить запросы из файла HAR
+  postui export har [-n <количество>] [-o <файл>]
+                              экспортировать историю запросов в HAR 1.2
+  postui export postman [-e <окружение>] [--name <имя>] [-o <файл>]
+                              экспортировать запросы в коллекцию Postman v2.1
+
+Флаги run:
+  -f <файл>        файл .http / .rest, из которого берется запрос
+
+Флаги runner:
+  -f <файл>        файл .http / .rest, из которого берутся запросы
+  -e <имя>         окружение для подстановки переменных (по умолчанию активное)
+  -n <количество>  количество итераций (по умолчанию по числу строк --data или 1)
+  --data <файл>    файл CSV со строкой заголовков или JSON с массивом объектов;
+                   строки по очереди становятся переменными итераций
+  --delay <время>  пауза между запросами, например 500ms
+  --stop-on-failure
+                   остановить запуск после первого неуспешного запроса
+  --fail-on <коды> диапазоны кодов ответа, считающиеся ошибкой (по умолчанию 400-599)
+  --junit <файл>   сохранить отчет JUnit XML: итерация - testsuite, запрос - testcase,
+                   непройденные проверки - failure, ошибка запроса - error
+  --report <файл>  сохранить отчет JSON с временем этапов и данными ответов;
+                   - вместо файла выводит отчет в stdout, а ход запуска - в stderr
+  Настройки клиента такие же, как у run и send. Код завершения 1, если запрос
+  не выполнен или запуск прерван, 4 - если не пройдена проверка, 3 - если код
+  ответа попал в --fail-on
+
+Флаги load:
+  -f <файл>        файл .http / .rest, из которого берется запрос
+  -e <имя>         окружение для подстановки переменных (по умолчанию активное)
+  -c <количество>  количество одновременных запросов (по умолчанию 10)
+  -n <количество>  общее количество запросов (по умолчанию 100, 0 - до окончания --duration)
+  --duration <время>
+                   длительность теста, например 30s; новые запросы после нее не отправляются
+  --rps <число>    целевое количество запросов в секунду (по умолчанию без ограничения)
+  --fail-on <коды> диапазоны кодов ответа, считающиеся ошибкой (по умолчанию 400-599)
+  --report <файл>  сохранить итоги в JSON; - выводит отчет в stdout, а итоги - в stderr
+  Настройки клиента такие же, как у run и send. Код завершения 1, если тест прерван
+  или были ошибки выполнения, 3 - если код ответа попал в --fail-on
+
+Флаги run и send:
+  -e <имя>         окружение для подстановки переменных (по умолчанию активное)
+  -o <формат>      формат вывода: raw, pretty, json (по умолчанию pretty)
+  --fail-on <коды> диапазоны кодов ответа, считающиеся ошибкой,
+                   например 400-599 или 404,500-599; none - отключить (по умолчанию 400-599)
+  --save <файл>    сохранить тело ответа в новый файл
+  --assert <проверка>
+                   проверка ответа в дополнение к проверкам запроса, например
+                   "status in 2xx", "header Content-Type contains json",
+                   "json $.items[0].id == 42", "body matches ^ok", "time < 500ms";
+                   можно указывать несколько раз. Если проверка не пройдена,
+                   код завершения 4
+  --extract <правило>
+                   записать значение из ответа в переменную окружения -e
+                   или активного, например "token = json $.access_token",
+                   "location = header Location", "sid = cookie session",
+                   "code = body code=(\d+)", "result = status";
+                   можно указывать несколько раз
+
+Настройки клиента для run и send (заменяют настройки запроса и общие настройки):
+  --timeout <время>      таймаут запроса, например 10s или 1m; 0 - без ограничения
+  --no-follow            не выполнять перенаправления
+  --max-redirects <n>    максимальное количество перенаправлений (по умолчанию 10)
+  -k                     не проверять сертификат сервера
+  --cacert <файл>        файл PEM с дополнительными корневыми сертификатами
+  --cert <файл>          клиентский сертификат PEM для mTLS
+  --key <файл>           ключ клиентского сертификата (если он не в файле --cert)
+  --tls-min <версия>     минимальная версия TLS: 1.0, 1.1, 1.2, 1.3
+  --proxy <URL>          прокси http://, https://, socks5:// или socks5h://
+  --no-cookies           не отправлять и не сохранять cookies окружения
+  --max-body <размер>    размер тела ответа в памяти, например 50MB (по умолчанию 10MB);
+                         больший ответ выводится -o raw и сохраняется --save полностью
+
+Флаги send:
+  -X <метод>       HTTP метод (по умолчанию GET)
+  -H <заголовок>   заголовок в формате "Key: Value", можно указывать несколько раз
+  -d <тело>        тело запроса
+  -F <поле>        поле multipart "name=value" или файл "name=@path;type=image/png",
+                   можно указывать несколько раз (метод по умолчанию POST)
+  --data-file <файл>
+                   отправить содержимое файла телом запроса (метод по умолчанию POST)
+`
+
+// Run выполняет команду командной строки и возвращает код завершения
+func Run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return ExitUsage
+	}
+
+	switch args[0] {
+	case "list":
+		return runList(args[1:], stdout, stderr)
+	case "run":
+		return runSaved(args[1:], stdout, stderr)
+	case "runner":
+		return runCollection(args[1:], stdout, stderr)
+	case "load":
+		return runLoad(args[1:], stdout, stderr)
+	case "send":
+		return runSend(args[1:], stdout, stderr)
+	case "import":
+		return runImport(args[1:], stdout, stderr)
+	case "export":
+		return runExport(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return ExitOK
+	}
+
+	fmt.Fprintf(stderr, "Неизвестная команда: %s\n\n%s", args[0], usage)
+	return ExitUsage
+}
+
+// IsCommand сообщает, является ли аргумент командой командной строки
+func IsCommand(arg string) bool {
+	switch arg {
+	case "list", "run", "runner", "load", "send", "import", "export", "help", "-h", "--help":
+		return true
+	}
+	return false
+}
+
+// --- Команды ---
+
+func runList(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	file := fs.String("f", "", "файл .http / .rest")
+	if _, err := parseFlags(fs, args); err != nil {
+		return ExitUsage
+	}
+
+	collection, err := loadCollection(*file)
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: не удалось загрузить запросы: %v\n", err)
+		return ExitError
+	}
+	for _, sr := range collection.Resolved() {
+		fmt.Fprintf(stdout, "%s\t%s\n", sr.Name, sr.Description())
+	}
+	return ExitOK
+}
+
+func runSaved(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts := registerOutputFlags(fs)
+	file := fs.String("f", "", "файл .http / .rest")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		fmt.Fprintf(stderr, "Ошибка: укажите имя сохраненного запроса\n\n%s", usage)
+		return ExitUsage
+	}
+
+	collection, err := loadCollection(*file)
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: не удалось загрузить запросы: %v\n", err)
+		return ExitError
+	}
+	sr, err := collection.FindRequest(positional[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+		return ExitError
+	}
+	opts.collectionVars = collection.Variables
+	return execute(sr, opts, stdout, stderr)
+}
+
+func runSend(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("send", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts := registerOutputFlags(fs)
+	method := fs.String("X", "GET", "HTTP метод")
+	body := fs.String("d", "", "тело запроса")
+	bodyFile := fs.String("data-file", "", "файл, содержимое которого отправляется телом запроса")
+	var headers headerFlag
+	fs.Var(&headers, "H", "заголовок \"Key: Value\"")
+	var form formFlag
+	fs.Var(&form, "F", "поле multipart \"name=value\" или файл \"name=@path\"")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		fmt.Fprintf(stderr, "Ошибка: укажите URL\n\n%s", usage)
+		return ExitUsage
+	}
+	httpMethod, ok := models.LookupMethod(*method)
+	if !ok {
+		fmt.Fprintf(stderr, "Ошибка: метод %s не поддерживается (доступны: %s)\n",
+			*method, strings.Join(models.MethodNames, ", "))
+		return ExitUsage
+	}
+
+	sr := models.SavedRequest{
+		Method:  httpMethod,
+		URL:     positional[0],
+		Body:    *body,
+		Headers: headers,
+	}
+	switch {
+	case len(form) > 0:
+		sr.BodyType, sr.Form = models.BodyMultipart, form
+	case *bodyFile != "":
+		sr.BodyType, sr.Body = models.BodyBinary, *bodyFile
+	}
+	if sr.BodyType != models.BodyRaw && *body != "" {
+		fmt.Fprintln(stderr, "Ошибка: -d нельзя использовать вместе с -F и --data-file")
+		return ExitUsage
+	}
+	if sr.BodyType != models.BodyRaw && !flagSet(fs, "X") {
+		sr.Method = models.MethodPOST
+	}
+	return execute(sr, opts, stdout, stderr)
+}
+
+func runCollection(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("runner", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	file := fs.String("f", "", "файл .http / .rest")
+	envName := fs.String("e", "", "окружение")
+	opts := models.DefaultRunOptions()
+	fs.IntVar(&opts.Iterations, "n", 0, "количество итераций")
+	fs.StringVar(&opts.DataFile, "data", "", "файл данных CSV или JSON")
+	fs.Func("delay", "пауза между запросами, например 500ms", func(value string) (err error) {
+		opts.Delay, err = models.ParseDelay(value)
+		return err
+	})
+	fs.BoolVar(&opts.StopOnFailure, "stop-on-failure", false, "остановить запуск после первого неуспешного запроса")
+	fs.StringVar(&opts.FailOn, "fail-on", opts.FailOn, "диапазоны кодов ответа, считающиеся ошибкой")
+	junitPath := fs.String("junit", "", "файл отчета JUnit XML (- для stdout)")
+	reportPath := fs.String("report", "", "файл отчета JSON (- для stdout)")
+	var override models.ClientSettings
+	registerSettingsFlags(fs, &override)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if *junitPath == "-" && *reportPath == "-" {
+		fmt.Fprintf(stderr, "Ошибка: в stdout можно вывести только один отчет\n")
+		return ExitUsage
+	}
+	if len(positional) > 1 || opts.Iterations < 0 {
+		fmt.Fprintf(stderr, "Ошибка: укажите не больше одной папки и неотрицательное -n\n\n%s", usage)
+		return ExitUsage
+	}
+	if opts.FailOn == "none" {
+		opts.FailOn = ""
+	} else if _, err := models.ParseStatusRanges(opts.FailOn); err != nil {
+		fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+		return ExitUsage
+	}
+
+	collection, err := loadCollection(*file)
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: не удалось загрузить запросы: %v\n", err)
+		return ExitError
+	}
+	folder := strings.Join(positional, "")
+	requests, err := collection.FolderRequests(folder)
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+		return ExitError
+	}
+	var data []map[string]string
+	if opts.DataFile != "" {
+		if data, err = models.LoadRunData(opts.DataFile); err != nil {
+			fmt.Fprintf(stderr, "Ошибка: файл данных %s: %v\n", opts.DataFile, err)
+			return ExitError
+		}
+	}
+
+	env, vars, err := resolveVariables(*envName)
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+		return ExitError
+	}
+	settings, err := models.LoadClientSettings()
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: не удалось загрузить настройки: %v\n", err)
+		return ExitError
+	}
+	cookies, err := models.LoadCookieStore()
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: не удалось загрузить cookies: %v\n", err)
+		return ExitError
+	}
+
+	if folder == "" {
+		folder = "коллекция"
+	}
+	runner := &httpclient.Runner{
+		Client:    httpclient.NewHTTPClient(),
+		Settings:  settings,
+		Override:  override,
+		Cookies:   cookies.Jar(env),
+		Variables: models.MergeVariables(collection.Variables, vars),
+	}
+	iterations := opts.IterationCount(len(data))
+	// Если отчет выводится в stdout, ход запуска выводится в stderr
+	progress := stdout
+	if *junitPath == "-" || *reportPath == "-" {
+		progress = stderr
+	}
+	// Прерывание завершает запуск с итогами по выполненным запросам
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report := runner.Run(ctx, folder, requests, opts, data, func(result models.RunResult) {
+		fmt.Fprintln(progress, models.FormatRunResult(result, iterations))
+	})
+	fmt.Fprintf(progress, "\n%s\n", models.FormatRunSummary(report))
+
+	if err := storeExtracted(env, report.Variables); err != nil {
+		fmt.Fprintf(stderr, "Предупреждение: %v\n", err)
+	}
+	code := runExitCode(report)
+	exportJUnit := func() ([]byte, error) { return converter.ExportJUnit(report) }
+	if err := writeReport(*junitPath, exportJUnit, stdout); err != nil {
+		fmt.Fprintf(stderr, "Ошибка: отчет JUnit: %v\n", err)
+		code = ExitError
+	}
+	exportJSON := func() ([]byte, error) { return converter.ExportRunReport(report) }
+	if err := writeReport(*reportPath, exportJSON, stdout); err != nil {
+		fmt.Fprintf(stderr, "Ошибка: отчет JSON: %v\n", err)
+		code = ExitError
+	}
+	return code
+}
+
+// writeReport записывает отчет в файл или в stdout ("-").
+// Пустой путь означает, что отчет не нужен.
+func writeReport(path string, export func() ([]byte, error), stdout io.Writer) error {
+	if path == "" {
+		return nil
+	}
+	data, err := export()
+	if err != nil {
+		return err
+	}
+	if path == "-" {
+		_, err = stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// runExitCode возвращает код завершения запуска: прерванный запуск и ошибка
+// выполнения запроса важнее непройденной проверки, а проверка - кода ответа из --fail-on
+func runExitCode(report models.RunReport) int {
+	if report.Canceled {
+		return ExitError
+	}
+	code := ExitOK
+	for _, result := range report.Results {
+		switch {
+		case result.Passed:
+		case result.Error != "":
+			return ExitError
+		case models.CountPassed(result.Assertions) < len(result.Assertions):
+			code = ExitAssertFailure
+		case code == ExitOK:
+			code = ExitStatusFailure
+		}
+	}
+	return code
+}
+
+func runLoad(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("load", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	file := fs.String("f", "", "файл .http / .rest")
+	envName := fs.String("e", "", "окружение")
+	opts := models.DefaultLoadOptions()
+	fs.IntVar(&opts.Concurrency, "c", opts.Concurrency, "количество одновременных запросов")
+	fs.IntVar(&opts.Requests, "n", opts.Requests, "общее количество запросов")
+	fs.DurationVar(&opts.Duration, "duration", 0, "длительность теста, например 30s")
+	fs.Float64Var(&opts.RPS, "rps", 0, "целевое количество запросов в секунду")
+	fs.StringVar(&opts.FailOn, "fail-on", opts.FailOn, "диапазоны кодов ответа, считающиеся ошибкой")
+	reportPath := fs.String("report", "", "файл отчета JSON (- для stdout)")
+	var override models.ClientSettings
+	registerSettingsFlags(fs, &override)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		fmt.Fprintf(stderr, "Ошибка: укажите имя сохраненного запроса\n\n%s", usage)
+		return ExitUsage
+	}
+	// С --duration без -n тест ограничен только временем
+	if !flagSet(fs, "n") && opts.Duration > 0 {
+		opts.Requests = 0
+	}
+	if opts.Concurrency < 1 || opts.Requests < 0 || opts.Duration < 0 || opts.RPS < 0 {
+		fmt.Fprintf(stderr, "Ошибка: -c должен быть положительным, -n, --duration и --rps - неотрицательными\n\n%s", usage)
+		return ExitUsage
+	}
+	if err := opts.Validate(); err != nil {
+		fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+		return ExitUsage
+	}
+	if opts.FailOn == "none" {
+		opts.FailOn = ""
+	} else if _, err := models.ParseStatusRanges(opts.FailOn); err != nil {
+		fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+		return ExitUsage
+	}
+
+	collection, err := loadCollection(*file)
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: не удалось загрузить запросы: %v\n", err)
+		return ExitError
+	}
+	sr, err := collection.FindRequest(positional[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+		return ExitError
+	}
+	env, vars, err := resolveVariables(*envName)
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+		return ExitError
+	}
+	settings, err := models.LoadClientSettings()
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: не удалось загрузить настройки: %v\n", err)
+		return ExitError
+	}
+	cookies, err := models.LoadCookieStore()
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: не удалось загрузить cookies: %v\n", err)
+		return ExitError
+	}
+
+	tester := &httpclient.LoadTester{
+		Client:    httpclient.NewHTTPClient(),
+		Settings:  settings,
+		Override:  override,
+		Cookies:   cookies.Jar(env),
+		Variables: models.MergeVariables(collection.Variables, vars),
+	}
+	// Прерывание завершает тест с итогами по выполненным запросам
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	stats := tester.Run(ctx, sr, opts, nil)
+
+	summary := stdout
+	if *reportPath == "-" {
+		summary = stderr
+	}
+	fmt.Fprintln(summary, models.FormatLoadStats(stats, 80))
+
+	code := loadExitCode(stats)
+	export := func() ([]byte, error) { return converter.ExportLoadReport(stats) }
+	if err := writeReport(*reportPath, export, stdout); err != nil {
+		fmt.Fprintf(stderr, "Ошибка: отчет JSON: %v\n", err)
+		code = ExitError
+	}
+	return code
+}
+
+// loadExitCode возвращает код завершения нагрузочного теста: прерванный тест
+// и ошибки выполнения важнее кодов ответа из --fail-on
+func loadExitCode(stats models.LoadStats) int {
+	switch {
+	case stats.Canceled || len(stats.Errors) > 0:
+		return ExitError
+	case stats.Failed > 0:
+		return ExitStatusFailure
+	}
+	return ExitOK
+}
+
+// --- Выполнение запроса и вывод ---
+
+type outputOptions struct {
+	env            string
+	format         string
+	failOn         string
+	collectionVars []models.Variable
+	settings       models.ClientSettings // настройки клиента, переопределяющие настройки запроса
+	save           string                // файл, в который сохраняется тело ответа
+	assertions     assertFlag            // проверки ответа в дополнение к проверкам запроса
+	extract        extractFlag           // переменные из ответа в дополнение к правилам запроса
+}
+
+func registerOutputFlags(fs *flag.FlagSet) *outputOptions {
+	opts := &outputOptions{}
+	fs.StringVar(&opts.env, "e", "", "окружение")
+	fs.StringVar(&opts.format, "o", "pretty", "формат вывода: raw, pretty, json")
+	fs.StringVar(&opts.failOn, "fail-on", "400-599", "диапазоны кодов ответа, считающиеся ошибкой")
+	fs.StringVar(&opts.save, "save", "", "сохранить тело ответа в файл")
+	fs.Var(&opts.assertions, "assert", "проверка ответа, например \"status in 2xx\"")
+	fs.Var(&opts.extract, "extract", "переменная из ответа, например \"token = json $.access_token\"")
+	registerSettingsFlags(fs, &opts.settings)
+	return opts
+}
+
+// registerSettingsFlags добавляет флаги настроек HTTP клиента. Незаданные флаги
+// не меняют настройки, поэтому действуют значения запроса и общих настроек.
+func registerSettingsFlags(fs *flag.FlagSet, settings *models.ClientSettings) {
+	fs.Func("timeout", "таймаут запроса, например 10s; 0 - без ограничения", func(value string) error {
+		settings.Timeout = value
+		_, err := settings.TimeoutDuration()
+		return err
+	})
+	fs.BoolFunc("no-follow", "не выполнять перенаправления", func(value string) error {
+		follow, err := strconv.ParseBool(value)
+		follow = !follow
+		settings.FollowRedirects = &follow
+		return err
+	})
+	fs.Func("max-redirects", "максимальное количество перенаправлений", func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("ожидается неотрицательное число")
+		}
+		settings.MaxRedirects = &n
+		return nil
+	})
+	fs.BoolFunc("k", "не проверять сертификат сервера", func(value string) error {
+		insecure, err := strconv.ParseBool(value)
+		settings.Insecure = &insecure
+		return err
+	})
+	fs.StringVar(&settings.CACert, "cacert", "", "файл PEM с корневыми сертификатами")
+	fs.StringVar(&settings.ClientCert, "cert", "", "клиентский сертификат PEM")
+	fs.StringVar(&settings.ClientKey, "key", "", "ключ клиентского сертификата PEM")
+	fs.StringVar(&settings.MinTLSVersion, "tls-min", "", "минимальная версия TLS: 1.0, 1.1, 1.2, 1.3")
+	fs.StringVar(&settings.Proxy, "proxy", "", "прокси http://, https://, socks5:// или socks5h://")
+	fs.Func("max-body", "размер тела ответа в памяти, например 50MB", func(value string) error {
+		settings.MaxBodySize = value
+		_, err := settings.MaxBodyBytes()
+		return err
+	})
+	fs.BoolFunc("no-cookies", "не отправлять и не сохранять cookies окружения", func(value string) error {
+		disabled, err := strconv.ParseBool(value)
+		enabled := !disabled
+		settings.Cookies = &enabled
+		return err
+	})
+}
+
+func execute(sr models.SavedRequest, opts *outputOptions, stdout, stderr io.Writer) int {
+	var failRanges []models.StatusRange
+	if opts.failOn != "" && opts.failOn != "none" {
+		ranges, err := models.ParseStatusRanges(opts.failOn)
+		if err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitUsage
+		}
+		failRanges = ranges
+	}
+	if opts.format != "raw" && opts.format != "pretty" && opts.format != "json" {
+		fmt.Fprintf(stderr, "Ошибка: неизвестный формат вывода %q\n", opts.format)
+		return ExitUsage
+	}
+
+	envName, vars, err := resolveVariables(opts.env)
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+		return ExitError
+	}
+	vars = models.MergeVariables(opts.collectionVars, vars)
+	settings, err := models.LoadClientSettings()
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: не удалось загрузить настройки: %v\n", err)
+		return ExitError
+	}
+
+	cookies, err := models.LoadCookieStore()
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: не удалось загрузить cookies: %v\n", err)
+		return ExitError
+	}
+
+	sr.Assertions = append(slices.Clone(sr.Assertions), opts.assertions...)
+	sr.Extract = append(slices.Clone(sr.Extract), opts.extract...)
+	req := httpclient.NewHTTPRequestFromSaved(sr, vars)
+	req.Settings = req.Settings.Merge(opts.settings)
+	req.Cookies = cookies.Jar(envName)
+	client := httpclient.NewHTTPClientWithSettings(settings.Expand(vars))
+	// Прерывание отменяет запрос, временный файл тела при этом удаляется
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	response, err := client.SendRequest(ctx, &req)
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("запрос прерван")
+		}
+		if opts.format == "json" {
+			writeJSON(stdout, map[string]string{"error": err.Error()})
+		}
+		fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+		return ExitError
+	}
+
+	defer models.RemoveBodyFile(response)
+	req.CheckResponse(&response)
+
+	switch opts.format {
+	case "raw":
+		fmt.Fprintf(stderr, "%s (%s)\n", response.Status, response.Time)
+		if _, err := models.CopyResponseBody(stdout, response); err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitError
+		}
+		if len(response.Assertions) > 0 {
+			fmt.Fprint(stderr, models.FormatAssertionResults(response.Assertions))
+		}
+		if len(response.Extracted) > 0 {
+			fmt.Fprint(stderr, models.FormatExtractionResults(response.Extracted))
+		}
+	case "pretty":
+		fmt.Fprintf(stdout, "%s (%s)\n%s\n\n", response.Status, response.Time, response.Timings.Summary())
+		fmt.Fprintln(stdout, prettyBody(response))
+		if response.BodyFile != "" && opts.save == "" {
+			fmt.Fprintf(stderr, "Показано начало тела (%s из %s); полностью: -o raw или --save <файл>\n",
+				models.FormatSize(int64(len(response.Body))), models.FormatSize(response.DecodedSize))
+		}
+		if len(response.Assertions) > 0 {
+			fmt.Fprintf(stdout, "\n%s", models.FormatAssertionResults(response.Assertions))
+		}
+		if len(response.Extracted) > 0 {
+			fmt.Fprintf(stdout, "\n%s", models.FormatExtractionResults(response.Extracted))
+		}
+	case "json":
+		writeJSON(stdout, newEnvelope(response))
+	}
+
+	if opts.save != "" {
+		written, err := models.WriteResponseBody(response, opts.save)
+		if err != nil {
+			fmt.Fprintf(stderr, "Ошибка: не удалось сохранить тело: %v\n", err)
+			return ExitError
+		}
+		fmt.Fprintf(stderr, "Тело сохранено в %s (%s)\n", opts.save, models.FormatSize(written))
+	}
+	if err := storeExtracted(envName, models.ExtractedVariables(response.Extracted)); err != nil {
+		fmt.Fprintf(stderr, "Предупреждение: %v\n", err)
+	}
+
+	if models.CountPassed(response.Assertions) < len(response.Assertions) {
+		return ExitAssertFailure
+	}
+	if models.StatusInRanges(failRanges, response.StatusCode) {
+		return ExitStatusFailure
+	}
+	return ExitOK
+}
+
+// prettyBody форматирует тело ответа для вывода в терминал. Двоичное тело
+// выводится шестнадцатеричным дампом.
+func prettyBody(response models.ResponseData) string {
+	if response.Binary {
+		return models.FormatHexDump(response.Body)
+	}
+	kind := models.DetectContentKind(models.HeaderValue(response.Headers, "Content-Type"), response.Body)
+	return models.SanitizeText(models.PrettyBody(kind, response.Body))
+}
+
+// loadCollection загружает запросы из файла .http или из requests.json
+func loadCollection(path string) (models.Collection, error) {
+	if path == "" {
+		return models.NewJSONStore().Load()
+	}
+	if !converter.IsHTTPFile(path) {
+		return models.Collection{}, fmt.Errorf("файл %s не является файлом .http / .rest", path)
+	}
+	return converter.NewHTTPFileStore(path).Load()
+}
+
+// resolveVariables возвращает имя и переменные указанного или активного окружения
+func resolveVariables(envName string) (string, map[string]string, error) {
+	set, err := models.LoadEnvironments()
+	if err != nil {
+		return "", nil, fmt.Errorf("не удалось загрузить окружения: %w", err)
+	}
+	if envName != "" {
+		if set.Find(envName) == nil {
+			return "", nil, fmt.Errorf("окружение %q не найдено", envName)
+		}
+		set.Active = envName
+	}
+	return set.Active, set.ActiveVariables(), nil
+}
+
+// storeExtracted записывает извлеченные из ответа значения в указанное
+// или активное окружение
+func storeExtracted(envName string, vars []models.Variable) error {
+	if len(vars) == 0 {
+		return nil
+	}
+	if envName == "" {
+		return fmt.Errorf("окружение не выбрано, переменные из ответа не сохранены (укажите -e)")
+	}
+	set, err := models.LoadEnvironments()
+	if err != nil {
+		return fmt.Errorf("не удалось загрузить окружения: %w", err)
+	}
+	set.MergeVariables(envName, vars)
+	if err := models.SaveEnvironments(set); err != nil {
+		return fmt.Errorf("не удалось сохранить переменные окружения: %w", err)
+	}
+	return nil
+}
+
+// envelope представляет ответ в формате вывода json
+type envelope struct {
+	Status      string                    `json:"status"`
+	StatusCode  int                       `json:"statusCode"`
+	Proto       string                    `json:"proto"`
+	URL         string                    `json:"url"`
+	Time        string                    `json:"time"`
+	Size        int64                     `json:"size"`
+	DecodedSize int64                     `json:"decodedSize"`
+	Headers     []models.Header           `json:"headers"`
+	Cookies     []models.Cookie           `json:"cookies,omitempty"`
+	Redirects   []models.RedirectHop      `json:"redirects,omitempty"`
+	Timings     models.Timings            `json:"timings"`
+	Settings    models.ClientSettings     `json:"settings"`
+	Body        string                    `json:"body"`
+	Encoding    string                    `json:"bodyEncoding,omitempty"` // base64 для двоичного тела
+	Truncated   bool                      `json:"truncated,omitempty"`    // тело больше лимита max_body
+	Assertions  []models.AssertionResult  `json:"assertions,omitempty"`
+	Extracted   []models.ExtractionResult `json:"extracted,omitempty"`
+}
+
+func newEnvelope(response models.ResponseData) envelope {
+	body, encoding := response.Body, ""
+	if response.Binary {
+		body, encoding = base64.StdEncoding.EncodeToString([]byte(body)), "base64"
+	}
+	return envelope{
+		Status:      response.Status,
+		StatusCode:  response.StatusCode,
+		Proto:       response.Proto,
+		URL:         response.URL,
+		Time:        response.Time,
+		Size:        response.Size,
+		DecodedSize: response.DecodedSize,
+		Headers:     response.Headers,
+		Cookies:     response.Cookies,
+		Redirects:   response.Redirects,
+		Timings:     response.Timings,
+		Settings:    response.Settings,
+		Body:        body,
+		Encoding:    encoding,
+		Truncated:   response.BodyFile != "",
+		Assertions:  response.Assertions,
+		Extracted:   response.Extracted,
+	}
+}
+
+func writeJSON(w io.Writer, v interface{}) {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// --- Разбор аргументов ---
+
+// parseFlags разбирает флаги, допуская их расположение после позиционных аргументов
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// headerFlag собирает повторяющиеся флаги -H
+type headerFlag []models.Header
+
+func (f *headerFlag) String() string {
+	return fmt.Sprint(*f)
+}
+
+func (f *headerFlag) Set(value string) error {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("неверный заголовок %q, ожидается \"Key: Value\"", value)
+	}
+	*f = append(*f, models.Header{Key: strings.TrimSpace(parts[0]), Value: strings.TrimSpace(parts[1])})
+	return nil
+}
+
+// flagSet сообщает, был ли флаг указан в командной строке
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// formFlag собирает поля multipart в форме curl -F: name=value или name=@path;type=content/type
+type formFlag []models.FormField
+
+func (f *formFlag) String() string {
+	return models.FormatFormFields(*f)
+}
+
+func (f *formFlag) Set(value string) error {
+	fields := models.ParseFormFields(value, true)
+	if len(fields) == 0 || fields[0].Key == "" {
+		return fmt.Errorf("неверное поле %q, ожидается \"name=value\" или \"name=@path\"", value)
+	}
+	*f = append(*f, fields[0])
+	return nil
+}
+
+// assertFlag собирает повторяющиеся флаги --assert
+type assertFlag []models.Assertion
+
+func (f *assertFlag) String() string {
+	return models.FormatAssertions(*f)
+}
+
+func (f *assertFlag) Set(value string) error {
+	a, err := models.ParseAssertion(value)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, a)
+	return nil
+}
+
+// extractFlag собирает повторяющиеся флаги --extract
+type extractFlag []models.Extraction
+
+func (f *extractFlag) String() string {
+	return models.FormatExtractions(*f)
+}
+
+func (f *extractFlag) Set(value string) error {
+	e, err := models.ParseExtraction(value)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, e)
+	return nil
+}
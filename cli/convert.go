@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/KharpukhaevV/postui/converter"
+	"github.com/KharpukhaevV/postui/httpclient"
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// runImport импортирует запросы из внешнего формата в requests.json
+func runImport(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintf(stderr, "Ошибка: укажите формат импорта\n\n%s", usage)
+		return ExitUsage
+	}
+
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	name := fs.String("name", "", "имя сохраненного запроса")
+	envName := fs.String("env", "", "окружение для импортированных переменных")
+	positional, err := parseFlags(fs, args[1:])
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		fmt.Fprintf(stderr, "Ошибка: укажите источник импорта\n\n%s", usage)
+		return ExitUsage
+	}
+
+	var result converter.ImportResult
+	switch args[0] {
+	case "curl":
+		sr, err := converter.ParseCurl(positional[0])
+		if err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitError
+		}
+		sr.Name = *name
+		if sr.Name == "" {
+			sr.Name = converter.DefaultRequestName(sr)
+		}
+		result.Requests = append(result.Requests, sr)
+	case "postman":
+		data, err := os.ReadFile(positional[0])
+		if err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitError
+		}
+		if result, err = converter.ImportPostman(data); err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitError
+		}
+	case "openapi":
+		data, err := os.ReadFile(positional[0])
+		if err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitError
+		}
+		if result, err = converter.ImportOpenAPI(data); err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitError
+		}
+	case "har":
+		data, err := os.ReadFile(positional[0])
+		if err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitError
+		}
+		if result, err = converter.ImportHAR(data); err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitError
+		}
+	default:
+		fmt.Fprintf(stderr, "Ошибка: неизвестный формат импорта %q\n", args[0])
+		return ExitUsage
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(stderr, "Предупреждение: %s\n", warning)
+	}
+
+	// Файлы записываются только после того, как оба удалось загрузить
+	store := models.NewJSONStore()
+	collection, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(stderr, "Ошибка: не удалось загрузить запросы: %v\n", err)
+		return ExitError
+	}
+	var environments models.EnvironmentSet
+	target := *envName
+	if target == "" {
+		target = result.Name
+	}
+	if len(result.Variables) > 0 {
+		if environments, err = models.LoadEnvironments(); err != nil {
+			fmt.Fprintf(stderr, "Ошибка: не удалось загрузить окружения: %v\n", err)
+			return ExitError
+		}
+		environments.MergeVariables(target, result.Variables)
+	}
+
+	// Одиночный запрос curl сохраняется в корень, коллекции - в отдельную папку
+	imported := models.Collection{}
+	if args[0] == "curl" {
+		collection.Requests = append(collection.Requests, result.Requests...)
+		imported.Requests = result.Requests
+	} else {
+		folder := result.Folder()
+		collection.Folders = append(collection.Folders, folder)
+		imported.Folders = []*models.Folder{folder}
+	}
+	if err := store.Save(collection); err != nil {
+		fmt.Fprintf(stderr, "Ошибка: не удалось сохранить запросы: %v\n", err)
+		return ExitError
+	}
+	if len(result.Variables) > 0 {
+		if err := models.SaveEnvironments(environments); err != nil {
+			fmt.Fprintf(stderr, "Ошибка: не удалось сохранить окружение: %v\n", err)
+			return ExitError
+		}
+		fmt.Fprintf(stdout, "Переменные сохранены в окружение %q\n", target)
+	}
+	for _, sr := range imported.Resolved() {
+		fmt.Fprintf(stdout, "Импортирован: %s\t%s\n", sr.Name, sr.Description())
+	}
+	return ExitOK
+}
+
+// runExport выводит сохраненный запрос во внешнем формате
+func runExport(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintf(stderr, "Ошибка: укажите формат экспорта\n\n%s", usage)
+		return ExitUsage
+	}
+
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	envName := fs.String("e", "", "окружение")
+	output := fs.String("o", "", "файл для записи результата (по умолчанию stdout)")
+	collectionName := fs.String("name", "postui", "имя экспортируемой коллекции")
+	limit := fs.Int("n", 0, "количество последних записей истории (0 - все)")
+	positional, err := parseFlags(fs, args[1:])
+	if err != nil {
+		return ExitUsage
+	}
+
+	switch args[0] {
+	case "curl":
+		if len(positional) != 1 {
+			fmt.Fprintf(stderr, "Ошибка: укажите имя сохраненного запроса\n\n%s", usage)
+			return ExitUsage
+		}
+		sr, err := findSavedRequest(positional[0])
+		if err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitError
+		}
+		_, vars, err := resolveVariables(*envName)
+		if err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitError
+		}
+		req := httpclient.NewHTTPRequestFromSaved(sr, vars)
+		fmt.Fprintln(stdout, converter.ToCurl(&req))
+	case "postman":
+		collection, err := models.NewJSONStore().Load()
+		if err != nil {
+			fmt.Fprintf(stderr, "Ошибка: не удалось загрузить запросы: %v\n", err)
+			return ExitError
+		}
+		var vars []models.Variable
+		if *envName != "" {
+			if vars, err = environmentVariables(*envName); err != nil {
+				fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+				return ExitError
+			}
+		}
+		collection.Name = *collectionName
+		data, err := converter.ExportPostman(collection, vars)
+		if err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitError
+		}
+		return writeOutput(*output, data, stdout, stderr)
+	case "har":
+		entries, err := models.LoadHistory()
+		if err != nil {
+			fmt.Fprintf(stderr, "Ошибка: не удалось загрузить историю: %v\n", err)
+			return ExitError
+		}
+		if *limit > 0 && len(entries) > *limit {
+			entries = entries[:*limit]
+		}
+		data, err := converter.ExportHAR(entries)
+		if err != nil {
+			fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+			return ExitError
+		}
+		return writeOutput(*output, data, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "Ошибка: неизвестный формат экспорта %q\n", args[0])
+		return ExitUsage
+	}
+	return ExitOK
+}
+
+// writeOutput записывает данные в файл или stdout
+func writeOutput(path string, data []byte, stdout, stderr io.Writer) int {
+	if path == "" {
+		fmt.Fprintln(stdout, string(data))
+		return ExitOK
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		fmt.Fprintf(stderr, "Ошибка: %v\n", err)
+		return ExitError
+	}
+	return ExitOK
+}
+
+// environmentVariables возвращает переменные окружения с указанным именем
+func environmentVariables(name string) ([]models.Variable, error) {
+	set, err := models.LoadEnvironments()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось загрузить окружения: %w", err)
+	}
+	env := set.Find(name)
+	if env == nil {
+		return nil, fmt.Errorf("окружение %q не найдено", name)
+	}
+	return env.Variables, nil
+}
+
+// findSavedRequest ищет сохраненный запрос по пути "Папка/Запрос" или по имени
+func findSavedRequest(name string) (models.SavedRequest, error) {
+	collection, err := models.NewJSONStore().Load()
+	if err != nil {
+		return models.SavedRequest{}, fmt.Errorf("не удалось загрузить запросы: %w", err)
+	}
+	return collection.FindRequest(name)
+}
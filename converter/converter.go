@@ -0,0 +1,80 @@
+// Package converter преобразует запросы между postui и внешними форматами
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// ImportResult содержит результат импорта коллекции из внешнего формата
+type ImportResult struct {
+	Name      string
+	Requests  []models.SavedRequest
+	Folders   []*models.Folder
+	Variables []models.Variable
+	// Warnings перечисляет данные, которые не удалось представить в postui
+	Warnings []string
+}
+
+// Folder возвращает импортированные запросы и папки как одну папку с именем коллекции
+func (r ImportResult) Folder() *models.Folder {
+	name := r.Name
+	if name == "" {
+		name = "Импорт"
+	}
+	return &models.Folder{Name: name, Folders: r.Folders, Requests: r.Requests}
+}
+
+// ImportFile импортирует коллекцию из файла, определяя формат по расширению и содержимому.
+// Поддерживаются HAR, коллекции Postman, спецификации OpenAPI/Swagger и файлы .http.
+func ImportFile(path string) (ImportResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	switch {
+	case ext == ".har":
+		return ImportHAR(data)
+	case IsHTTPFile(path):
+		collection, err := ParseHTTPFile(data)
+		return ImportResult{
+			Name:      filepath.Base(path),
+			Requests:  collection.Requests,
+			Folders:   collection.Folders,
+			Variables: collection.Variables,
+		}, err
+	case ext == ".yaml" || ext == ".yml":
+		return ImportOpenAPI(data)
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return ImportResult{}, fmt.Errorf("не удалось определить формат файла %s", path)
+	}
+	switch {
+	case probe["log"] != nil:
+		return ImportHAR(data)
+	case probe["info"] != nil && probe["item"] != nil:
+		return ImportPostman(data)
+	case probe["openapi"] != nil || probe["swagger"] != nil:
+		return ImportOpenAPI(data)
+	}
+	return ImportResult{}, fmt.Errorf("не удалось определить формат файла %s", path)
+}
+
+// DefaultRequestName формирует имя запроса из метода и адреса
+func DefaultRequestName(sr models.SavedRequest) string {
+	path := sr.URL
+	if parsed, err := url.Parse(sr.URL); err == nil && parsed.Host != "" {
+		path = parsed.Host + parsed.Path
+	}
+	return strings.TrimSpace(models.MethodNames[sr.Method] + " " + path)
+}
@@ -0,0 +1,593 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/KharpukhaevV/postui/httpclient"
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// curlFlagsWithValue содержит флаги curl, принимающие аргумент, но не влияющие на запрос
+var curlFlagsWithValue = map[string]bool{
+	"-o": true, "--output": true, "-D": true, "--dump-header": true,
+	"-w": true, "--write-out": true, "--stderr": true, "--trace": true, "--trace-ascii": true,
+	"-c": true, "--cookie-jar": true, "-T": true, "--upload-file": true, "-K": true, "--config": true,
+	"--connect-timeout": true, "--retry": true, "--retry-delay": true, "--retry-max-time": true,
+	"--limit-rate": true, "--max-filesize": true, "-y": true, "--speed-time": true, "-Y": true, "--speed-limit": true,
+	"--keepalive-time": true, "--expect100-timeout": true, "--proto": true, "--proto-redir": true,
+	"--resolve": true, "--connect-to": true, "--interface": true, "--dns-servers": true,
+	"-r": true, "--range": true,
+}
+
+// curlIgnoredFlags содержит флаги curl без аргумента, не влияющие на запрос
+var curlIgnoredFlags = map[string]bool{
+	"-s": true, "--silent": true, "-S": true, "--show-error": true, "--no-progress-meter": true,
+	"-v": true, "--verbose": true, "-i": true, "--include": true, "-#": true, "--progress-bar": true,
+	"-f": true, "--fail": true, "--fail-with-body": true, "--fail-early": true,
+	"-O": true, "--remote-name": true, "-J": true, "--remote-header-name": true, "--create-dirs": true,
+	"-N": true, "--no-buffer": true, "--compressed": true, "--raw": true, "--tr-encoding": true,
+	"-g": true, "--globoff": true, "--path-as-is": true, "-q": true, "--disable": true,
+	"-4": true, "--ipv4": true, "-6": true, "--ipv6": true, "--no-keepalive": true, "--tcp-nodelay": true,
+	"-0": true, "--http1.0": true, "--http1.1": true, "--http2": true, "--http2-prior-knowledge": true,
+	"--retry-connrefused": true, "--retry-all-errors": true, "--basic": true,
+}
+
+// curlSwitches содержит однобуквенные флаги без аргумента, которые обрабатывает ParseCurl
+var curlSwitches = map[string]bool{"-k": true, "-L": true, "-G": true, "-I": true}
+
+// IsCurlCommand сообщает, похожа ли строка на команду curl
+func IsCurlCommand(input string) bool {
+	input = strings.TrimSpace(input)
+	return input == "curl" || strings.HasPrefix(input, "curl ")
+}
+
+// ParseCurl преобразует командную строку curl в запрос
+func ParseCurl(command string) (models.SavedRequest, error) {
+	args, err := splitShellWords(command)
+	if err != nil {
+		return models.SavedRequest{}, err
+	}
+	if len(args) == 0 || args[0] != "curl" {
+		return models.SavedRequest{}, errors.New("команда должна начинаться с curl")
+	}
+
+	var (
+		method    string
+		rawURL    string
+		data      []string
+		useGet    bool
+		headers   []models.Header
+		hasCType  bool
+		hasAccept bool
+		auth      models.Auth
+		digest    bool
+		settings  models.ClientSettings
+		form      []models.FormField
+	)
+
+	addHeader := func(key, value string) {
+		if strings.EqualFold(key, "Content-Type") {
+			hasCType = true
+		}
+		if strings.EqualFold(key, "Accept") {
+			hasAccept = true
+		}
+		headers = append(headers, models.Header{Key: key, Value: value})
+	}
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := splitCurlFlag(arg)
+
+		// next возвращает значение флага: из той же позиции (-XPOST, --data=...) или следующего аргумента
+		next := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("флаг %s требует значение", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "-X", "--request":
+			if method, err = next(); err != nil {
+				return models.SavedRequest{}, err
+			}
+		case "-H", "--header":
+			header, err := next()
+			if err != nil {
+				return models.SavedRequest{}, err
+			}
+			parts := strings.SplitN(header, ":", 2)
+			if len(parts) == 2 {
+				addHeader(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+			}
+		case "-d", "--data", "--data-ascii", "--data-binary", "--data-raw", "--data-urlencode", "--json":
+			v, err := next()
+			if err != nil {
+				return models.SavedRequest{}, err
+			}
+			switch {
+			case name == "--data-urlencode":
+				v = encodeCurlDataURLEncode(v)
+			case name != "--data-raw" && strings.HasPrefix(v, "@"):
+				content, err := os.ReadFile(v[1:])
+				if err != nil {
+					return models.SavedRequest{}, fmt.Errorf("не удалось прочитать %s: %w", v[1:], err)
+				}
+				v = string(content)
+				if name != "--data-binary" {
+					v = strings.NewReplacer("\r", "", "\n", "").Replace(v)
+				}
+			}
+			if name == "--json" {
+				if !hasCType {
+					addHeader("Content-Type", "application/json")
+				}
+				if !hasAccept {
+					addHeader("Accept", "application/json")
+				}
+			}
+			data = append(data, v)
+		case "-F", "--form", "--form-string":
+			v, err := next()
+			if err != nil {
+				return models.SavedRequest{}, err
+			}
+			fields := models.ParseFormFields(v, name != "--form-string")
+			if len(fields) == 0 {
+				return models.SavedRequest{}, fmt.Errorf("флаг %s: ожидается name=value", name)
+			}
+			form = append(form, fields[0])
+		case "-u", "--user":
+			credentials, err := next()
+			if err != nil {
+				return models.SavedRequest{}, err
+			}
+			username, password, _ := strings.Cut(credentials, ":")
+			auth = models.Auth{Type: models.AuthBasic, Username: username, Password: password}
+		case "--digest":
+			digest = true
+		case "--oauth2-bearer":
+			token, err := next()
+			if err != nil {
+				return models.SavedRequest{}, err
+			}
+			auth = models.Auth{Type: models.AuthBearer, Token: token}
+		case "-A", "--user-agent":
+			v, err := next()
+			if err != nil {
+				return models.SavedRequest{}, err
+			}
+			addHeader("User-Agent", v)
+		case "-e", "--referer":
+			v, err := next()
+			if err != nil {
+				return models.SavedRequest{}, err
+			}
+			addHeader("Referer", v)
+		case "-b", "--cookie":
+			v, err := next()
+			if err != nil {
+				return models.SavedRequest{}, err
+			}
+			addHeader("Cookie", v)
+		case "-k", "--insecure":
+			insecure := true
+			settings.Insecure = &insecure
+		case "-L", "--location", "--location-trusted":
+			follow := true
+			settings.FollowRedirects = &follow
+		case "-m", "--max-time":
+			v, err := next()
+			if err != nil {
+				return models.SavedRequest{}, err
+			}
+			settings.Timeout = v + "s"
+		case "--max-redirs":
+			v, err := next()
+			if err != nil {
+				return models.SavedRequest{}, err
+			}
+			// -1 в curl - без ограничения, такое значение не переносится
+			if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+				settings.MaxRedirects = &n
+			}
+		case "-x", "--proxy":
+			if settings.Proxy, err = next(); err != nil {
+				return models.SavedRequest{}, err
+			}
+		case "--cacert":
+			if settings.CACert, err = next(); err != nil {
+				return models.SavedRequest{}, err
+			}
+		case "-E", "--cert":
+			if settings.ClientCert, err = next(); err != nil {
+				return models.SavedRequest{}, err
+			}
+		case "--key":
+			if settings.ClientKey, err = next(); err != nil {
+				return models.SavedRequest{}, err
+			}
+		case "--tlsv1.0", "--tlsv1.1", "--tlsv1.2", "--tlsv1.3":
+			settings.MinTLSVersion = strings.TrimPrefix(name, "--tlsv")
+		case "-G", "--get":
+			useGet = true
+		case "-I", "--head":
+			method = "HEAD"
+		case "--url":
+			if rawURL, err = next(); err != nil {
+				return models.SavedRequest{}, err
+			}
+		default:
+			switch {
+			case curlFlagsWithValue[name]:
+				if _, err := next(); err != nil {
+					return models.SavedRequest{}, err
+				}
+			case curlIgnoredFlags[name]:
+			case isCurlSwitchGroup(arg):
+				// -sSL разворачивается в -s -S -L
+				group := make([]string, 0, len(arg)-1)
+				for _, c := range arg[1:] {
+					group = append(group, "-"+string(c))
+				}
+				args = slices.Concat(args[:i], group, args[i+1:])
+				i--
+			case strings.HasPrefix(arg, "-") && arg != "-":
+				// Неизвестный флаг может принимать аргумент, который иначе был бы принят за URL
+				return models.SavedRequest{}, fmt.Errorf("флаг %s не поддерживается", name)
+			default:
+				rawURL = arg
+			}
+		}
+	}
+
+	if rawURL == "" {
+		return models.SavedRequest{}, errors.New("в команде curl не найден URL")
+	}
+
+	if digest && auth.Type == models.AuthBasic {
+		auth.Type = models.AuthDigest
+	}
+	sr := models.SavedRequest{Headers: headers, Auth: auth, Settings: settings}
+	sr.URL, sr.Params = splitQuery(rawURL)
+
+	body := strings.Join(data, "&")
+	switch {
+	case len(form) > 0:
+		// curl формирует Content-Type с границей частей сам, как и postui
+		sr.BodyType = models.BodyMultipart
+		sr.Form = form
+		sr.Headers = slices.DeleteFunc(sr.Headers, func(h models.Header) bool {
+			return strings.EqualFold(h.Key, "Content-Type") && strings.HasPrefix(strings.ToLower(h.Value), "multipart/form-data")
+		})
+	case useGet && body != "":
+		_, params := splitQuery("?" + body)
+		sr.Params = append(sr.Params, params...)
+	case body != "":
+		sr.Body = body
+		if !hasCType {
+			sr.Headers = append(sr.Headers, models.Header{Key: "Content-Type", Value: "application/x-www-form-urlencoded"})
+		}
+	}
+
+	switch {
+	case method != "":
+		var ok bool
+		if sr.Method, ok = models.LookupMethod(method); !ok {
+			return models.SavedRequest{}, fmt.Errorf("метод %s не поддерживается (доступны: %s)",
+				method, strings.Join(models.MethodNames, ", "))
+		}
+	case len(form) > 0 || body != "" && !useGet:
+		sr.Method = models.MethodPOST
+	default:
+		sr.Method = models.MethodGET
+	}
+
+	if sr.Headers == nil {
+		sr.Headers = []models.Header{}
+	}
+	if sr.Params == nil {
+		sr.Params = []models.Param{}
+	}
+	return sr, nil
+}
+
+// ToCurl преобразует запрос в командную строку curl с экранированием для shell
+func ToCurl(req *httpclient.HTTPRequest) string {
+	fullURL, err := req.BuildURL()
+	if err != nil {
+		fullURL = req.URL
+	}
+
+	// Метод и URL на первой строке, заголовки и тело - на строках продолжения.
+	// С телом curl по умолчанию отправляет POST, поэтому остальные методы задаются явно.
+	hasBody := req.BodyType == models.BodyMultipart || req.BodyType == models.BodyBinary || len(req.Body) > 0
+	head := []string{"curl"}
+	switch {
+	case req.Method == "POST" && hasBody:
+	case req.Method == "GET" && !hasBody:
+	case req.Method == "HEAD" && !hasBody:
+		head = append(head, "--head")
+	default:
+		head = append(head, "-X", req.Method)
+	}
+	head = append(head, shellQuote(fullURL))
+
+	lines := []string{strings.Join(head, " ")}
+	lines = append(lines, curlSettingsFlags(req.Settings)...)
+	if req.Auth.Type == models.AuthDigest {
+		// Digest требует ответа на вызов сервера, поэтому передается через curl
+		lines = append(lines, "--digest -u "+shellQuote(req.Auth.Username+":"+req.Auth.Password))
+	}
+	for _, h := range req.Headers {
+		if req.BodyType == models.BodyMultipart && strings.EqualFold(h.Key, "Content-Type") {
+			// Для -F curl задает Content-Type с границей частей
+			continue
+		}
+		lines = append(lines, "-H "+shellQuote(h.Key+": "+h.Value))
+	}
+	switch {
+	case req.BodyType == models.BodyMultipart:
+		for _, f := range req.Form {
+			flag := "-F "
+			if !f.File && (strings.HasPrefix(f.Value, "@") || strings.HasPrefix(f.Value, "<")) {
+				// Иначе curl прочитает значение из файла
+				flag = "--form-string "
+			}
+			lines = append(lines, flag+shellQuote(models.FormatFormFields([]models.FormField{f})))
+		}
+	case req.BodyType == models.BodyBinary:
+		lines = append(lines, "--data-binary "+shellQuote("@"+req.BodyFile))
+	case len(req.Body) > 0:
+		lines = append(lines, "--data-raw "+shellQuote(string(req.Body)))
+	}
+	return strings.Join(lines, " \\\n  ")
+}
+
+// curlSettingsFlags возвращает флаги curl для заданных настроек клиента
+func curlSettingsFlags(settings models.ClientSettings) []string {
+	var flags []string
+	if timeout, err := settings.TimeoutDuration(); err == nil && timeout > 0 {
+		flags = append(flags, fmt.Sprintf("--max-time %g", timeout.Seconds()))
+	}
+	if settings.FollowRedirects != nil && *settings.FollowRedirects {
+		flags = append(flags, "-L")
+	}
+	if settings.MaxRedirects != nil {
+		flags = append(flags, fmt.Sprintf("--max-redirs %d", *settings.MaxRedirects))
+	}
+	if settings.Insecure != nil && *settings.Insecure {
+		flags = append(flags, "-k")
+	}
+	for _, f := range []struct{ flag, value string }{
+		{"--cacert", settings.CACert},
+		{"--cert", settings.ClientCert},
+		{"--key", settings.ClientKey},
+		{"--proxy", settings.Proxy},
+	} {
+		if f.value != "" {
+			flags = append(flags, f.flag+" "+shellQuote(f.value))
+		}
+	}
+	if settings.MinTLSVersion != "" {
+		flags = append(flags, "--tlsv"+settings.MinTLSVersion)
+	}
+	return flags
+}
+
+// --- Вспомогательные функции ---
+
+// splitCurlFlag разделяет флаг и значение в формах --data=value и -XPOST
+func splitCurlFlag(arg string) (name, value string, hasValue bool) {
+	if strings.HasPrefix(arg, "--") {
+		if idx := strings.Index(arg, "="); idx > 0 {
+			return arg[:idx], arg[idx+1:], true
+		}
+		return arg, "", false
+	}
+	if strings.HasPrefix(arg, "-") && len(arg) > 2 {
+		short := arg[:2]
+		switch short {
+		case "-X", "-H", "-d", "-u", "-A", "-e", "-b", "-F", "-m", "-x", "-E":
+			return short, arg[2:], true
+		}
+		if curlFlagsWithValue[short] {
+			return short, arg[2:], true
+		}
+	}
+	return arg, "", false
+}
+
+// isCurlSwitchGroup сообщает, является ли аргумент группой однобуквенных флагов без аргумента (-sSL)
+func isCurlSwitchGroup(arg string) bool {
+	if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+		return false
+	}
+	for _, c := range arg[1:] {
+		flag := "-" + string(c)
+		if !curlIgnoredFlags[flag] && !curlSwitches[flag] {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeCurlDataURLEncode реализует семантику --data-urlencode (name=content)
+func encodeCurlDataURLEncode(v string) string {
+	if idx := strings.Index(v, "="); idx >= 0 {
+		return v[:idx+1] + url.QueryEscape(v[idx+1:])
+	}
+	return url.QueryEscape(v)
+}
+
+// splitQuery отделяет строку запроса от URL, сохраняя порядок параметров
+func splitQuery(rawURL string) (string, []models.Param) {
+	idx := strings.Index(rawURL, "?")
+	if idx < 0 {
+		return rawURL, nil
+	}
+	base, rawQuery := rawURL[:idx], rawURL[idx+1:]
+	if hash := strings.Index(rawQuery, "#"); hash >= 0 {
+		rawQuery = rawQuery[:hash]
+	}
+
+	var params []models.Param
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		key, err := url.QueryUnescape(kv[0])
+		if err != nil {
+			key = kv[0]
+		}
+		var value string
+		if len(kv) == 2 {
+			if value, err = url.QueryUnescape(kv[1]); err != nil {
+				value = kv[1]
+			}
+		}
+		params = append(params, models.Param{Key: key, Value: value})
+	}
+	return base, params
+}
+
+// shellQuote заключает строку в одинарные кавычки для POSIX shell
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:@%+=,", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// splitShellWords разбивает командную строку на аргументы по правилам POSIX shell.
+// Поддерживаются одинарные и двойные кавычки, $'...' и перенос строки через обратный слеш.
+func splitShellWords(input string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+	)
+	runes := []rune(input)
+
+	flush := func() {
+		if inWord {
+			words = append(words, current.String())
+			current.Reset()
+			inWord = false
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\':
+			// Обратный слеш перед переводом строки продолжает команду, перед остальными
+			// символами (в том числе пробелом) экранирует их
+			switch {
+			case i+1 >= len(runes):
+				continue
+			case runes[i+1] == '\n':
+				flush()
+				i++
+				continue
+			case runes[i+1] == '\r' && i+2 < len(runes) && runes[i+2] == '\n':
+				flush()
+				i += 2
+				continue
+			}
+			i++
+			current.WriteRune(runes[i])
+			inWord = true
+		case r == '\'':
+			end := indexRune(runes, '\'', i+1)
+			if end < 0 {
+				return nil, errors.New("незакрытая одинарная кавычка")
+			}
+			current.WriteString(string(runes[i+1 : end]))
+			i = end
+			inWord = true
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			end, value, err := readANSIQuoted(runes, i+2)
+			if err != nil {
+				return nil, err
+			}
+			current.WriteString(value)
+			i = end
+			inWord = true
+		case r == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						continue
+					}
+				}
+				current.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, errors.New("незакрытая двойная кавычка")
+			}
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	flush()
+	return words, nil
+}
+
+// readANSIQuoted читает строку в кавычках $'...' начиная с позиции start
+func readANSIQuoted(runes []rune, start int) (int, string, error) {
+	var sb strings.Builder
+	for i := start; i < len(runes); i++ {
+		switch runes[i] {
+		case '\'':
+			return i, sb.String(), nil
+		case '\\':
+			if i+1 >= len(runes) {
+				break
+			}
+			i++
+			switch runes[i] {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			default:
+				sb.WriteRune(runes[i])
+			}
+		default:
+			sb.WriteRune(runes[i])
+		}
+	}
+	return 0, "", errors.New("незакрытая кавычка $'")
+}
+
+func indexRune(runes []rune, r rune, from int) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
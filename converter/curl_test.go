@@ -0,0 +1,334 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KharpukhaevV/postui/httpclient"
+	"github.com/KharpukhaevV/postui/models"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  []string
+	}{
+		{`curl  https://x`, []string{"curl", "https://x"}},
+		{`'a b' "c d" e\ f`, []string{"a b", "c d", "e f"}},
+		{`ab"c d"'e f'g`, []string{"abc de fg"}},
+		{`'it'\''s'`, []string{"it's"}},
+		{`"q\"uote \$HOME \\ \n"`, []string{`q"uote $HOME \ \n`}},
+		{`'$HOME \n'`, []string{`$HOME \n`}},
+		{`$'a\tb\nc\'d'`, []string{"a\tb\nc'd"}},
+		{`'' ""`, []string{"", ""}},
+		// Перенос строки через обратный слеш разделяет аргументы, в том числе с CRLF
+		{"curl \\\n  -H x\\\r\n-d y", []string{"curl", "-H", "x", "-d", "y"}},
+		{"\"a\\\nb\"", []string{"ab"}},
+		// Экранированный пробел - часть аргумента, как в shell
+		{`-H \ x`, []string{"-H", " x"}},
+	} {
+		got, err := splitShellWords(tc.input)
+		if err != nil {
+			t.Errorf("%q: %v", tc.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%q: аргументы %q, ожидалось %q", tc.input, got, tc.want)
+		}
+	}
+
+	for _, input := range []string{`'abc`, `"abc`, `$'abc`} {
+		if _, err := splitShellWords(input); err == nil {
+			t.Errorf("%q: ожидалась ошибка незакрытой кавычки", input)
+		}
+	}
+}
+
+func TestParseCurl(t *testing.T) {
+	dir := t.TempDir()
+	dataFile := filepath.Join(dir, "data.txt")
+	if err := os.WriteFile(dataFile, []byte("a=1\nb=2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	form := models.BodyMultipart
+	urlencoded := []models.Header{{Key: "Content-Type", Value: "application/x-www-form-urlencoded"}}
+
+	for _, tc := range []struct {
+		name     string
+		command  string
+		method   models.HTTPMethod
+		url      string
+		params   []models.Param
+		headers  []models.Header
+		body     string
+		bodyType *models.BodyType
+		form     []models.FormField
+		auth     models.Auth
+	}{
+		{
+			name:    "GET по умолчанию",
+			command: `curl 'https://api.example.com/items?a=1&b=x%20y'`,
+			method:  models.MethodGET,
+			url:     "https://api.example.com/items",
+			params:  []models.Param{{Key: "a", Value: "1"}, {Key: "b", Value: "x y"}},
+		},
+		{
+			name:    "тело без -X отправляется POST",
+			command: `curl https://x -d 'a=1' --data b=2`,
+			method:  models.MethodPOST,
+			url:     "https://x",
+			headers: urlencoded,
+			body:    "a=1&b=2",
+		},
+		{
+			name:    "-X с телом",
+			command: `curl -X PUT https://x -H 'Content-Type: application/json' --data-raw '{"a":"it'\''s"}'`,
+			method:  models.MethodPUT,
+			url:     "https://x",
+			headers: []models.Header{{Key: "Content-Type", Value: "application/json"}},
+			body:    `{"a":"it's"}`,
+		},
+		{
+			name:    "-X слитно со значением",
+			command: `curl -XDELETE https://x`,
+			method:  models.MethodDELETE,
+			url:     "https://x",
+		},
+		{
+			name:    "-G переносит данные в параметры",
+			command: `curl -G https://x?page=1 -d q=a%20b --data-urlencode 'name=bob & co'`,
+			method:  models.MethodGET,
+			url:     "https://x",
+			params:  []models.Param{{Key: "page", Value: "1"}, {Key: "q", Value: "a b"}, {Key: "name", Value: "bob & co"}},
+		},
+		{
+			name:    "--data-urlencode",
+			command: `curl https://x --data-urlencode 'q=a b&c' --data-urlencode plain`,
+			method:  models.MethodPOST,
+			url:     "https://x",
+			headers: urlencoded,
+			body:    "q=a+b%26c&plain",
+		},
+		{
+			name:    "-d @file без переводов строк",
+			command: `curl https://x -d @` + dataFile,
+			method:  models.MethodPOST,
+			url:     "https://x",
+			headers: urlencoded,
+			body:    "a=1b=2",
+		},
+		{
+			name:    "--data-binary @file как есть",
+			command: `curl https://x --data-binary @` + dataFile,
+			method:  models.MethodPOST,
+			url:     "https://x",
+			headers: urlencoded,
+			body:    "a=1\nb=2\n",
+		},
+		{
+			name:    "--json",
+			command: `curl https://x --json '{}'`,
+			method:  models.MethodPOST,
+			url:     "https://x",
+			headers: []models.Header{{Key: "Content-Type", Value: "application/json"}, {Key: "Accept", Value: "application/json"}},
+			body:    "{}",
+		},
+		{
+			name:    "-u",
+			command: `curl -u alice:s3:cret https://x`,
+			method:  models.MethodGET,
+			url:     "https://x",
+			auth:    models.Auth{Type: models.AuthBasic, Username: "alice", Password: "s3:cret"},
+		},
+		{
+			name:    "-u с --digest",
+			command: `curl --digest -u alice:secret https://x`,
+			method:  models.MethodGET,
+			url:     "https://x",
+			auth:    models.Auth{Type: models.AuthDigest, Username: "alice", Password: "secret"},
+		},
+		{
+			name:    "--oauth2-bearer",
+			command: `curl https://x --oauth2-bearer t0ken`,
+			method:  models.MethodGET,
+			url:     "https://x",
+			auth:    models.Auth{Type: models.AuthBearer, Token: "t0ken"},
+		},
+		{
+			name: "-F с @file",
+			command: `curl https://x -H 'Content-Type: multipart/form-data; boundary=x' -F name=bob ` +
+				`-F 'avatar=@/tmp/a b.png;type=image/png' --form-string 'note=@not a file'`,
+			method:   models.MethodPOST,
+			url:      "https://x",
+			bodyType: &form,
+			form: []models.FormField{
+				{Key: "name", Value: "bob"},
+				{Key: "avatar", Value: "/tmp/a b.png", File: true, ContentType: "image/png"},
+				{Key: "note", Value: "@not a file"},
+			},
+		},
+		{
+			name:    "заголовки и пропускаемые флаги",
+			command: `curl -sSL -o /dev/null --retry-delay 2 -A agent -e https://ref -b 'a=1' -H 'X-Id:  7' https://x --compressed`,
+			method:  models.MethodGET,
+			url:     "https://x",
+			headers: []models.Header{{Key: "User-Agent", Value: "agent"}, {Key: "Referer", Value: "https://ref"}, {Key: "Cookie", Value: "a=1"}, {Key: "X-Id", Value: "7"}},
+		},
+		{
+			name:    "--url и -I",
+			command: `curl -I --url https://x`,
+			method:  models.MethodHEAD,
+			url:     "https://x",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sr, err := ParseCurl(tc.command)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.params == nil {
+				tc.params = []models.Param{}
+			}
+			if tc.headers == nil {
+				tc.headers = []models.Header{}
+			}
+			if sr.Method != tc.method || sr.URL != tc.url {
+				t.Errorf("запрос = %s %s, ожидалось %s %s", models.MethodNames[sr.Method], sr.URL, models.MethodNames[tc.method], tc.url)
+			}
+			if !reflect.DeepEqual(sr.Params, tc.params) {
+				t.Errorf("параметры = %+v, ожидалось %+v", sr.Params, tc.params)
+			}
+			if !reflect.DeepEqual(sr.Headers, tc.headers) {
+				t.Errorf("заголовки = %+v, ожидалось %+v", sr.Headers, tc.headers)
+			}
+			if sr.Body != tc.body {
+				t.Errorf("тело = %q, ожидалось %q", sr.Body, tc.body)
+			}
+			if tc.bodyType != nil && sr.BodyType != *tc.bodyType {
+				t.Errorf("тип тела = %q, ожидался %q", sr.BodyType, *tc.bodyType)
+			}
+			if !reflect.DeepEqual(sr.Form, tc.form) {
+				t.Errorf("поля формы = %+v, ожидалось %+v", sr.Form, tc.form)
+			}
+			if sr.Auth != tc.auth {
+				t.Errorf("авторизация = %+v, ожидалась %+v", sr.Auth, tc.auth)
+			}
+		})
+	}
+}
+
+func TestParseCurlSettings(t *testing.T) {
+	sr, err := ParseCurl(`curl -kL --max-redirs 0 -m 2.5 -x http://proxy:3128 --cacert ca.pem -E cert.pem --key key.pem --tlsv1.2 https://x`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := sr.Settings
+	if s.Insecure == nil || !*s.Insecure || s.FollowRedirects == nil || !*s.FollowRedirects {
+		t.Errorf("insecure = %v, follow_redirects = %v", s.Insecure, s.FollowRedirects)
+	}
+	if s.MaxRedirects == nil || *s.MaxRedirects != 0 {
+		t.Errorf("max_redirects = %v, ожидалось 0", s.MaxRedirects)
+	}
+	if s.Timeout != "2.5s" || s.Proxy != "http://proxy:3128" || s.CACert != "ca.pem" ||
+		s.ClientCert != "cert.pem" || s.ClientKey != "key.pem" || s.MinTLSVersion != "1.2" {
+		t.Errorf("настройки = %+v", s)
+	}
+}
+
+func TestParseCurlErrors(t *testing.T) {
+	for command, want := range map[string]string{
+		`wget https://x`:                  "начинаться с curl",
+		`curl -s`:                         "не найден URL",
+		`curl https://x -H`:               "требует значение",
+		`curl -X TRACE https://x`:         "метод TRACE не поддерживается",
+		`curl https://x --unix-socket /s`: "флаг --unix-socket не поддерживается",
+		`curl -sz https://x`:              "флаг -sz не поддерживается",
+		`curl https://x -d @/nonexistent`: "не удалось прочитать",
+		`curl 'https://x`:                 "незакрытая",
+	} {
+		if _, err := ParseCurl(command); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: ошибка %v, ожидалась %q", command, err, want)
+		}
+	}
+}
+
+func TestToCurlMethod(t *testing.T) {
+	for _, tc := range []struct {
+		method models.HTTPMethod
+		body   string
+		want   string
+	}{
+		{models.MethodGET, "", "curl https://x"},
+		{models.MethodPOST, "a", "curl https://x"},
+		{models.MethodPOST, "", "curl -X POST https://x"},
+		{models.MethodPUT, "a", "curl -X PUT https://x"},
+		{models.MethodGET, "a", "curl -X GET https://x"},
+		{models.MethodDELETE, "", "curl -X DELETE https://x"},
+		{models.MethodHEAD, "", "curl --head https://x"},
+	} {
+		req := httpclient.NewHTTPRequestFromSaved(models.SavedRequest{Method: tc.method, URL: "https://x", Body: tc.body}, nil)
+		got := ToCurl(&req)
+		if first, _, _ := strings.Cut(got, " \\\n"); first != tc.want {
+			t.Errorf("%s с телом %q: %q, ожидалось %q", models.MethodNames[tc.method], tc.body, first, tc.want)
+		}
+	}
+}
+
+func TestCurlRoundTrip(t *testing.T) {
+	follow, insecure, redirects := true, true, 0
+	for _, sr := range []models.SavedRequest{
+		{
+			Method:  models.MethodPATCH,
+			URL:     "https://api.example.com/items/1",
+			Params:  []models.Param{{Key: "q", Value: "a b&c"}, {Key: "z", Value: "it's"}},
+			Headers: []models.Header{{Key: "Content-Type", Value: "application/json"}, {Key: "X-Note", Value: `"quoted" $HOME`}},
+			Body:    "{\"name\": \"O'Brien\",\n \"tab\": \"\t\"}",
+			Auth:    models.Auth{Type: models.AuthDigest, Username: "alice", Password: "p@ss word"},
+			Settings: models.ClientSettings{
+				Timeout:         "1.5s",
+				FollowRedirects: &follow,
+				MaxRedirects:    &redirects,
+				Insecure:        &insecure,
+				Proxy:           "http://proxy:3128",
+				CACert:          "/etc/ssl/my ca.pem",
+				MinTLSVersion:   "1.3",
+			},
+		},
+		{
+			Method:   models.MethodPOST,
+			URL:      "https://api.example.com/upload",
+			BodyType: models.BodyMultipart,
+			Form: []models.FormField{
+				{Key: "title", Value: "Отчет"},
+				{Key: "raw", Value: "@literal"},
+				{Key: "file", Value: "/tmp/report.pdf", File: true, ContentType: "application/pdf"},
+			},
+		},
+		{
+			Method:  models.MethodGET,
+			URL:     "https://api.example.com/search",
+			Headers: []models.Header{{Key: "Accept", Value: "*/*"}},
+		},
+	} {
+		req := httpclient.NewHTTPRequestFromSaved(sr, nil)
+		command := ToCurl(&req)
+		got, err := ParseCurl(command)
+		if err != nil {
+			t.Fatalf("%v:\n%s", err, command)
+		}
+
+		want := sr
+		if want.Headers == nil {
+			want.Headers = []models.Header{}
+		}
+		if want.Params == nil {
+			want.Params = []models.Param{}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("запрос после экспорта и импорта:\n%+v\nожидалось:\n%+v\nкоманда:\n%s", got, want, command)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/KharpukhaevV/postui/httpclient"
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// --- Структуры HAR 1.2 ---
+
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+	Comment     string         `json:"comment,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text"`
+	Params   []harNameValue `json:"params,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// --- Импорт ---
+
+// ImportHAR создает по одному запросу на каждую запись HAR
+func ImportHAR(data []byte) (ImportResult, error) {
+	var doc harDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return ImportResult{}, fmt.Errorf("неверный формат HAR: %w", err)
+	}
+
+	result := ImportResult{Name: "HAR"}
+	for i, entry := range doc.Log.Entries {
+		req := entry.Request
+		sr := models.SavedRequest{
+			Method:  models.ParseMethod(req.Method),
+			Headers: []models.Header{},
+			Params:  []models.Param{},
+		}
+		sr.URL, sr.Params = splitQuery(req.URL)
+		if sr.Params == nil {
+			sr.Params = []models.Param{}
+		}
+		sr.Name = fmt.Sprintf("%d. %s", i+1, DefaultRequestName(sr))
+		if !strings.EqualFold(models.MethodNames[sr.Method], req.Method) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: метод %s не поддерживается, используется GET", sr.Name, req.Method))
+		}
+
+		for _, h := range req.Headers {
+			// Псевдозаголовки HTTP/2 и вычисляемые заголовки не переносятся
+			if strings.HasPrefix(h.Name, ":") || strings.EqualFold(h.Name, "Content-Length") || strings.EqualFold(h.Name, "Host") {
+				continue
+			}
+			sr.Headers = append(sr.Headers, models.Header{Key: h.Name, Value: h.Value})
+		}
+
+		if req.PostData != nil {
+			sr.Body = req.PostData.Text
+			if sr.Body == "" && len(req.PostData.Params) > 0 {
+				pairs := make([]string, len(req.PostData.Params))
+				for j, p := range req.PostData.Params {
+					pairs[j] = url.QueryEscape(p.Name) + "=" + url.QueryEscape(p.Value)
+				}
+				sr.Body = strings.Join(pairs, "&")
+			}
+			if !hasHeader(sr.Headers, "Content-Type") && req.PostData.MimeType != "" {
+				sr.Headers = append(sr.Headers, models.Header{Key: "Content-Type", Value: req.PostData.MimeType})
+			}
+		}
+		result.Requests = append(result.Requests, sr)
+	}
+	return result, nil
+}
+
+// --- Экспорт ---
+
+// ExportHAR преобразует записи истории (пары запрос/ответ) в документ HAR 1.2
+func ExportHAR(entries []models.HistoryEntry) ([]byte, error) {
+	doc := harDocument{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "postui", Version: "1.0"},
+		Entries: []harEntry{},
+	}}
+	for _, e := range entries {
+		doc.Log.Entries = append(doc.Log.Entries, exportHAREntry(e))
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func exportHAREntry(e models.HistoryEntry) harEntry {
+	snapshot := httpclient.HTTPRequest{URL: e.Request.URL, Params: e.Request.Params}
+	fullURL, err := snapshot.BuildURL()
+	if err != nil {
+		fullURL = e.Request.URL
+	}
+
+	req := harRequest{
+		Method:      e.Request.Method,
+		URL:         fullURL,
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []harNameValue{},
+		Headers:     toHARNameValues(e.Request.Headers),
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(e.Request.Body),
+	}
+	if parsed, err := url.Parse(fullURL); err == nil {
+		for _, pair := range strings.Split(parsed.RawQuery, "&") {
+			if pair == "" {
+				continue
+			}
+			kv := strings.SplitN(pair, "=", 2)
+			name, _ := url.QueryUnescape(kv[0])
+			var value string
+			if len(kv) == 2 {
+				value, _ = url.QueryUnescape(kv[1])
+			}
+			req.QueryString = append(req.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+	if e.Request.Body != "" {
+		req.PostData = &harPostData{MimeType: headerValue(e.Request.Headers, "Content-Type"), Text: e.Request.Body}
+	}
+
+	httpVersion := e.Proto
+	if httpVersion == "" {
+		httpVersion = "HTTP/1.1"
+	}
+	req.HTTPVersion = httpVersion
+
+	resp := harResponse{
+		Status:      e.StatusCode,
+		StatusText:  statusText(e.Status, e.StatusCode),
+		HTTPVersion: httpVersion,
+		Cookies:     []harNameValue{},
+		Headers:     toHARNameValues(e.ResponseHeaders),
+		Content: harContent{
+			Size:     len(e.Response),
+			MimeType: headerValue(e.ResponseHeaders, "Content-Type"),
+			Text:     e.Response,
+		},
+		RedirectURL: headerValue(e.ResponseHeaders, "Location"),
+		HeadersSize: -1,
+		BodySize:    len(e.Response),
+		Comment:     e.Error,
+	}
+	if e.Truncated {
+		resp.Comment = "тело ответа обрезано"
+	}
+
+	total := durationMillis(e.Time)
+	if e.Timings != nil {
+		total = models.Milliseconds(e.Timings.Total)
+	}
+	return harEntry{
+		StartedDateTime: e.Timestamp.Format(time.RFC3339Nano),
+		Time:            total,
+		Request:         req,
+		Response:        resp,
+		Timings:         exportHARTimings(e, total),
+	}
+}
+
+// exportHARTimings переводит этапы запроса в тайминги HAR. В HAR время connect
+// включает TLS рукопожатие, а -1 означает, что этап не выполнялся.
+func exportHARTimings(e models.HistoryEntry, total float64) harTimings {
+	if e.Timings == nil {
+		return harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: total}
+	}
+	t := e.Timings
+	optional := func(d time.Duration) float64 {
+		if d <= 0 {
+			return -1
+		}
+		return models.Milliseconds(d)
+	}
+	return harTimings{
+		Blocked: -1,
+		DNS:     optional(t.DNS),
+		Connect: optional(t.Connect + t.TLS),
+		SSL:     optional(t.TLS),
+		Send:    models.Milliseconds(t.Send),
+		Wait:    models.Milliseconds(t.TTFB),
+		Receive: models.Milliseconds(t.Download),
+	}
+}
+
+func toHARNameValues(headers []models.Header) []harNameValue {
+	values := make([]harNameValue, len(headers))
+	for i, h := range headers {
+		values[i] = harNameValue{Name: h.Key, Value: h.Value}
+	}
+	return values
+}
+
+// statusText отделяет текст статуса от кода ("200 OK" -> "OK")
+func statusText(status string, code int) string {
+	return strings.TrimSpace(strings.TrimPrefix(status, fmt.Sprint(code)))
+}
+
+func durationMillis(value string) float64 {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0
+	}
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,191 @@
+package converter
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+func harHistory() []models.HistoryEntry {
+	started := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	return []models.HistoryEntry{
+		{
+			Timestamp: started,
+			Request: models.RequestSnapshot{
+				Method:  "POST",
+				URL:     "https://api.example.com/items",
+				Params:  []models.Param{{Key: "dry", Value: "1"}, {Key: "q", Value: "a b"}},
+				Headers: []models.Header{{Key: "Content-Type", Value: "application/json"}, {Key: "X-Id", Value: "7"}},
+				Body:    `{"name":"item"}`,
+			},
+			Status:     "201 Created",
+			StatusCode: 201,
+			Time:       "95ms",
+			Proto:      "HTTP/2.0",
+			Timings: &models.Timings{
+				DNS:      5 * time.Millisecond,
+				Connect:  10 * time.Millisecond,
+				TLS:      20 * time.Millisecond,
+				Send:     1500 * time.Microsecond,
+				TTFB:     50 * time.Millisecond,
+				Download: 4 * time.Millisecond,
+				Total:    95 * time.Millisecond,
+			},
+			Response:        `{"id":1}`,
+			ResponseHeaders: []models.Header{{Key: "Content-Type", Value: "application/json"}, {Key: "Location", Value: "/items/1"}},
+		},
+		{
+			// Запись без этапов (из старой истории) с обрезанным телом
+			Timestamp: started.Add(time.Second),
+			Request: models.RequestSnapshot{
+				Method:  "GET",
+				URL:     "https://api.example.com/items/1",
+				Headers: []models.Header{{Key: "Accept", Value: "*/*"}},
+			},
+			Status:     "200 OK",
+			StatusCode: 200,
+			Time:       "120ms",
+			Response:   "ok",
+			Truncated:  true,
+		},
+		{
+			Timestamp:  started.Add(2 * time.Second),
+			Request:    models.RequestSnapshot{Method: "DELETE", URL: "https://api.example.com/items/1"},
+			StatusCode: 204,
+			Status:     "204 No Content",
+			Time:       "30ms",
+			Timings:    &models.Timings{Send: time.Millisecond, TTFB: 25 * time.Millisecond, Download: time.Millisecond, Total: 30 * time.Millisecond, Reused: true},
+		},
+	}
+}
+
+func TestExportHAR(t *testing.T) {
+	data, err := ExportHAR(harHistory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc harDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Log.Version != "1.2" || len(doc.Log.Entries) != 3 {
+		t.Fatalf("документ HAR: версия %q, записей %d", doc.Log.Version, len(doc.Log.Entries))
+	}
+
+	post := doc.Log.Entries[0]
+	if post.StartedDateTime != "2024-05-01T12:30:00Z" || post.Time != 95 {
+		t.Errorf("запись: начало %q, время %v", post.StartedDateTime, post.Time)
+	}
+	// connect включает TLS рукопожатие, ssl - отдельно
+	wantTimings := harTimings{Blocked: -1, DNS: 5, Connect: 30, SSL: 20, Send: 1.5, Wait: 50, Receive: 4}
+	if post.Timings != wantTimings {
+		t.Errorf("тайминги = %+v, ожидалось %+v", post.Timings, wantTimings)
+	}
+	if post.Request.URL != "https://api.example.com/items?dry=1&q=a+b" || post.Request.HTTPVersion != "HTTP/2.0" {
+		t.Errorf("запрос: %s %s", post.Request.URL, post.Request.HTTPVersion)
+	}
+	wantQuery := []harNameValue{{Name: "dry", Value: "1"}, {Name: "q", Value: "a b"}}
+	if !reflect.DeepEqual(post.Request.QueryString, wantQuery) {
+		t.Errorf("queryString = %+v", post.Request.QueryString)
+	}
+	wantHeaders := []harNameValue{{Name: "Content-Type", Value: "application/json"}, {Name: "X-Id", Value: "7"}}
+	if !reflect.DeepEqual(post.Request.Headers, wantHeaders) {
+		t.Errorf("заголовки запроса = %+v", post.Request.Headers)
+	}
+	if post.Request.PostData == nil || post.Request.PostData.MimeType != "application/json" || post.Request.PostData.Text != `{"name":"item"}` {
+		t.Errorf("postData = %+v", post.Request.PostData)
+	}
+	resp := post.Response
+	wantRespHeaders := []harNameValue{{Name: "Content-Type", Value: "application/json"}, {Name: "Location", Value: "/items/1"}}
+	if resp.Status != 201 || resp.StatusText != "Created" || !reflect.DeepEqual(resp.Headers, wantRespHeaders) {
+		t.Errorf("ответ = %d %q, заголовки %+v", resp.Status, resp.StatusText, resp.Headers)
+	}
+	if resp.RedirectURL != "/items/1" || resp.Content.MimeType != "application/json" || resp.Content.Text != `{"id":1}` || resp.Content.Size != 8 {
+		t.Errorf("ответ: redirectURL %q, содержимое %+v", resp.RedirectURL, resp.Content)
+	}
+
+	// Без этапов все время считается ожиданием ответа
+	old := doc.Log.Entries[1]
+	if want := (harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: 120}); old.Time != 120 || old.Timings != want {
+		t.Errorf("запись без этапов: время %v, тайминги %+v", old.Time, old.Timings)
+	}
+	if old.Request.PostData != nil || old.Request.HTTPVersion != "HTTP/1.1" || old.Response.Comment != "тело ответа обрезано" {
+		t.Errorf("запись без этапов: %+v", old)
+	}
+
+	// Для повторно использованного соединения DNS, connect и ssl не выполнялись
+	reused := doc.Log.Entries[2]
+	if want := (harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Send: 1, Wait: 25, Receive: 1}); reused.Timings != want {
+		t.Errorf("тайминги повторного соединения = %+v, ожидалось %+v", reused.Timings, want)
+	}
+	if len(reused.Request.Headers) != 0 || reused.Request.Headers == nil {
+		t.Errorf("заголовки должны быть пустым массивом: %s", data)
+	}
+}
+
+func TestHARRoundTrip(t *testing.T) {
+	history := harHistory()
+	data, err := ExportHAR(history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ImportHAR(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Requests) != len(history) || len(result.Warnings) != 0 {
+		t.Fatalf("запросов %d, предупреждения %q", len(result.Requests), result.Warnings)
+	}
+	for i, sr := range result.Requests {
+		want := history[i].ToSavedRequest(sr.Name)
+		if want.Headers == nil {
+			want.Headers = []models.Header{}
+		}
+		if want.Params == nil {
+			want.Params = []models.Param{}
+		}
+		if !reflect.DeepEqual(sr, want) {
+			t.Errorf("запрос %d после экспорта и импорта:\n%+v\nожидалось:\n%+v", i+1, sr, want)
+		}
+	}
+	if name := result.Requests[0].Name; name != "1. POST api.example.com/items" {
+		t.Errorf("имя запроса = %q", name)
+	}
+}
+
+func TestImportHARBrowser(t *testing.T) {
+	data := []byte(`{"log": {"entries": [
+		{"request": {"method": "POST", "url": "https://example.com/login?next=%2Fhome",
+			"headers": [
+				{"name": ":authority", "value": "example.com"},
+				{"name": "Host", "value": "example.com"},
+				{"name": "Content-Length", "value": "21"},
+				{"name": "Cookie", "value": "a=1"}
+			],
+			"postData": {"mimeType": "application/x-www-form-urlencoded",
+				"params": [{"name": "user", "value": "bob"}, {"name": "pass", "value": "a&b"}]}}},
+		{"request": {"method": "CONNECT", "url": "https://example.com:443"}}
+	]}}`)
+	result, err := ImportHAR(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := result.Requests[0]
+	wantHeaders := []models.Header{{Key: "Cookie", Value: "a=1"}, {Key: "Content-Type", Value: "application/x-www-form-urlencoded"}}
+	if !reflect.DeepEqual(login.Headers, wantHeaders) {
+		t.Errorf("заголовки = %+v, ожидалось %+v", login.Headers, wantHeaders)
+	}
+	if login.Body != "user=bob&pass=a%26b" || !reflect.DeepEqual(login.Params, []models.Param{{Key: "next", Value: "/home"}}) {
+		t.Errorf("тело %q, параметры %+v", login.Body, login.Params)
+	}
+	if want := []string{"2. GET example.com:443: метод CONNECT не поддерживается, используется GET"}; !reflect.DeepEqual(result.Warnings, want) {
+		t.Errorf("предупреждения = %q", result.Warnings)
+	}
+
+	if _, err := ImportHAR([]byte(`{"log": []}`)); err == nil {
+		t.Error("ожидалась ошибка формата HAR")
+	}
+}
@@ -0,0 +1,773 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+var (
+	httpFileVariable    = regexp.MustCompile(`^@([A-Za-z0-9_.\-]+)\s*=\s*(.*)$`)
+	httpFileNameComment = regexp.MustCompile(`^(?:#|//)\s*@name\s+(.+)$`)
+	httpFileRequestLine = regexp.MustCompile(`^([A-Za-z]+)\s+(\S+)(?:\s+HTTP/[0-9.]+)?$`)
+	// httpFileAuthComment задает авторизацию, которую нельзя записать заголовком (API Key, OAuth2)
+	httpFileAuthComment = regexp.MustCompile(`^(?:#|//)\s*@auth\s+(\S+)\s*(.*)$`)
+	// httpFileAssertComment задает проверку ответа: # @assert status in 2xx
+	httpFileAssertComment = regexp.MustCompile(`^(?:#|//)\s*@assert\s+(.+)$`)
+	// httpFileExtractComment задает переменную из ответа: # @extract token = json $.token
+	httpFileExtractComment = regexp.MustCompile(`^(?:#|//)\s*@extract\s+(.+)$`)
+	// httpFileFolderComment объявляет папку: # @folder Папка/Подпапка. Запросы с путем
+	// папки в имени попадают в нее, # @base_url и # @header задают ее базовый URL и заголовки
+	httpFileFolderComment  = regexp.MustCompile(`^(?:#|//)\s*@folder\s+(.+)$`)
+	httpFileBaseURLComment = regexp.MustCompile(`^(?:#|//)\s*@base_url\s+(.+)$`)
+	httpFileHeaderComment  = regexp.MustCompile(`^(?:#|//)\s*@header\s+(.+)$`)
+)
+
+// IsHTTPFile сообщает, является ли путь файлом в формате .http / .rest
+func IsHTTPFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".http" || ext == ".rest"
+}
+
+// ParseHTTPFile разбирает файл в формате VS Code REST Client / JetBrains HTTP Client.
+// Запросы разделяются строками ###, переменные задаются строками @name = value.
+func ParseHTTPFile(data []byte) (models.Collection, error) {
+	collection, _, err := parseHTTPFile(data)
+	return collection, err
+}
+
+// httpFileSource хранит исходный текст файла, чтобы при сохранении записывать
+// неизмененные запросы и комментарии файла в прежнем виде
+type httpFileSource struct {
+	preamble    string // текст до первого запроса: переменные, папки, комментарии
+	preambleKey string // переменные и папки файла в формате FormatHTTPFile
+	requests    []httpFileText
+}
+
+// httpFileText содержит исходный текст запроса до начала следующего запроса
+type httpFileText struct {
+	key        string // запрос с примененным наследованием в формате FormatHTTPFile
+	location   string // путь папки и индекс запроса в ней после загрузки
+	text       string
+	separated  bool // текст начинается разделителем ###
+	directives bool // текст содержит переменные или папки файла
+}
+
+func parseHTTPFile(data []byte) (models.Collection, *httpFileSource, error) {
+	var (
+		collection models.Collection
+		current    *models.SavedRequest
+		name       string
+		state      int // 0 - до строки запроса, 1 - заголовки, 2 - тело
+		body       []string
+		assertions []models.Assertion  // проверки, заданные до строки запроса
+		extract    []models.Extraction // переменные из ответа, заданные до строки запроса
+		folder     *models.Folder      // папка, к которой относятся # @base_url и # @header
+
+		chunkStart = -1 // смещение разделителя ### текущего блока
+		leadStart  = -1 // смещение комментариев перед запросом вне блока ###
+		starts     []int
+		separated  []bool
+		directives []int // смещения строк переменных и папок
+	)
+
+	finish := func() {
+		if current != nil {
+			current.Body = strings.TrimRight(strings.Join(body, "\n"), "\n")
+			// Тело "< path" в REST Client - содержимое файла
+			if path, ok := strings.CutPrefix(current.Body, "< "); ok && !strings.Contains(path, "\n") {
+				current.BodyType, current.Body = models.BodyBinary, strings.TrimSpace(path)
+			}
+			if current.Name == "" {
+				current.Name = models.MethodNames[current.Method] + " " + current.URL
+			}
+			collection.Requests = append(collection.Requests, *current)
+		}
+		current, name, state, body, assertions, extract = nil, "", 0, nil, nil, nil
+	}
+
+	lineNumber := 0
+	for offset := 0; offset < len(data); {
+		lineNumber++
+		lineStart, lineEnd := offset, len(data)
+		if idx := bytes.IndexByte(data[offset:], '\n'); idx >= 0 {
+			lineEnd = offset + idx
+			offset = lineEnd + 1
+		} else {
+			offset = len(data)
+		}
+		line := strings.TrimRight(string(data[lineStart:lineEnd]), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "###") {
+			finish()
+			name = strings.TrimSpace(strings.TrimPrefix(trimmed, "###"))
+			chunkStart, leadStart = lineStart, -1
+			continue
+		}
+
+		switch state {
+		case 0:
+			if trimmed == "" {
+				leadStart = -1
+				continue
+			}
+			if m := httpFileVariable.FindStringSubmatch(trimmed); m != nil {
+				collection.Variables = append(collection.Variables, models.Variable{Key: m[1], Value: strings.TrimSpace(m[2])})
+				directives, leadStart = append(directives, lineStart), -1
+				continue
+			}
+			if m := httpFileFolderComment.FindStringSubmatch(trimmed); m != nil {
+				folder = ensureHTTPFileFolder(&collection.Folder, strings.Split(strings.Trim(strings.TrimSpace(m[1]), "/"), "/"))
+				directives, leadStart = append(directives, lineStart), -1
+				continue
+			}
+			if m := httpFileBaseURLComment.FindStringSubmatch(trimmed); m != nil {
+				if folder == nil {
+					return collection, nil, fmt.Errorf("строка %d: @base_url без @folder", lineNumber)
+				}
+				folder.BaseURL = strings.TrimSpace(m[1])
+				directives, leadStart = append(directives, lineStart), -1
+				continue
+			}
+			if m := httpFileHeaderComment.FindStringSubmatch(trimmed); m != nil {
+				key, value, ok := strings.Cut(m[1], ":")
+				if folder == nil || !ok {
+					return collection, nil, fmt.Errorf("строка %d: неверный заголовок папки %q", lineNumber, trimmed)
+				}
+				folder.Headers = append(folder.Headers, models.Header{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+				directives, leadStart = append(directives, lineStart), -1
+				continue
+			}
+			if leadStart < 0 && (strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//")) {
+				leadStart = lineStart
+			}
+			if m := httpFileNameComment.FindStringSubmatch(trimmed); m != nil {
+				name = strings.TrimSpace(m[1])
+				continue
+			}
+			if m := httpFileAssertComment.FindStringSubmatch(trimmed); m != nil {
+				a, err := models.ParseAssertion(m[1])
+				if err != nil {
+					return collection, nil, fmt.Errorf("строка %d: %w", lineNumber, err)
+				}
+				assertions = append(assertions, a)
+				continue
+			}
+			if m := httpFileExtractComment.FindStringSubmatch(trimmed); m != nil {
+				e, err := models.ParseExtraction(m[1])
+				if err != nil {
+					return collection, nil, fmt.Errorf("строка %d: %w", lineNumber, err)
+				}
+				extract = append(extract, e)
+				continue
+			}
+			if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") {
+				continue
+			}
+
+			method, rawURL := "GET", trimmed
+			if m := httpFileRequestLine.FindStringSubmatch(trimmed); m != nil {
+				method, rawURL = strings.ToUpper(m[1]), m[2]
+			} else if strings.Contains(trimmed, " ") {
+				return collection, nil, fmt.Errorf("строка %d: неверная строка запроса %q", lineNumber, trimmed)
+			}
+			httpMethod, ok := models.LookupMethod(method)
+			if !ok {
+				return collection, nil, fmt.Errorf("строка %d: неподдерживаемый метод %s", lineNumber, method)
+			}
+			current = &models.SavedRequest{
+				Name:       name,
+				Method:     httpMethod,
+				URL:        rawURL,
+				Headers:    []models.Header{},
+				Params:     []models.Param{},
+				Assertions: assertions,
+				Extract:    extract,
+			}
+			state = 1
+
+			// Текст запроса начинается с разделителя ### или с комментариев перед ним
+			switch {
+			case chunkStart >= 0:
+				starts, separated = append(starts, chunkStart), append(separated, true)
+			case leadStart >= 0:
+				starts, separated = append(starts, leadStart), append(separated, false)
+			default:
+				starts, separated = append(starts, lineStart), append(separated, false)
+			}
+			chunkStart = -1
+		case 1:
+			if trimmed == "" {
+				state = 2
+				continue
+			}
+			// Продолжение строки запроса: параметры на отдельных строках
+			if strings.HasPrefix(trimmed, "?") || strings.HasPrefix(trimmed, "&") {
+				current.URL += trimmed
+				continue
+			}
+			if m := httpFileAuthComment.FindStringSubmatch(trimmed); m != nil {
+				current.Auth = models.ParseAuthParams(models.AuthType(strings.ToLower(m[1])), m[2])
+				continue
+			}
+			if m := httpFileAssertComment.FindStringSubmatch(trimmed); m != nil {
+				a, err := models.ParseAssertion(m[1])
+				if err != nil {
+					return collection, nil, fmt.Errorf("строка %d: %w", lineNumber, err)
+				}
+				current.Assertions = append(current.Assertions, a)
+				continue
+			}
+			if m := httpFileExtractComment.FindStringSubmatch(trimmed); m != nil {
+				e, err := models.ParseExtraction(m[1])
+				if err != nil {
+					return collection, nil, fmt.Errorf("строка %d: %w", lineNumber, err)
+				}
+				current.Extract = append(current.Extract, e)
+				continue
+			}
+			if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") {
+				continue
+			}
+			parts := strings.SplitN(trimmed, ":", 2)
+			if len(parts) != 2 {
+				return collection, nil, fmt.Errorf("строка %d: неверный заголовок %q", lineNumber, trimmed)
+			}
+			key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+			if auth, ok := parseHTTPFileAuth(key, value); ok {
+				current.Auth = auth
+				continue
+			}
+			current.Headers = append(current.Headers, models.Header{Key: key, Value: value})
+		case 2:
+			body = append(body, line)
+		}
+	}
+	finish()
+
+	// Запросы с путем объявленной папки в имени переносятся в эту папку
+	source := &httpFileSource{preamble: string(data)}
+	requests := collection.Requests
+	collection.Requests = nil
+	for i, sr := range requests {
+		target, prefix := findHTTPFileFolder(&collection.Folder, sr.Name)
+		defaults := collection.DefaultsFor(target)
+		sr.Name = strings.TrimPrefix(sr.Name, prefix)
+		sr = unapplyHTTPFileDefaults(defaults, sr)
+		target.Requests = append(target.Requests, sr)
+
+		end := len(data)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		resolved := defaults.Apply(sr)
+		resolved.Name = prefix + sr.Name
+		text := httpFileText{
+			key:       formatHTTPFileRequest(resolved),
+			location:  httpFileLocation(prefix, len(target.Requests)-1),
+			text:      string(data[starts[i]:end]),
+			separated: separated[i],
+		}
+		for _, d := range directives {
+			text.directives = text.directives || d >= starts[i] && d < end
+		}
+		source.requests = append(source.requests, text)
+	}
+	if len(starts) > 0 {
+		source.preamble = string(data[:starts[0]])
+	}
+	source.preambleKey = formatHTTPFilePreamble(collection)
+	return collection, source, nil
+}
+
+// ensureHTTPFileFolder возвращает вложенную папку по цепочке имен, создавая недостающие
+func ensureHTTPFileFolder(root *models.Folder, names []string) *models.Folder {
+	folder := root
+	for _, name := range names {
+		var next *models.Folder
+		for _, child := range folder.Folders {
+			if child.Name == name {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			next = &models.Folder{Name: name}
+			folder.Folders = append(folder.Folders, next)
+		}
+		folder = next
+	}
+	return folder
+}
+
+// findHTTPFileFolder возвращает самую глубокую объявленную папку, путь которой
+// является началом имени запроса, и этот путь с завершающим "/"
+func findHTTPFileFolder(root *models.Folder, name string) (*models.Folder, string) {
+	folder, prefix := root, ""
+	for {
+		var next *models.Folder
+		for _, child := range folder.Folders {
+			if strings.HasPrefix(name, prefix+child.Name+"/") {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return folder, prefix
+		}
+		folder, prefix = next, prefix+next.Name+"/"
+	}
+}
+
+// unapplyHTTPFileDefaults убирает из запроса унаследованные от папок базовый URL и заголовки,
+// которые FormatHTTPFile записывает в каждый запрос папки
+func unapplyHTTPFileDefaults(defaults models.RequestDefaults, sr models.SavedRequest) models.SavedRequest {
+	if base := defaults.BaseURL; base != "" {
+		switch {
+		case sr.URL == base:
+			sr.URL = ""
+		case strings.HasPrefix(sr.URL, base+"?"):
+			sr.URL = strings.TrimPrefix(sr.URL, base)
+		case strings.HasPrefix(sr.URL, strings.TrimRight(base, "/")+"/"):
+			sr.URL = strings.TrimPrefix(sr.URL, strings.TrimRight(base, "/")+"/")
+		}
+	}
+	if len(defaults.Headers) > 0 {
+		sr.Headers = slices.DeleteFunc(slices.Clone(sr.Headers), func(h models.Header) bool {
+			return slices.ContainsFunc(defaults.Headers, func(d models.Header) bool {
+				return strings.EqualFold(d.Key, h.Key) && d.Value == h.Value
+			})
+		})
+	}
+	return sr
+}
+
+// FormatHTTPFile записывает коллекцию в формате .http. Формат не поддерживает папки,
+// поэтому запросы записываются с путем в имени и унаследованными от папок значениями,
+// а папки объявляются комментариями # @folder, # @base_url и # @header.
+func FormatHTTPFile(collection models.Collection) []byte {
+	return formatHTTPFile(collection, nil)
+}
+
+// formatHTTPFile записывает коллекцию в формате .http. Если задан исходный текст файла,
+// неизмененные запросы и начало файла записываются им, а запросы сохраняют порядок в файле.
+func formatHTTPFile(collection models.Collection, source *httpFileSource) []byte {
+	preamble := formatHTTPFilePreamble(collection)
+	requests := resolveHTTPFileRequests(collection)
+	original := false // записать начало файла исходным текстом
+	if source != nil {
+		original = source.match(requests, preamble)
+	}
+
+	var buf bytes.Buffer
+	last := -2 // индекс последнего записанного исходного текста (-1 - начало файла)
+	switch {
+	case original:
+		buf.WriteString(source.preamble)
+		last = -1
+	case source != nil:
+		buf.WriteString(source.mergePreamble(collection))
+	default:
+		buf.WriteString(preamble)
+	}
+	for _, r := range requests {
+		if r.text < 0 || r.text != last+1 {
+			separateHTTPFileBlock(&buf)
+		}
+		if r.text >= 0 {
+			buf.WriteString(source.requests[r.text].text)
+			last = r.text
+		} else {
+			buf.WriteString(formatHTTPFileRequest(r.request))
+			last = -2
+		}
+	}
+	return buf.Bytes()
+}
+
+// httpFileRequest - запрос коллекции с примененным наследованием и путем в имени
+type httpFileRequest struct {
+	request  models.SavedRequest
+	location string // путь папки и индекс запроса в ней
+	text     int    // индекс исходного текста запроса (-1 - записать заново)
+}
+
+// resolveHTTPFileRequests возвращает запросы коллекции в порядке Collection.Resolved
+func resolveHTTPFileRequests(collection models.Collection) []httpFileRequest {
+	var requests []httpFileRequest
+	var walk func(f *models.Folder, prefix string, defaults models.RequestDefaults)
+	walk = func(f *models.Folder, prefix string, defaults models.RequestDefaults) {
+		defaults = defaults.Inherit(f)
+		for _, child := range f.Folders {
+			walk(child, prefix+child.Name+"/", defaults)
+		}
+		for i, sr := range f.Requests {
+			resolved := defaults.Apply(sr)
+			resolved.Name = prefix + sr.Name
+			requests = append(requests, httpFileRequest{
+				request:  resolved,
+				location: httpFileLocation(prefix, i),
+				text:     -1,
+			})
+		}
+	}
+	walk(&collection.Folder, "", models.RequestDefaults{})
+	return requests
+}
+
+func httpFileLocation(prefix string, index int) string {
+	return fmt.Sprintf("%s#%d", prefix, index)
+}
+
+// match сопоставляет запросы с исходным текстом и упорядочивает их как в файле:
+// неизмененные и отредактированные запросы остаются на своих местах, новые
+// записываются после предыдущего запроса коллекции. Возвращает, можно ли записать
+// начало файла исходным текстом.
+func (s *httpFileSource) match(requests []httpFileRequest, preamble string) bool {
+	used := make([]bool, len(s.requests))
+	for i := range requests {
+		key := formatHTTPFileRequest(requests[i].request)
+		for j, text := range s.requests {
+			if !used[j] && text.key == key {
+				used[j], requests[i].text = true, j
+				break
+			}
+		}
+	}
+
+	type position struct{ text, seq int }
+	positions := make(map[string]position, len(requests))
+	previous := position{text: -1}
+	for i, r := range requests {
+		pos := position{text: r.text}
+		if r.text < 0 {
+			// Отредактированный запрос занимает место исходного текста на том же месте в папке
+			pos = position{text: previous.text, seq: i + 1}
+			for j, text := range s.requests {
+				if !used[j] && text.location == r.location {
+					pos = position{text: j}
+					break
+				}
+			}
+		}
+		positions[r.location], previous = pos, pos
+	}
+	slices.SortStableFunc(requests, func(a, b httpFileRequest) int {
+		pa, pb := positions[a.location], positions[b.location]
+		if pa.text != pb.text {
+			return pa.text - pb.text
+		}
+		return pa.seq - pb.seq
+	})
+
+	// Текст без ### можно записать только на прежнем месте: сразу после начала файла
+	for i, r := range requests {
+		if r.text >= 0 && !s.requests[r.text].separated && (i > 0 || r.text > 0) {
+			used[r.text], requests[i].text = false, -1
+		}
+	}
+	// Переменные и папки из текста измененных запросов попадут в начало файла
+	original := s.preambleKey == preamble
+	for j, text := range s.requests {
+		if text.directives && !used[j] {
+			original = false
+		}
+	}
+	for i, r := range requests {
+		if r.text >= 0 && !original && s.requests[r.text].directives {
+			requests[i].text = -1
+		}
+	}
+	return original
+}
+
+// mergePreamble записывает переменные и папки коллекции в исходное начало файла,
+// сохраняя комментарии и пустые строки. Переменные заменяются на месте, новые
+// добавляются после последней из них, папки объявляются на месте первой исходной папки.
+func (s *httpFileSource) mergePreamble(collection models.Collection) string {
+	values := make(map[string]string, len(collection.Variables))
+	for _, v := range collection.Variables {
+		values[v.Key] = v.Value
+	}
+	var folders bytes.Buffer
+	writeHTTPFileFolders(&folders, &collection.Folder, "")
+
+	lines := strings.SplitAfter(s.preamble, "\n")
+	lastVariable := -1
+	for i, line := range lines {
+		if httpFileVariable.MatchString(strings.TrimSpace(line)) {
+			lastVariable = i
+		}
+	}
+
+	var buf bytes.Buffer
+	written := map[string]bool{}
+	writeVariables := func() {
+		for _, v := range collection.Variables {
+			if !written[v.Key] {
+				fmt.Fprintf(&buf, "@%s = %s\n", v.Key, v.Value)
+				written[v.Key] = true
+			}
+		}
+	}
+	foldersWritten, afterDirective := false, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		match := httpFileVariable.FindStringSubmatch(trimmed)
+		switch {
+		case match != nil:
+			if value, ok := values[match[1]]; ok && !written[match[1]] {
+				if value == strings.TrimSpace(match[2]) {
+					buf.WriteString(line)
+				} else {
+					fmt.Fprintf(&buf, "@%s = %s\n", match[1], value)
+				}
+				written[match[1]] = true
+			}
+			if i == lastVariable {
+				writeVariables()
+			}
+			afterDirective = true
+		case isHTTPFileDirective(trimmed):
+			if !foldersWritten {
+				writeVariables()
+				buf.Write(folders.Bytes())
+				foldersWritten = true
+			}
+			afterDirective = true
+		case trimmed == "" && afterDirective && bytes.HasSuffix(buf.Bytes(), []byte("\n\n")):
+			// Пустые строки между удаленными объявлениями
+		default:
+			buf.WriteString(line)
+			afterDirective = afterDirective && trimmed == ""
+		}
+	}
+
+	pending := slices.ContainsFunc(collection.Variables, func(v models.Variable) bool { return !written[v.Key] })
+	if pending || !foldersWritten && folders.Len() > 0 {
+		separateHTTPFileBlock(&buf)
+		writeVariables()
+		buf.Write(folders.Bytes())
+	}
+	return buf.String()
+}
+
+// isHTTPFileDirective сообщает, задает ли строка переменную или папку файла
+func isHTTPFileDirective(trimmed string) bool {
+	for _, re := range []*regexp.Regexp{httpFileVariable, httpFileFolderComment, httpFileBaseURLComment, httpFileHeaderComment} {
+		if re.MatchString(trimmed) {
+			return true
+		}
+	}
+	return false
+}
+
+// separateHTTPFileBlock отделяет следующий запрос пустой строкой
+func separateHTTPFileBlock(buf *bytes.Buffer) {
+	if buf.Len() == 0 {
+		return
+	}
+	for !bytes.HasSuffix(buf.Bytes(), []byte("\n\n")) {
+		buf.WriteString("\n")
+	}
+}
+
+// formatHTTPFilePreamble записывает переменные коллекции и объявления папок
+func formatHTTPFilePreamble(collection models.Collection) string {
+	var buf bytes.Buffer
+	for _, v := range collection.Variables {
+		fmt.Fprintf(&buf, "@%s = %s\n", v.Key, v.Value)
+	}
+	writeHTTPFileFolders(&buf, &collection.Folder, "")
+	return buf.String()
+}
+
+// writeHTTPFileFolders объявляет вложенные папки с их базовым URL и заголовками
+func writeHTTPFileFolders(buf *bytes.Buffer, folder *models.Folder, prefix string) {
+	for _, child := range folder.Folders {
+		path := prefix + child.Name
+		fmt.Fprintf(buf, "# @folder %s\n", path)
+		if child.BaseURL != "" {
+			fmt.Fprintf(buf, "# @base_url %s\n", child.BaseURL)
+		}
+		for _, h := range child.Headers {
+			fmt.Fprintf(buf, "# @header %s: %s\n", h.Key, h.Value)
+		}
+		writeHTTPFileFolders(buf, child, path+"/")
+	}
+}
+
+// formatHTTPFileRequest записывает запрос с разделителем ### и именем
+func formatHTTPFileRequest(sr models.SavedRequest) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "### %s\n", sr.Name)
+
+	requestURL := sr.URL
+	if len(sr.Params) > 0 {
+		pairs := make([]string, len(sr.Params))
+		for j, p := range sr.Params {
+			pairs[j] = p.Key + "=" + p.Value
+		}
+		separator := "?"
+		if strings.Contains(requestURL, "?") {
+			separator = "&"
+		}
+		requestURL += separator + strings.Join(pairs, "&")
+	}
+	fmt.Fprintf(&buf, "%s %s\n", models.MethodNames[sr.Method], requestURL)
+
+	for _, h := range sr.Headers {
+		if sr.BodyType.HasForm() && strings.EqualFold(h.Key, "Content-Type") {
+			continue
+		}
+		fmt.Fprintf(&buf, "%s: %s\n", h.Key, h.Value)
+	}
+	writeHTTPFileAuth(&buf, sr.Auth)
+	for _, a := range sr.Assertions {
+		fmt.Fprintf(&buf, "# @assert %s\n", a)
+	}
+	for _, e := range sr.Extract {
+		fmt.Fprintf(&buf, "# @extract %s\n", e)
+	}
+	writeHTTPFileBody(&buf, sr)
+	return buf.String()
+}
+
+// httpFileBoundary граница частей multipart в экспортированных файлах
+const httpFileBoundary = "postui-boundary"
+
+// writeHTTPFileBody записывает тело запроса в синтаксисе REST Client:
+// поля формы - строками name=value через &, файлы - строками "< path"
+func writeHTTPFileBody(buf *bytes.Buffer, sr models.SavedRequest) {
+	switch sr.BodyType {
+	case models.BodyNone:
+	case models.BodyForm:
+		buf.WriteString("Content-Type: application/x-www-form-urlencoded\n\n")
+		for i, f := range sr.Form {
+			if i > 0 {
+				buf.WriteString("\n&")
+			}
+			buf.WriteString(url.QueryEscape(f.Key) + "=" + url.QueryEscape(f.Value))
+		}
+		buf.WriteString("\n")
+	case models.BodyMultipart:
+		fmt.Fprintf(buf, "Content-Type: multipart/form-data; boundary=%s\n\n", httpFileBoundary)
+		for _, f := range sr.Form {
+			fmt.Fprintf(buf, "--%s\n", httpFileBoundary)
+			if f.File {
+				fmt.Fprintf(buf, "Content-Disposition: form-data; name=%q; filename=%q\n", f.Key, filepath.Base(f.Value))
+				if f.ContentType != "" {
+					fmt.Fprintf(buf, "Content-Type: %s\n", f.ContentType)
+				}
+				fmt.Fprintf(buf, "\n< %s\n", f.Value)
+			} else {
+				fmt.Fprintf(buf, "Content-Disposition: form-data; name=%q\n\n%s\n", f.Key, f.Value)
+			}
+		}
+		fmt.Fprintf(buf, "--%s--\n", httpFileBoundary)
+	case models.BodyBinary:
+		fmt.Fprintf(buf, "\n< %s\n", sr.Body)
+	default:
+		if sr.Body != "" {
+			buf.WriteString("\n")
+			buf.WriteString(sr.Body)
+			buf.WriteString("\n")
+		}
+	}
+}
+
+// parseHTTPFileAuth распознает заголовки Authorization в форме REST Client
+// с открытыми учетными данными: "Basic user:password", "Basic user password",
+// "Digest user password" и "Bearer token". Закодированный Basic остается обычным заголовком.
+func parseHTTPFileAuth(key, value string) (models.Auth, bool) {
+	if !strings.EqualFold(key, "Authorization") {
+		return models.Auth{}, false
+	}
+	fields := strings.Fields(value)
+	if len(fields) < 2 {
+		return models.Auth{}, false
+	}
+	switch {
+	case strings.EqualFold(fields[0], "Basic") && len(fields) == 2 && strings.Contains(fields[1], ":"):
+		username, password, _ := strings.Cut(fields[1], ":")
+		return models.Auth{Type: models.AuthBasic, Username: username, Password: password}, true
+	case strings.EqualFold(fields[0], "Basic") && len(fields) == 3:
+		return models.Auth{Type: models.AuthBasic, Username: fields[1], Password: fields[2]}, true
+	case strings.EqualFold(fields[0], "Bearer") && len(fields) == 2:
+		return models.Auth{Type: models.AuthBearer, Token: fields[1]}, true
+	case strings.EqualFold(fields[0], "Digest") && len(fields) == 3:
+		return models.Auth{Type: models.AuthDigest, Username: fields[1], Password: fields[2]}, true
+	}
+	return models.Auth{}, false
+}
+
+// writeHTTPFileAuth записывает авторизацию запроса заголовком в форме REST Client,
+// а API Key и OAuth2 - комментарием # @auth
+func writeHTTPFileAuth(buf *bytes.Buffer, auth models.Auth) {
+	switch auth.Type {
+	case models.AuthBasic:
+		fmt.Fprintf(buf, "Authorization: Basic %s:%s\n", auth.Username, auth.Password)
+	case models.AuthDigest:
+		fmt.Fprintf(buf, "Authorization: Digest %s %s\n", auth.Username, auth.Password)
+	case models.AuthBearer:
+		fmt.Fprintf(buf, "Authorization: Bearer %s\n", auth.Token)
+	case models.AuthAPIKey, models.AuthOAuth2:
+		fmt.Fprintf(buf, "# @auth %s %s\n", auth.Type, models.FormatAuthParams(auth))
+	}
+}
+
+// HTTPFileStore хранит коллекцию запросов в файле .http / .rest
+type HTTPFileStore struct {
+	path   string
+	source *httpFileSource // текст файла при последней загрузке или сохранении
+}
+
+// NewHTTPFileStore создает хранилище запросов в указанном файле .http
+func NewHTTPFileStore(path string) *HTTPFileStore {
+	return &HTTPFileStore{path: path}
+}
+
+func (s *HTTPFileStore) Load() (models.Collection, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return models.Collection{Folder: models.Folder{Name: filepath.Base(s.path)}}, nil
+		}
+		return models.Collection{}, err
+	}
+	collection, source, err := parseHTTPFile(data)
+	collection.Name = filepath.Base(s.path)
+	if err == nil {
+		s.source = source
+	}
+	return collection, err
+}
+
+// Source возвращает абсолютный путь файла
+func (s *HTTPFileStore) Source() string {
+	if path, err := filepath.Abs(s.path); err == nil {
+		return path
+	}
+	return s.path
+}
+
+// Save записывает коллекцию в файл. Запросы, не изменившиеся с загрузки,
+// сохраняют исходный текст вместе с комментариями и порядком заголовков.
+func (s *HTTPFileStore) Save(collection models.Collection) error {
+	data := formatHTTPFile(collection, s.source)
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return err
+	}
+	if _, source, err := parseHTTPFile(data); err == nil {
+		s.source = source
+	}
+	return nil
+}
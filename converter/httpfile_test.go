@@ -0,0 +1,227 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+const httpFileSample = `# Тестовое API
+@host = https://api.example.com
+
+# @folder Users
+# @base_url {{host}}/users
+# @header Accept: application/json
+
+# @name list
+GET {{host}}/health
+Authorization: Bearer {{token}}
+X-Trace: 1
+
+###
+
+### Users/Create
+// создание пользователя
+POST {{host}}/users
+Accept: application/json
+Content-Type: application/json
+# @assert status 201
+
+{"name": "bob"}
+
+### Users/Get
+GET {{host}}/users/1
+?expand=roles
+Accept: application/json
+
+###
+`
+
+func writeHTTPFile(t *testing.T, content string) *HTTPFileStore {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "api.http")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return NewHTTPFileStore(path)
+}
+
+func readHTTPFile(t *testing.T, store *HTTPFileStore) string {
+	t.Helper()
+	data, err := os.ReadFile(store.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestHTTPFileStoreSaveUnchanged(t *testing.T) {
+	for name, content := range map[string]string{
+		"sample": httpFileSample,
+		"crlf":   strings.ReplaceAll(httpFileSample, "\n", "\r\n"),
+		"no trailing newline": "@host = http://localhost\n\n# комментарий\nGET {{host}}/a\n\n" +
+			"###\nPOST {{host}}/b\n\n{}",
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := writeHTTPFile(t, content)
+			collection, err := store.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Save(collection); err != nil {
+				t.Fatal(err)
+			}
+			if got := readHTTPFile(t, store); got != content {
+				t.Errorf("файл изменился при сохранении:\n%s\nожидалось:\n%s", got, content)
+			}
+		})
+	}
+}
+
+func TestHTTPFileStoreKeepsFolders(t *testing.T) {
+	store := writeHTTPFile(t, httpFileSample)
+	collection, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collection.Folders) != 1 || collection.Folders[0].Name != "Users" {
+		t.Fatalf("папки = %+v, ожидалась папка Users", collection.Folders)
+	}
+	users := collection.Folders[0]
+	if users.BaseURL != "{{host}}/users" || len(users.Headers) != 1 {
+		t.Errorf("папка Users = %+v", users)
+	}
+	if len(users.Requests) != 2 || users.Requests[0].Name != "Create" || users.Requests[1].URL != "1?expand=roles" {
+		t.Errorf("запросы папки Users = %+v", users.Requests)
+	}
+	create := users.Requests[0]
+	if len(create.Headers) != 1 || create.Headers[0].Key != "Content-Type" {
+		t.Errorf("заголовки Create = %+v, заголовок папки должен наследоваться", create.Headers)
+	}
+
+	// Без исходного текста папки записываются объявлениями и читаются обратно
+	reparsed, err := ParseHTTPFile(FormatHTTPFile(collection))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := FormatHTTPFile(reparsed), FormatHTTPFile(collection); string(got) != string(want) {
+		t.Errorf("коллекция изменилась после записи и чтения:\n%s\nожидалось:\n%s", got, want)
+	}
+	if len(reparsed.Folders) != 1 || len(reparsed.Folders[0].Requests) != 2 {
+		t.Errorf("папки после записи и чтения = %+v", reparsed.Folders)
+	}
+}
+
+func TestHTTPFileStoreSaveEdited(t *testing.T) {
+	store := writeHTTPFile(t, httpFileSample)
+	collection, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.Folders[0].Requests[1].Method = models.MethodDELETE
+	if err := store.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readHTTPFile(t, store)
+	// Неизмененные запросы и комментарии сохраняют исходный текст
+	unchanged := httpFileSample[:strings.Index(httpFileSample, "### Users/Get")]
+	if !strings.HasPrefix(got, unchanged) {
+		t.Errorf("текст неизмененных запросов изменился:\n%s", got)
+	}
+	if !strings.Contains(got, "### Users/Get\nDELETE {{host}}/users/1?expand=roles\nAccept: application/json\n") {
+		t.Errorf("измененный запрос не записан:\n%s", got)
+	}
+
+	reloaded, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sr, err := reloaded.FindRequest("Users/Get"); err != nil || sr.Method != models.MethodDELETE {
+		t.Errorf("запрос после сохранения = %+v, %v", sr, err)
+	}
+}
+
+func TestHTTPFileStoreSaveVariables(t *testing.T) {
+	store := writeHTTPFile(t, httpFileSample)
+	collection, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.Variables = append(collection.Variables, models.Variable{Key: "token", Value: "secret"})
+	if err := store.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readHTTPFile(t, store)
+	want := "# Тестовое API\n@host = https://api.example.com\n@token = secret\n\n# @folder Users\n"
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("начало файла:\n%s\nожидалось:\n%s", got, want)
+	}
+	if !strings.Contains(got, httpFileSample[strings.Index(httpFileSample, "\n# @name list"):]) {
+		t.Errorf("текст запросов изменился:\n%s", got)
+	}
+}
+
+func TestHTTPFileStoreMergePreamble(t *testing.T) {
+	const request = "### ping\nGET {{host}}/ping\n"
+	for _, tc := range []struct {
+		name, preamble string
+		edit           func(c *models.Collection)
+		want           string
+	}{
+		{
+			name:     "значение переменной заменяется на месте",
+			preamble: "# Адрес\n@host=http://a\n// токен\n@token = x\n\n",
+			edit:     func(c *models.Collection) { c.Variables[0].Value = "http://b" },
+			want:     "# Адрес\n@host = http://b\n// токен\n@token = x\n\n",
+		},
+		{
+			name:     "новая переменная после последней, удаленная пропускается",
+			preamble: "@host = http://a\n# комментарий\n@token = x\n\n# @folder Users\n\n",
+			edit: func(c *models.Collection) {
+				c.Variables = []models.Variable{{Key: "host", Value: "http://a"}, {Key: "id", Value: "1"}}
+			},
+			want: "@host = http://a\n# комментарий\n@id = 1\n\n# @folder Users\n\n",
+		},
+		{
+			name:     "переменные перед папками, если их не было",
+			preamble: "# API\n\n# @folder Users\n# @base_url /users\n\n",
+			edit:     func(c *models.Collection) { c.Variables = []models.Variable{{Key: "host", Value: "http://a"}} },
+			want:     "# API\n\n@host = http://a\n# @folder Users\n# @base_url /users\n\n",
+		},
+		{
+			name:     "папки объявляются на месте первой папки",
+			preamble: "@host = http://a\n\n# @folder Old\n\n# @folder Other\n\n",
+			edit: func(c *models.Collection) {
+				c.Folders = []*models.Folder{{Name: "New", Headers: []models.Header{{Key: "Accept", Value: "*/*"}}}}
+			},
+			want: "@host = http://a\n\n# @folder New\n# @header Accept: */*\n\n",
+		},
+		{
+			name:     "начало без объявлений дополняется",
+			preamble: "# Только комментарий\n\n",
+			edit: func(c *models.Collection) {
+				c.Variables = append(c.Variables, models.Variable{Key: "host", Value: "http://a"})
+				c.Folders = []*models.Folder{{Name: "Users"}}
+			},
+			want: "# Только комментарий\n\n@host = http://a\n# @folder Users\n\n",
+		},
+	} {
+		store := writeHTTPFile(t, tc.preamble+request)
+		collection, err := store.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tc.edit(&collection)
+		if err := store.Save(collection); err != nil {
+			t.Fatal(err)
+		}
+		if got := readHTTPFile(t, store); got != tc.want+request {
+			t.Errorf("%s:\n%s\nожидалось:\n%s", tc.name, got, tc.want+request)
+		}
+	}
+}
@@ -0,0 +1,470 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/KharpukhaevV/postui/models"
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIMethods перечисляет поддерживаемые операции в порядке импорта
+var openAPIMethods = []string{"get", "post", "put", "delete", "patch", "head", "options"}
+
+// maxExampleDepth ограничивает глубину генерации примеров для рекурсивных схем
+const maxExampleDepth = 8
+
+var openAPIPathParam = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// openAPIDoc оборачивает разобранный документ OpenAPI/Swagger
+type openAPIDoc struct {
+	root     map[string]interface{}
+	swagger2 bool
+	result   *ImportResult
+	// visiting содержит ссылки на схемы, для которых сейчас строится пример
+	visiting map[string]bool
+}
+
+// ImportOpenAPI создает по одному запросу на каждую операцию спецификации
+// OpenAPI 3 или Swagger 2 в формате YAML или JSON.
+// Адрес сервера сохраняется в переменную baseUrl.
+func ImportOpenAPI(data []byte) (ImportResult, error) {
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return ImportResult{}, fmt.Errorf("неверный формат спецификации: %w", err)
+	}
+
+	doc := &openAPIDoc{root: root, result: &ImportResult{}}
+	switch {
+	case strings.HasPrefix(specVersion(root["openapi"]), "3."):
+	case specVersion(root["swagger"]) == "2.0":
+		doc.swagger2 = true
+	default:
+		return ImportResult{}, fmt.Errorf("документ не является спецификацией OpenAPI 3 или Swagger 2")
+	}
+
+	doc.result.Name = str(mapAt(root, "info")["title"])
+	doc.result.Variables = []models.Variable{{Key: "baseUrl", Value: doc.baseURL()}}
+	if _, ok := root["security"]; ok {
+		doc.warn("спецификация", "требования безопасности не импортированы, настройте авторизацию вручную")
+	}
+
+	paths := mapAt(root, "paths")
+	for _, path := range sortedKeys(paths) {
+		item := doc.resolve(paths[path])
+		for _, method := range openAPIMethods {
+			if op, ok := item[method].(map[string]interface{}); ok {
+				doc.importOperation(path, method, item, op)
+			}
+		}
+		if _, ok := item["trace"]; ok {
+			doc.warn(path, "метод TRACE не поддерживается")
+		}
+	}
+	return *doc.result, nil
+}
+
+// baseURL возвращает адрес первого сервера спецификации
+func (d *openAPIDoc) baseURL() string {
+	if d.swagger2 {
+		host := str(d.root["host"])
+		if host == "" {
+			d.warn("спецификация", "host не указан, используется http://localhost")
+			host = "localhost"
+		}
+		scheme := "https"
+		if schemes, ok := d.root["schemes"].([]interface{}); ok && len(schemes) > 0 {
+			scheme = str(schemes[0])
+		}
+		return scheme + "://" + host + strings.TrimSuffix(str(d.root["basePath"]), "/")
+	}
+
+	servers, _ := d.root["servers"].([]interface{})
+	if len(servers) == 0 {
+		d.warn("спецификация", "servers не указаны, используется http://localhost")
+		return "http://localhost"
+	}
+	server, _ := servers[0].(map[string]interface{})
+	serverURL := str(server["url"])
+	// Переменные сервера заменяются значениями по умолчанию
+	for name, v := range mapAt(server, "variables") {
+		if variable, ok := v.(map[string]interface{}); ok {
+			serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", str(variable["default"]))
+		}
+	}
+	return strings.TrimSuffix(serverURL, "/")
+}
+
+func (d *openAPIDoc) importOperation(path, method string, item, op map[string]interface{}) {
+	name := str(op["operationId"])
+	if name == "" {
+		name = str(op["summary"])
+	}
+	if name == "" {
+		name = strings.ToUpper(method) + " " + path
+	}
+	// Операции с тегами помещаются в папки по первому тегу
+	label, folder := name, (*models.Folder)(nil)
+	if tags, ok := op["tags"].([]interface{}); ok && len(tags) > 0 {
+		label = str(tags[0]) + "/" + name
+		folder = d.tagFolder(str(tags[0]))
+	}
+
+	sr := models.SavedRequest{
+		Name:    name,
+		Method:  models.ParseMethod(method),
+		URL:     "{{baseUrl}}" + openAPIPathParam.ReplaceAllString(path, "{{$1}}"),
+		Headers: []models.Header{},
+		Params:  []models.Param{},
+	}
+
+	var formFields []models.FormField
+	multipart := false
+	for _, param := range d.parameters(item, op) {
+		paramName := str(param["name"])
+		value := d.parameterExample(param)
+		switch str(param["in"]) {
+		case "path":
+			if value != "" {
+				d.addVariable(paramName, value)
+			}
+		case "query":
+			sr.Params = append(sr.Params, models.Param{Key: paramName, Value: value})
+		case "header":
+			sr.Headers = append(sr.Headers, models.Header{Key: paramName, Value: value})
+		case "body":
+			d.setBody(&sr, d.swaggerContentType(op), d.exampleFromSchema(param["schema"], 0))
+		case "formData":
+			if str(param["type"]) == "file" {
+				// Путь к файлу указывается пользователем после импорта
+				multipart = true
+				formFields = append(formFields, models.FormField{Key: paramName, File: true})
+				continue
+			}
+			formFields = append(formFields, models.FormField{Key: paramName, Value: value})
+		case "cookie":
+			d.warn(label, "cookie-параметр %s не импортирован", paramName)
+		}
+	}
+	if len(formFields) > 0 {
+		multipart = multipart || strings.HasPrefix(d.swaggerContentType(op), "multipart/")
+		d.setFormBody(&sr, formFields, multipart)
+	}
+
+	if body := d.resolve(op["requestBody"]); body != nil {
+		d.importRequestBody(label, &sr, body)
+	}
+	if _, ok := op["security"]; ok {
+		d.warn(label, "требования безопасности не импортированы")
+	}
+
+	if folder != nil {
+		folder.Requests = append(folder.Requests, sr)
+	} else {
+		d.result.Requests = append(d.result.Requests, sr)
+	}
+}
+
+// tagFolder возвращает папку для операций с тегом, создавая ее при первом обращении
+func (d *openAPIDoc) tagFolder(tag string) *models.Folder {
+	for _, folder := range d.result.Folders {
+		if folder.Name == tag {
+			return folder
+		}
+	}
+	folder := &models.Folder{Name: tag}
+	d.result.Folders = append(d.result.Folders, folder)
+	return folder
+}
+
+// parameters объединяет параметры пути и операции (параметры операции имеют приоритет)
+func (d *openAPIDoc) parameters(item, op map[string]interface{}) []map[string]interface{} {
+	var params []map[string]interface{}
+	index := map[string]int{}
+	for _, source := range []interface{}{item["parameters"], op["parameters"]} {
+		list, _ := source.([]interface{})
+		for _, raw := range list {
+			param := d.resolve(raw)
+			if param == nil {
+				continue
+			}
+			key := str(param["in"]) + ":" + str(param["name"])
+			if i, ok := index[key]; ok {
+				params[i] = param
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// parameterExample возвращает пример значения параметра
+func (d *openAPIDoc) parameterExample(param map[string]interface{}) string {
+	if example, ok := param["example"]; ok {
+		return str(example)
+	}
+	var value interface{}
+	if schema, ok := param["schema"]; ok {
+		value = d.exampleFromSchema(schema, 0)
+	} else {
+		// В Swagger 2 описание типа находится в самом параметре
+		value = d.exampleFromSchema(param, 0)
+	}
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return ""
+	}
+	return str(value)
+}
+
+// importRequestBody формирует тело запроса из requestBody OpenAPI 3
+func (d *openAPIDoc) importRequestBody(name string, sr *models.SavedRequest, body map[string]interface{}) {
+	content := mapAt(body, "content")
+	if len(content) == 0 {
+		return
+	}
+
+	contentType := "application/json"
+	if _, ok := content[contentType]; !ok {
+		contentType = sortedKeys(content)[0]
+	}
+	media := mapAt(content, contentType)
+
+	var example interface{}
+	if ex, ok := media["example"]; ok {
+		example = ex
+	} else if examples := mapAt(media, "examples"); len(examples) > 0 {
+		example = d.resolve(examples[sortedKeys(examples)[0]])["value"]
+	} else {
+		example = d.exampleFromSchema(media["schema"], 0)
+	}
+
+	switch {
+	case strings.Contains(contentType, "x-www-form-urlencoded"), strings.HasPrefix(contentType, "multipart/form-data"):
+		multipart := strings.HasPrefix(contentType, "multipart/")
+		properties := mapAt(d.resolve(media["schema"]), "properties")
+		obj, _ := example.(map[string]interface{})
+		var fields []models.FormField
+		for _, key := range sortedKeys(obj) {
+			field := models.FormField{Key: key, Value: str(obj[key])}
+			if format := str(d.resolve(properties[key])["format"]); multipart && (format == "binary" || format == "base64") {
+				// Путь к файлу указывается пользователем после импорта
+				field = models.FormField{Key: key, File: true}
+			}
+			fields = append(fields, field)
+		}
+		d.setFormBody(sr, fields, multipart)
+	case strings.HasPrefix(contentType, "multipart/"):
+		d.warn(name, "тело %s не поддерживается", contentType)
+	default:
+		d.setBody(sr, contentType, example)
+	}
+}
+
+func (d *openAPIDoc) setBody(sr *models.SavedRequest, contentType string, example interface{}) {
+	if example == nil {
+		return
+	}
+	if s, ok := example.(string); ok && !strings.Contains(contentType, "json") {
+		sr.Body = s
+	} else if data, err := json.MarshalIndent(example, "", "  "); err == nil {
+		sr.Body = string(data)
+	}
+	sr.Headers = append(sr.Headers, models.Header{Key: "Content-Type", Value: contentType})
+}
+
+// setFormBody задает тело формы; Content-Type формируется при отправке по типу тела
+func (d *openAPIDoc) setFormBody(sr *models.SavedRequest, fields []models.FormField, multipart bool) {
+	sr.BodyType = models.BodyForm
+	if multipart {
+		sr.BodyType = models.BodyMultipart
+	}
+	sr.Form = fields
+}
+
+// swaggerContentType возвращает тип содержимого операции Swagger 2
+func (d *openAPIDoc) swaggerContentType(op map[string]interface{}) string {
+	for _, source := range []interface{}{op["consumes"], d.root["consumes"]} {
+		if list, ok := source.([]interface{}); ok && len(list) > 0 {
+			return str(list[0])
+		}
+	}
+	return "application/json"
+}
+
+// exampleFromSchema строит пример значения по JSON-схеме.
+// Рекурсивные ссылки на схему, которая уже строится, пропускаются.
+func (d *openAPIDoc) exampleFromSchema(raw interface{}, depth int) interface{} {
+	if ref, ok := mapValue(raw, "$ref").(string); ok {
+		if d.visiting[ref] {
+			return nil
+		}
+		if d.visiting == nil {
+			d.visiting = map[string]bool{}
+		}
+		d.visiting[ref] = true
+		defer delete(d.visiting, ref)
+	}
+
+	schema := d.resolve(raw)
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+	for _, key := range []string{"example", "default"} {
+		if v, ok := schema[key]; ok {
+			return v
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		merged := map[string]interface{}{}
+		for _, part := range all {
+			if obj, ok := d.exampleFromSchema(part, depth+1).(map[string]interface{}); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if variants, ok := schema[key].([]interface{}); ok && len(variants) > 0 {
+			return d.exampleFromSchema(variants[0], depth+1)
+		}
+	}
+
+	schemaType := str(schema["type"])
+	if schemaType == "" && schema["properties"] != nil {
+		schemaType = "object"
+	}
+	switch schemaType {
+	case "object":
+		obj := map[string]interface{}{}
+		for name, prop := range mapAt(schema, "properties") {
+			if value := d.exampleFromSchema(prop, depth+1); value != nil {
+				obj[name] = value
+			}
+		}
+		return obj
+	case "array":
+		item := d.exampleFromSchema(schema["items"], depth+1)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "integer":
+		return 0
+	case "number":
+		return 0.0
+	case "boolean":
+		return true
+	case "string":
+		switch str(schema["format"]) {
+		case "date-time":
+			return "2024-01-01T00:00:00Z"
+		case "date":
+			return "2024-01-01"
+		case "email":
+			return "user@example.com"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		case "uri", "url":
+			return "https://example.com"
+		}
+		return "string"
+	}
+	return nil
+}
+
+// resolve разыменовывает локальные ссылки $ref
+func (d *openAPIDoc) resolve(raw interface{}) map[string]interface{} {
+	node, _ := raw.(map[string]interface{})
+	for i := 0; node != nil && i < maxExampleDepth; i++ {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			d.warn("спецификация", "внешняя ссылка %s не поддерживается", ref)
+			return nil
+		}
+		var current interface{} = d.root
+		for _, part := range strings.Split(ref[2:], "/") {
+			part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+			current = mapAt(current, part)
+		}
+		node, _ = current.(map[string]interface{})
+	}
+	return node
+}
+
+func (d *openAPIDoc) addVariable(key, value string) {
+	for _, v := range d.result.Variables {
+		if v.Key == key {
+			return
+		}
+	}
+	d.result.Variables = append(d.result.Variables, models.Variable{Key: key, Value: value})
+}
+
+func (d *openAPIDoc) warn(context, format string, args ...interface{}) {
+	message := context + ": " + fmt.Sprintf(format, args...)
+	for _, w := range d.result.Warnings {
+		if w == message {
+			return
+		}
+	}
+	d.result.Warnings = append(d.result.Warnings, message)
+}
+
+// --- Вспомогательные функции ---
+
+func mapAt(node interface{}, key string) map[string]interface{} {
+	child, _ := mapValue(node, key).(map[string]interface{})
+	return child
+}
+
+func mapValue(node interface{}, key string) interface{} {
+	m, _ := node.(map[string]interface{})
+	return m[key]
+}
+
+func str(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// specVersion возвращает версию спецификации. Версию без кавычек (swagger: 2.0)
+// YAML разбирает как число.
+func specVersion(v interface{}) string {
+	switch n := v.(type) {
+	case float64:
+		if n == math.Trunc(n) {
+			return strconv.FormatFloat(n, 'f', 1, 64)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(n) + ".0"
+	}
+	return str(v)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,197 @@
+package converter
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+func importOpenAPIFixture(t *testing.T, name string) (ImportResult, *models.Collection) {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ImportOpenAPI(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, &models.Collection{Folder: models.Folder{Folders: result.Folders, Requests: result.Requests}}
+}
+
+func TestOpenAPIVersion(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		ok     bool
+	}{
+		// Версия без кавычек разбирается YAML как число или строка
+		{"swagger: 2.0", true},
+		{"swagger: '2.0'", true},
+		{"openapi: 3.0", true},
+		{"openapi: 3.0.3", true},
+		{"openapi: 3.1", true},
+		{`openapi: "3.1.0"`, true},
+		{"openapi: 3", true},
+		{"swagger: 1.2", false},
+		{"openapi: 2.0", false},
+		{"title: none", false},
+	} {
+		_, err := ImportOpenAPI([]byte(tc.header + "\ninfo: {title: x}\nhost: x\nservers: [{url: 'http://x'}]\npaths: {}\n"))
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: ошибка %v", tc.header, err)
+		}
+	}
+
+	// JSON разбирается тем же разборщиком
+	result, err := ImportOpenAPI([]byte(`{"openapi": "3.0.0", "info": {"title": "J"}, "servers": [{"url": "http://j"}], "paths": {}}`))
+	if err != nil || result.Name != "J" {
+		t.Errorf("JSON: %+v, %v", result, err)
+	}
+}
+
+func TestImportOpenAPI3(t *testing.T) {
+	result, collection := importOpenAPIFixture(t, "openapi3.yaml")
+	if result.Name != "Pets" {
+		t.Errorf("имя = %q", result.Name)
+	}
+
+	// Первый сервер с подставленными значениями переменных; пример пути - из первой операции
+	wantVars := []models.Variable{{Key: "baseUrl", Value: "https://api.example.com/v1"}, {Key: "petId", Value: "7"}}
+	if !reflect.DeepEqual(result.Variables, wantVars) {
+		t.Errorf("переменные = %+v, ожидалось %+v", result.Variables, wantVars)
+	}
+
+	var names []string
+	for _, sr := range collection.Resolved() {
+		names = append(names, models.MethodNames[sr.Method]+" "+sr.Name+" "+sr.URL)
+	}
+	wantNames := []string{
+		"GET pets/listPets {{baseUrl}}/pets",
+		"POST pets/createPet {{baseUrl}}/pets",
+		"PUT pets/Update pet {{baseUrl}}/pets/{{petId}}",
+		"POST login {{baseUrl}}/login",
+		"POST addNote {{baseUrl}}/notes",
+		"DELETE deletePet {{baseUrl}}/pets/{{petId}}",
+		"POST uploadPhoto {{baseUrl}}/pets/{{petId}}/photo",
+	}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("запросы:\n%s\nожидалось:\n%s", strings.Join(names, "\n"), strings.Join(wantNames, "\n"))
+	}
+
+	find := func(path string) models.SavedRequest {
+		t.Helper()
+		sr, err := collection.FindRequest(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sr
+	}
+
+	// Параметры пути объединяются с параметрами операции; значения - из example, default и enum
+	list := find("pets/listPets")
+	requestID := models.Header{Key: "X-Request-Id", Value: "00000000-0000-0000-0000-000000000000"}
+	if !reflect.DeepEqual(list.Headers, []models.Header{requestID}) {
+		t.Errorf("listPets: заголовки = %+v", list.Headers)
+	}
+	if want := []models.Param{{Key: "limit", Value: "20"}, {Key: "status", Value: "available"}}; !reflect.DeepEqual(list.Params, want) {
+		t.Errorf("listPets: параметры = %+v", list.Params)
+	}
+
+	// Пример тела строится по схеме, рекурсивная ссылка пропускается
+	create := find("pets/createPet")
+	wantBody := "{\n  \"born\": \"2024-01-01\",\n  \"id\": 0,\n  \"name\": \"Rex\",\n  \"tags\": [\n    \"string\"\n  ]\n}"
+	if create.Body != wantBody {
+		t.Errorf("createPet: тело\n%s\nожидалось\n%s", create.Body, wantBody)
+	}
+	if want := []models.Header{requestID, {Key: "Content-Type", Value: "application/json"}}; !reflect.DeepEqual(create.Headers, want) {
+		t.Errorf("createPet: заголовки = %+v", create.Headers)
+	}
+
+	update := find("pets/Update pet")
+	if update.Body != "{\n  \"name\": \"Rex\",\n  \"tag\": \"dog\"\n}" {
+		t.Errorf("Update pet: тело из examples = %q", update.Body)
+	}
+
+	login := find("login")
+	wantForm := []models.FormField{{Key: "password", Value: "secret"}, {Key: "user", Value: "bob"}}
+	if login.BodyType != models.BodyForm || !reflect.DeepEqual(login.Form, wantForm) {
+		t.Errorf("login: тело %q %+v", login.BodyType, login.Form)
+	}
+
+	note := find("addNote")
+	if note.Body != "hello" || !reflect.DeepEqual(note.Headers, []models.Header{{Key: "Content-Type", Value: "text/plain"}}) {
+		t.Errorf("addNote: тело %q, заголовки %+v", note.Body, note.Headers)
+	}
+
+	photo := find("uploadPhoto")
+	wantForm = []models.FormField{{Key: "caption", Value: "hello"}, {Key: "file", File: true}}
+	if photo.BodyType != models.BodyMultipart || !reflect.DeepEqual(photo.Form, wantForm) {
+		t.Errorf("uploadPhoto: тело %q %+v", photo.BodyType, photo.Form)
+	}
+
+	wantWarnings := []string{
+		"pets/listPets: cookie-параметр session не импортирован",
+		"deletePet: требования безопасности не импортированы",
+	}
+	if !reflect.DeepEqual(result.Warnings, wantWarnings) {
+		t.Errorf("предупреждения = %q, ожидалось %q", result.Warnings, wantWarnings)
+	}
+}
+
+func TestImportSwagger2(t *testing.T) {
+	result, collection := importOpenAPIFixture(t, "swagger2.yaml")
+
+	// Адрес из первой схемы, host и basePath без завершающего слеша
+	wantVars := []models.Variable{{Key: "baseUrl", Value: "http://store.example.com/api"}, {Key: "id", Value: "5"}}
+	if !reflect.DeepEqual(result.Variables, wantVars) {
+		t.Errorf("переменные = %+v, ожидалось %+v", result.Variables, wantVars)
+	}
+
+	create, err := collection.FindRequest("orders/createOrder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if create.Method != models.MethodPOST || create.URL != "{{baseUrl}}/orders" {
+		t.Errorf("createOrder: %s %s", models.MethodNames[create.Method], create.URL)
+	}
+	if create.Body != "{\n  \"email\": \"user@example.com\",\n  \"qty\": 2\n}" {
+		t.Errorf("createOrder: тело из параметра body = %q", create.Body)
+	}
+	if want := []models.Header{{Key: "Content-Type", Value: "application/json"}}; !reflect.DeepEqual(create.Headers, want) {
+		t.Errorf("createOrder: заголовки = %+v", create.Headers)
+	}
+
+	get, err := collection.FindRequest("orders/getOrder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if get.URL != "{{baseUrl}}/orders/{{id}}" ||
+		!reflect.DeepEqual(get.Params, []models.Param{{Key: "expand", Value: "items"}}) ||
+		!reflect.DeepEqual(get.Headers, []models.Header{{Key: "X-Tenant", Value: "acme"}}) {
+		t.Errorf("getOrder = %+v", get)
+	}
+
+	upload, err := collection.FindRequest("upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantForm := []models.FormField{{Key: "note", Value: "hi"}, {Key: "file", File: true}}
+	if upload.BodyType != models.BodyMultipart || !reflect.DeepEqual(upload.Form, wantForm) {
+		t.Errorf("upload: тело %q %+v", upload.BodyType, upload.Form)
+	}
+
+	submit, err := collection.FindRequest("submit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if submit.BodyType != models.BodyForm || !reflect.DeepEqual(submit.Form, []models.FormField{{Key: "a", Value: "x"}}) {
+		t.Errorf("submit: тело %q %+v", submit.BodyType, submit.Form)
+	}
+
+	if want := []string{"/forms: метод TRACE не поддерживается"}; !reflect.DeepEqual(result.Warnings, want) {
+		t.Errorf("предупреждения = %q, ожидалось %q", result.Warnings, want)
+	}
+}
@@ -0,0 +1,489 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// --- Структуры коллекции Postman v2.1 ---
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+	Auth     json.RawMessage   `json:"auth,omitempty"`
+	Event    json.RawMessage   `json:"event,omitempty"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item,omitempty"`
+	Request *postmanRequest `json:"request,omitempty"`
+	Event   json.RawMessage `json:"event,omitempty"`
+	Auth    json.RawMessage `json:"auth,omitempty"`
+}
+
+type postmanRequest struct {
+	Method string          `json:"method"`
+	Header postmanHeaders  `json:"header"`
+	URL    postmanURL      `json:"url"`
+	Body   *postmanBody    `json:"body,omitempty"`
+	Auth   json.RawMessage `json:"auth,omitempty"`
+}
+
+type postmanKV struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Src         any    `json:"src,omitempty"` // путь к файлу поля formdata: строка или массив строк
+	ContentType string `json:"contentType,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+type postmanVariable struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Disabled bool        `json:"disabled,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string      `json:"raw"`
+	Query    []postmanKV `json:"query,omitempty"`
+	Variable []postmanKV `json:"variable,omitempty"`
+}
+
+type postmanBody struct {
+	Mode       string          `json:"mode"`
+	Raw        string          `json:"raw,omitempty"`
+	URLEncoded []postmanKV     `json:"urlencoded,omitempty"`
+	FormData   []postmanKV     `json:"formdata,omitempty"`
+	File       *postmanFile    `json:"file,omitempty"`
+	Options    json.RawMessage `json:"options,omitempty"`
+}
+
+type postmanFile struct {
+	Src string `json:"src"`
+}
+
+type postmanHeaders []postmanKV
+
+// UnmarshalJSON поддерживает заголовки, заданные строкой "Key: Value" на каждой строке
+func (h *postmanHeaders) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*h = nil
+		for _, line := range strings.Split(raw, "\n") {
+			if parts := strings.SplitN(line, ":", 2); len(parts) == 2 {
+				*h = append(*h, postmanKV{Key: strings.TrimSpace(parts[0]), Value: strings.TrimSpace(parts[1])})
+			}
+		}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]postmanKV)(h))
+}
+
+// UnmarshalJSON поддерживает запрос, заданный строкой URL
+func (r *postmanRequest) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*r = postmanRequest{Method: "GET", URL: postmanURL{Raw: raw}}
+		return nil
+	}
+	type plain postmanRequest
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+// UnmarshalJSON поддерживает URL, заданный строкой
+func (u *postmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*u = postmanURL{Raw: raw}
+		return nil
+	}
+	type plain postmanURL
+	return json.Unmarshal(data, (*plain)(u))
+}
+
+// --- Импорт ---
+
+var postmanPathVariable = regexp.MustCompile(`/:([A-Za-z0-9_]+)`)
+
+// ImportPostman преобразует коллекцию Postman v2.1 в сохраненные запросы.
+// Переменные коллекции и пути возвращаются как переменные окружения.
+func ImportPostman(data []byte) (ImportResult, error) {
+	var collection postmanCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return ImportResult{}, fmt.Errorf("неверный формат коллекции Postman: %w", err)
+	}
+	if collection.Info.Schema != "" && !strings.Contains(collection.Info.Schema, "v2.1") && !strings.Contains(collection.Info.Schema, "v2.0") {
+		return ImportResult{}, fmt.Errorf("неподдерживаемая схема коллекции: %s", collection.Info.Schema)
+	}
+
+	result := ImportResult{Name: collection.Info.Name}
+	for _, v := range collection.Variable {
+		if v.Disabled {
+			continue
+		}
+		result.Variables = append(result.Variables, models.Variable{Key: v.Key, Value: fmt.Sprint(v.Value)})
+	}
+	auth, err := importPostmanAuth(collection.Auth, models.Auth{})
+	if err != nil {
+		result.Warnings = append(result.Warnings, "коллекция: "+err.Error())
+	}
+	if len(collection.Event) > 0 && string(collection.Event) != "null" {
+		result.Warnings = append(result.Warnings, "коллекция: скрипты не импортированы")
+	}
+
+	root := &models.Folder{}
+	importPostmanItems(collection.Item, root, "", auth, &result)
+	result.Requests, result.Folders = root.Requests, root.Folders
+	return result, nil
+}
+
+// importPostmanItems переносит элементы Postman в папку: папки Postman становятся вложенными папками.
+// Авторизация папок Postman передается запросам, у которых нет своей.
+func importPostmanItems(items []postmanItem, folder *models.Folder, path string, auth models.Auth, result *ImportResult) {
+	for _, item := range items {
+		itemPath := item.Name
+		if path != "" {
+			itemPath = path + "/" + item.Name
+		}
+		if item.Request == nil {
+			child := &models.Folder{Name: item.Name}
+			childAuth, err := importPostmanAuth(item.Auth, auth)
+			if err != nil {
+				result.Warnings = append(result.Warnings, itemPath+": "+err.Error())
+			}
+			importPostmanItems(item.Item, child, itemPath, childAuth, result)
+			folder.Folders = append(folder.Folders, child)
+			continue
+		}
+		folder.Requests = append(folder.Requests, importPostmanRequest(itemPath, item, auth, result))
+	}
+}
+
+func importPostmanRequest(path string, item postmanItem, auth models.Auth, result *ImportResult) models.SavedRequest {
+	req := item.Request
+	warn := func(format string, args ...interface{}) {
+		result.Warnings = append(result.Warnings, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	sr := models.SavedRequest{
+		Name:    item.Name,
+		Method:  models.ParseMethod(req.Method),
+		Headers: []models.Header{},
+		Params:  []models.Param{},
+	}
+	if req.Method != "" && !strings.EqualFold(models.MethodNames[sr.Method], req.Method) {
+		warn("метод %s не поддерживается, используется GET", req.Method)
+	}
+
+	// Параметры берем из структурированного списка, если он есть, иначе из строки URL
+	var params []models.Param
+	sr.URL, params = splitQuery(req.URL.Raw)
+	if req.URL.Query != nil {
+		params = nil
+		for _, q := range req.URL.Query {
+			if q.Disabled {
+				warn("отключенный параметр %s пропущен", q.Key)
+				continue
+			}
+			params = append(params, models.Param{Key: q.Key, Value: q.Value})
+		}
+	}
+	sr.Params = append(sr.Params, params...)
+
+	// Переменные пути :id превращаются в плейсхолдеры {{id}}
+	sr.URL = postmanPathVariable.ReplaceAllString(sr.URL, "/{{$1}}")
+	for _, v := range req.URL.Variable {
+		result.Variables = append(result.Variables, models.Variable{Key: v.Key, Value: v.Value})
+	}
+
+	for _, h := range req.Header {
+		if h.Disabled {
+			warn("отключенный заголовок %s пропущен", h.Key)
+			continue
+		}
+		sr.Headers = append(sr.Headers, models.Header{Key: h.Key, Value: h.Value})
+	}
+
+	if req.Body != nil {
+		switch req.Body.Mode {
+		case "raw":
+			sr.Body = req.Body.Raw
+			if !hasHeader(sr.Headers, "Content-Type") && strings.Contains(string(req.Body.Options), `"json"`) {
+				sr.Headers = append(sr.Headers, models.Header{Key: "Content-Type", Value: "application/json"})
+			}
+		case "urlencoded":
+			sr.BodyType = models.BodyForm
+			for _, kv := range req.Body.URLEncoded {
+				if kv.Disabled {
+					warn("отключенное поле формы %s пропущено", kv.Key)
+					continue
+				}
+				sr.Form = append(sr.Form, models.FormField{Key: kv.Key, Value: kv.Value})
+			}
+		case "formdata":
+			sr.BodyType = models.BodyMultipart
+			for _, kv := range req.Body.FormData {
+				if kv.Disabled {
+					warn("отключенное поле формы %s пропущено", kv.Key)
+					continue
+				}
+				field := models.FormField{Key: kv.Key, Value: kv.Value}
+				if kv.Type == "file" {
+					field = models.FormField{Key: kv.Key, Value: postmanSrc(kv.Src), File: true, ContentType: kv.ContentType}
+				}
+				sr.Form = append(sr.Form, field)
+			}
+		case "file":
+			sr.BodyType = models.BodyBinary
+			if req.Body.File != nil {
+				sr.Body = req.Body.File.Src
+			}
+		case "":
+		default:
+			warn("тело в режиме %s не поддерживается", req.Body.Mode)
+		}
+	}
+
+	auth, err := importPostmanAuth(req.Auth, auth)
+	if err != nil {
+		warn("%s", err)
+	}
+	sr.Auth = auth
+	if len(item.Event) > 0 && string(item.Event) != "null" {
+		warn("скрипты не импортированы")
+	}
+	return sr
+}
+
+// postmanSrc возвращает путь к файлу поля formdata (Postman допускает несколько файлов, берется первый)
+func postmanSrc(src any) string {
+	switch v := src.(type) {
+	case string:
+		return v
+	case []any:
+		if len(v) > 0 {
+			return fmt.Sprint(v[0])
+		}
+	}
+	return ""
+}
+
+// --- Экспорт ---
+
+// ExportPostman преобразует коллекцию в коллекцию Postman v2.1. Папки сохраняются,
+// а базовый URL и заголовки папок подставляются в запросы, так как в Postman их нет.
+func ExportPostman(source models.Collection, vars []models.Variable) ([]byte, error) {
+	collection := postmanCollection{
+		Info: postmanInfo{Name: source.Name, Schema: postmanSchema},
+	}
+	for _, v := range vars {
+		collection.Variable = append(collection.Variable, postmanVariable{Key: v.Key, Value: v.Value})
+	}
+	collection.Item = exportPostmanFolder(&source.Folder, models.RequestDefaults{})
+	return json.MarshalIndent(collection, "", "\t")
+}
+
+func exportPostmanFolder(folder *models.Folder, defaults models.RequestDefaults) []postmanItem {
+	defaults = defaults.Inherit(folder)
+	items := []postmanItem{}
+	for _, child := range folder.Folders {
+		items = append(items, postmanItem{Name: child.Name, Item: exportPostmanFolder(child, defaults)})
+	}
+	for _, sr := range folder.Requests {
+		items = append(items, exportPostmanItem(defaults.Apply(sr)))
+	}
+	return items
+}
+
+func exportPostmanItem(sr models.SavedRequest) postmanItem {
+	req := &postmanRequest{
+		Method: models.MethodNames[sr.Method],
+		Header: postmanHeaders{},
+		URL:    postmanURL{Raw: sr.URL},
+	}
+	for _, h := range sr.Headers {
+		req.Header = append(req.Header, postmanKV{Key: h.Key, Value: h.Value})
+	}
+
+	if len(sr.Params) > 0 {
+		pairs := make([]string, len(sr.Params))
+		for i, p := range sr.Params {
+			req.URL.Query = append(req.URL.Query, postmanKV{Key: p.Key, Value: p.Value})
+			pairs[i] = p.Key + "=" + p.Value
+		}
+		separator := "?"
+		if strings.Contains(sr.URL, "?") {
+			separator = "&"
+		}
+		req.URL.Raw += separator + strings.Join(pairs, "&")
+	}
+
+	switch sr.BodyType {
+	case models.BodyNone:
+	case models.BodyForm:
+		req.Body = &postmanBody{Mode: "urlencoded", URLEncoded: []postmanKV{}}
+		for _, f := range sr.Form {
+			req.Body.URLEncoded = append(req.Body.URLEncoded, postmanKV{Key: f.Key, Value: f.Value})
+		}
+	case models.BodyMultipart:
+		req.Body = &postmanBody{Mode: "formdata", FormData: []postmanKV{}}
+		for _, f := range sr.Form {
+			kv := postmanKV{Key: f.Key, Value: f.Value, Type: "text"}
+			if f.File {
+				kv = postmanKV{Key: f.Key, Type: "file", Src: f.Value, ContentType: f.ContentType}
+			}
+			req.Body.FormData = append(req.Body.FormData, kv)
+		}
+	case models.BodyBinary:
+		req.Body = &postmanBody{Mode: "file", File: &postmanFile{Src: sr.Body}}
+	default:
+		if sr.Body == "" {
+			break
+		}
+		if strings.Contains(headerValue(sr.Headers, "Content-Type"), "x-www-form-urlencoded") {
+			_, params := splitQuery("?" + sr.Body)
+			req.Body = &postmanBody{Mode: "urlencoded"}
+			for _, p := range params {
+				req.Body.URLEncoded = append(req.Body.URLEncoded, postmanKV{Key: p.Key, Value: p.Value})
+			}
+		} else {
+			req.Body = &postmanBody{Mode: "raw", Raw: sr.Body}
+			if sr.BodyType == models.BodyJSON || strings.Contains(headerValue(sr.Headers, "Content-Type"), "json") {
+				req.Body.Options = json.RawMessage(`{"raw":{"language":"json"}}`)
+			}
+		}
+	}
+
+	req.Auth = exportPostmanAuth(sr.Auth)
+	return postmanItem{Name: sr.Name, Request: req}
+}
+
+// --- Авторизация ---
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Basic  []postmanVariable `json:"basic,omitempty"`
+	Bearer []postmanVariable `json:"bearer,omitempty"`
+	APIKey []postmanVariable `json:"apikey,omitempty"`
+	Digest []postmanVariable `json:"digest,omitempty"`
+	OAuth2 []postmanVariable `json:"oauth2,omitempty"`
+}
+
+// importPostmanAuth преобразует авторизацию Postman. Если она не задана,
+// возвращается унаследованная от родителя авторизация.
+func importPostmanAuth(raw json.RawMessage, inherited models.Auth) (models.Auth, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return inherited, nil
+	}
+	var pa postmanAuth
+	if err := json.Unmarshal(raw, &pa); err != nil {
+		return inherited, fmt.Errorf("неверный формат авторизации: %w", err)
+	}
+	value := func(attrs []postmanVariable, key string) string {
+		for _, attr := range attrs {
+			if attr.Key == key && attr.Value != nil {
+				return fmt.Sprint(attr.Value)
+			}
+		}
+		return ""
+	}
+
+	switch pa.Type {
+	case "noauth":
+		return models.Auth{}, nil
+	case "basic":
+		return models.Auth{Type: models.AuthBasic, Username: value(pa.Basic, "username"), Password: value(pa.Basic, "password")}, nil
+	case "bearer":
+		return models.Auth{Type: models.AuthBearer, Token: value(pa.Bearer, "token")}, nil
+	case "apikey":
+		return models.Auth{Type: models.AuthAPIKey, Key: value(pa.APIKey, "key"), Value: value(pa.APIKey, "value"), In: value(pa.APIKey, "in")}, nil
+	case "digest":
+		return models.Auth{Type: models.AuthDigest, Username: value(pa.Digest, "username"), Password: value(pa.Digest, "password")}, nil
+	case "oauth2":
+		auth := models.Auth{
+			Type:         models.AuthOAuth2,
+			Grant:        models.GrantClientCredentials,
+			TokenURL:     value(pa.OAuth2, "accessTokenUrl"),
+			ClientID:     value(pa.OAuth2, "clientId"),
+			ClientSecret: value(pa.OAuth2, "clientSecret"),
+			Scope:        value(pa.OAuth2, "scope"),
+		}
+		switch value(pa.OAuth2, "grant_type") {
+		case "", "client_credentials":
+		case "password_credentials":
+			auth.Grant = models.GrantPassword
+			auth.Username = value(pa.OAuth2, "username")
+			auth.Password = value(pa.OAuth2, "password")
+		default:
+			return inherited, fmt.Errorf("авторизация OAuth2 с типом %s не поддерживается", value(pa.OAuth2, "grant_type"))
+		}
+		return auth, nil
+	}
+	return inherited, fmt.Errorf("авторизация %s не поддерживается", pa.Type)
+}
+
+func exportPostmanAuth(auth models.Auth) json.RawMessage {
+	attr := func(pairs ...string) []postmanVariable {
+		var attrs []postmanVariable
+		for i := 0; i+1 < len(pairs); i += 2 {
+			attrs = append(attrs, postmanVariable{Key: pairs[i], Value: pairs[i+1]})
+		}
+		return attrs
+	}
+
+	pa := postmanAuth{Type: string(auth.Type)}
+	switch auth.Type {
+	case models.AuthBasic:
+		pa.Basic = attr("username", auth.Username, "password", auth.Password)
+	case models.AuthBearer:
+		pa.Bearer = attr("token", auth.Token)
+	case models.AuthAPIKey:
+		in := auth.In
+		if in == "" {
+			in = models.APIKeyInHeader
+		}
+		pa.APIKey = attr("key", auth.Key, "value", auth.Value, "in", in)
+	case models.AuthDigest:
+		pa.Digest = attr("username", auth.Username, "password", auth.Password)
+	case models.AuthOAuth2:
+		grant := "client_credentials"
+		if auth.Grant == models.GrantPassword {
+			grant = "password_credentials"
+		}
+		pa.OAuth2 = attr("grant_type", grant, "accessTokenUrl", auth.TokenURL, "clientId", auth.ClientID,
+			"clientSecret", auth.ClientSecret, "scope", auth.Scope, "username", auth.Username, "password", auth.Password)
+	default:
+		return nil
+	}
+	data, _ := json.Marshal(pa)
+	return data
+}
+
+// --- Вспомогательные функции ---
+
+func hasHeader(headers []models.Header, key string) bool {
+	return headerValue(headers, key) != ""
+}
+
+func headerValue(headers []models.Header, key string) string {
+	for _, h := range headers {
+		if strings.EqualFold(h.Key, key) {
+			return h.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,201 @@
+package converter
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+func importPostmanFixture(t *testing.T) ImportResult {
+	t.Helper()
+	data, err := os.ReadFile("testdata/postman_collection.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ImportPostman(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// postmanFixtureCollection возвращает импортированные запросы как коллекцию с путями от корня
+func postmanFixtureCollection(result ImportResult) *models.Collection {
+	return &models.Collection{Folder: models.Folder{Name: result.Name, Folders: result.Folders, Requests: result.Requests}}
+}
+
+func TestImportPostmanFolders(t *testing.T) {
+	result := importPostmanFixture(t)
+	if result.Name != "Shop API" {
+		t.Errorf("имя коллекции = %q", result.Name)
+	}
+
+	var names []string
+	for _, sr := range postmanFixtureCollection(result).Resolved() {
+		names = append(names, sr.Name)
+	}
+	want := []string{
+		"Users/Admin/Delete user", "Users/Admin/Audit", "Users/List users",
+		"Login", "Upload avatar", "Create order", "Upload file", "Ping", "Query",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("запросы = %q\nожидалось %q", names, want)
+	}
+
+	wantVars := []models.Variable{
+		{Key: "baseUrl", Value: "https://shop.example.com/api"},
+		{Key: "retries", Value: "3"},
+		{Key: "id", Value: "42"},
+		{Key: "session", Value: "all"},
+	}
+	if !reflect.DeepEqual(result.Variables, wantVars) {
+		t.Errorf("переменные = %+v\nожидалось %+v", result.Variables, wantVars)
+	}
+}
+
+func TestImportPostmanRequests(t *testing.T) {
+	result := importPostmanFixture(t)
+	collection := postmanFixtureCollection(result)
+	find := func(path string) models.SavedRequest {
+		t.Helper()
+		sr, err := collection.FindRequest(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sr
+	}
+
+	list := find("Users/List users")
+	if list.Method != models.MethodGET || list.URL != "{{baseUrl}}/users" {
+		t.Errorf("List users: %s %s", models.MethodNames[list.Method], list.URL)
+	}
+	if want := []models.Param{{Key: "page", Value: "1"}, {Key: "size", Value: "10"}}; !reflect.DeepEqual(list.Params, want) {
+		t.Errorf("List users: параметры = %+v", list.Params)
+	}
+	if want := []models.Header{{Key: "Accept", Value: "application/json"}}; !reflect.DeepEqual(list.Headers, want) {
+		t.Errorf("List users: заголовки = %+v", list.Headers)
+	}
+
+	remove := find("Users/Admin/Delete user")
+	if remove.Method != models.MethodDELETE || remove.URL != "{{baseUrl}}/users/{{id}}/sessions/{{session}}" {
+		t.Errorf("Delete user: %s %s", models.MethodNames[remove.Method], remove.URL)
+	}
+
+	audit := find("Users/Admin/Audit")
+	wantHeaders := []models.Header{{Key: "X-Audit", Value: "yes"}, {Key: "X-Trace", Value: "abc"}}
+	if audit.URL != "{{baseUrl}}/audit" || !reflect.DeepEqual(audit.Headers, wantHeaders) ||
+		!reflect.DeepEqual(audit.Params, []models.Param{{Key: "from", Value: "2024-01-01"}}) {
+		t.Errorf("Audit = %+v", audit)
+	}
+
+	login := find("Login")
+	if login.BodyType != models.BodyForm || !reflect.DeepEqual(login.Form, []models.FormField{{Key: "username", Value: "bob"}}) {
+		t.Errorf("Login: тело %q %+v", login.BodyType, login.Form)
+	}
+
+	upload := find("Upload avatar")
+	wantForm := []models.FormField{
+		{Key: "title", Value: "me"},
+		{Key: "avatar", Value: "/tmp/me.png", File: true, ContentType: "image/png"},
+	}
+	if upload.Method != models.MethodPUT || upload.BodyType != models.BodyMultipart || !reflect.DeepEqual(upload.Form, wantForm) {
+		t.Errorf("Upload avatar: %s, тело %q %+v", models.MethodNames[upload.Method], upload.BodyType, upload.Form)
+	}
+
+	create := find("Create order")
+	if create.Body != `{"item": 1}` || !reflect.DeepEqual(create.Headers, []models.Header{{Key: "Content-Type", Value: "application/json"}}) {
+		t.Errorf("Create order: тело %q, заголовки %+v", create.Body, create.Headers)
+	}
+
+	file := find("Upload file")
+	if file.BodyType != models.BodyBinary || file.Body != "/tmp/data.bin" {
+		t.Errorf("Upload file: тело %q %q", file.BodyType, file.Body)
+	}
+
+	ping := find("Ping")
+	if ping.Method != models.MethodGET || ping.URL != "https://shop.example.com/ping" {
+		t.Errorf("Ping: %s %s", models.MethodNames[ping.Method], ping.URL)
+	}
+}
+
+func TestImportPostmanAuth(t *testing.T) {
+	result := importPostmanFixture(t)
+	collection := postmanFixtureCollection(result)
+	bearer := models.Auth{Type: models.AuthBearer, Token: "{{token}}"}
+
+	for path, want := range map[string]models.Auth{
+		// Авторизация папки наследуется, noauth вложенной папки ее отменяет
+		"Users/List users":        {Type: models.AuthBasic, Username: "admin", Password: "p;ss"},
+		"Users/Admin/Delete user": {},
+		"Users/Admin/Audit":       {Type: models.AuthAPIKey, Key: "api_key", Value: "{{apiKey}}", In: "query"},
+		"Login": {
+			Type: models.AuthOAuth2, Grant: models.GrantPassword, TokenURL: "https://auth.example.com/token",
+			ClientID: "shop", ClientSecret: "s3cret", Username: "bob", Password: "hunter2", Scope: "read write",
+		},
+		"Upload avatar": {Type: models.AuthDigest, Username: "alice", Password: "secret"},
+		"Create order":  bearer,
+		// Неподдерживаемая авторизация заменяется унаследованной
+		"Query": bearer,
+	} {
+		sr, err := collection.FindRequest(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sr.Auth != want {
+			t.Errorf("%s: авторизация = %+v, ожидалась %+v", path, sr.Auth, want)
+		}
+	}
+}
+
+func TestImportPostmanWarnings(t *testing.T) {
+	result := importPostmanFixture(t)
+	want := []string{
+		"коллекция: скрипты не импортированы",
+		"Users/List users: отключенный параметр debug пропущен",
+		"Users/List users: отключенный заголовок X-Debug пропущен",
+		"Users/Admin/Delete user: скрипты не импортированы",
+		"Login: отключенное поле формы remember пропущено",
+		"Upload avatar: отключенное поле формы draft пропущено",
+		"Query: метод TRACE не поддерживается, используется GET",
+		"Query: тело в режиме graphql не поддерживается",
+		"Query: авторизация hawk не поддерживается",
+	}
+	if !reflect.DeepEqual(result.Warnings, want) {
+		t.Errorf("предупреждения:\n%s\nожидалось:\n%s", strings.Join(result.Warnings, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestImportPostmanSchema(t *testing.T) {
+	_, err := ImportPostman([]byte(`{"info": {"name": "x", "schema": "https://schema.getpostman.com/json/collection/v1.0.0/collection.json"}, "item": []}`))
+	if err == nil || !strings.Contains(err.Error(), "неподдерживаемая схема") {
+		t.Errorf("ошибка = %v, ожидалась неподдерживаемая схема", err)
+	}
+	if _, err := ImportPostman([]byte(`{"item": 1}`)); err == nil {
+		t.Error("ожидалась ошибка формата коллекции")
+	}
+}
+
+func TestPostmanRoundTrip(t *testing.T) {
+	result := importPostmanFixture(t)
+	// Экспортируются только поддерживаемые данные, поэтому повторный импорт дает те же запросы
+	data, err := ExportPostman(*postmanFixtureCollection(result), result.Variables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := ImportPostman(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Warnings) != 0 {
+		t.Errorf("предупреждения повторного импорта: %q", again.Warnings)
+	}
+	if !reflect.DeepEqual(again.Folder(), result.Folder()) {
+		t.Errorf("коллекция изменилась после экспорта и импорта:\n%s", data)
+	}
+	if !reflect.DeepEqual(again.Variables, result.Variables) {
+		t.Errorf("переменные = %+v, ожидалось %+v", again.Variables, result.Variables)
+	}
+}
@@ -0,0 +1,363 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// --- Отчет JUnit XML ---
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	ID        int             `xml:"id,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Time      string         `xml:"time,attr"`
+	Failures  []junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem  `xml:"error,omitempty"`
+	SystemOut string         `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ExportJUnit преобразует итоги запуска в отчет JUnit XML. Каждая итерация
+// становится набором тестов, каждый запрос - тестом, непройденные проверки
+// и код ответа из fail_on - элементами failure, ошибка выполнения - error.
+func ExportJUnit(report models.RunReport) ([]byte, error) {
+	doc := junitTestSuites{Name: report.Name, Time: junitSeconds(report.Duration)}
+	suites := map[int]*junitTestSuite{}
+	var order []int
+	for _, result := range report.Results {
+		suite, ok := suites[result.Iteration]
+		if !ok {
+			suite = &junitTestSuite{Name: report.Name, ID: len(order)}
+			if report.Iterations > 1 {
+				suite.Name = fmt.Sprintf("%s (итерация %d)", report.Name, result.Iteration)
+			}
+			if !result.Response.Timestamp.IsZero() {
+				suite.Timestamp = result.Response.Timestamp.Format("2006-01-02T15:04:05")
+			}
+			suites[result.Iteration] = suite
+			order = append(order, result.Iteration)
+		}
+		testCase := exportJUnitCase(report.Name, result)
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+		suite.Failures += min(len(testCase.Failures), 1)
+		if testCase.Error != nil {
+			suite.Errors++
+		}
+	}
+
+	for _, iteration := range order {
+		suite := suites[iteration]
+		var total time.Duration
+		for _, result := range report.Results {
+			if result.Iteration == iteration {
+				total += result.Response.Timings.Total
+			}
+		}
+		suite.Time = junitSeconds(total)
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Errors += suite.Errors
+		doc.Suites = append(doc.Suites, *suite)
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte(xml.Header), data...), '\n'), nil
+}
+
+// exportJUnitCase преобразует результат запроса в тест. Классом теста становится
+// запускаемая папка, именем - путь запроса в коллекции.
+func exportJUnitCase(runName string, r models.RunResult) junitTestCase {
+	testCase := junitTestCase{Name: r.Name, ClassName: runName, Time: junitSeconds(r.Response.Timings.Total)}
+	if r.Error != "" {
+		testCase.Error = &junitProblem{Message: r.Error, Type: "request", Text: r.Method + " " + r.URL}
+		return testCase
+	}
+
+	for _, a := range r.Assertions {
+		if a.Passed {
+			continue
+		}
+		problem := junitProblem{Message: a.Assertion, Type: "assertion"}
+		switch {
+		case a.Error != "":
+			problem.Text = a.Error
+		case a.Actual != "":
+			problem.Text = "получено: " + a.Actual
+		}
+		testCase.Failures = append(testCase.Failures, problem)
+	}
+	if statusFailed(r) {
+		testCase.Failures = append(testCase.Failures, junitProblem{
+			Message: fmt.Sprintf("код ответа %s считается ошибкой", r.Response.Status),
+			Type:    "status",
+		})
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "%s %s\n%s  %s", r.Method, r.URL, r.Response.Status, r.Response.Time)
+	for _, e := range r.Extracted {
+		if e.Error != "" {
+			fmt.Fprintf(&out, "\nпеременная %s: %s", e.Variable, e.Error)
+		}
+	}
+	testCase.SystemOut = out.String()
+	return testCase
+}
+
+// junitSeconds записывает длительность в секундах, как принято в JUnit
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// statusFailed сообщает, что запрос неуспешен только из-за кода ответа из fail_on
+func statusFailed(r models.RunResult) bool {
+	return !r.Passed && r.Error == "" && models.CountPassed(r.Assertions) == len(r.Assertions)
+}
+
+// --- Отчет JSON ---
+
+type runReportJSON struct {
+	Name       string            `json:"name"`
+	Started    time.Time         `json:"started"`
+	Duration   float64           `json:"duration"` // миллисекунды
+	Iterations int               `json:"iterations"`
+	Total      int               `json:"total"`
+	Executed   int               `json:"executed"`
+	Passed     int               `json:"passed"`
+	Failed     int               `json:"failed"`
+	Assertions runAssertionsJSON `json:"assertions"`
+	Stopped    bool              `json:"stopped"`
+	Canceled   bool              `json:"canceled"`
+	Variables  []models.Variable `json:"variables,omitempty"`
+	Results    []runResultJSON   `json:"results"`
+}
+
+type runAssertionsJSON struct {
+	Passed int `json:"passed"`
+	Total  int `json:"total"`
+}
+
+type runResultJSON struct {
+	Iteration    int                       `json:"iteration"`
+	Name         string                    `json:"name"`
+	Method       string                    `json:"method"`
+	URL          string                    `json:"url"`
+	Passed       bool                      `json:"passed"`
+	StatusFailed bool                      `json:"statusFailed,omitempty"` // код ответа попал в fail_on
+	Error        string                    `json:"error,omitempty"`
+	Timestamp    *time.Time                `json:"timestamp,omitempty"`
+	Status       string                    `json:"status,omitempty"`
+	StatusCode   int                       `json:"statusCode,omitempty"`
+	Proto        string                    `json:"proto,omitempty"`
+	Size         int64                     `json:"size"`
+	DecodedSize  int64                     `json:"decodedSize"`
+	Headers      []models.Header           `json:"headers,omitempty"`
+	Redirects    []models.RedirectHop      `json:"redirects,omitempty"`
+	Timings      *models.Timings           `json:"timings,omitempty"`
+	Assertions   []models.AssertionResult  `json:"assertions,omitempty"`
+	Extracted    []models.ExtractionResult `json:"extracted,omitempty"`
+}
+
+// ExportRunReport преобразует итоги запуска в отчет JSON с временем этапов
+// и данными ответов (без тел)
+func ExportRunReport(report models.RunReport) ([]byte, error) {
+	passedAssertions, totalAssertions := report.AssertionCounts()
+	doc := runReportJSON{
+		Name:       report.Name,
+		Started:    report.Started,
+		Duration:   models.Milliseconds(report.Duration),
+		Iterations: report.Iterations,
+		Total:      report.Total,
+		Executed:   len(report.Results),
+		Passed:     len(report.Results) - report.Failed(),
+		Failed:     report.Failed(),
+		Assertions: runAssertionsJSON{Passed: passedAssertions, Total: totalAssertions},
+		Stopped:    report.Stopped,
+		Canceled:   report.Canceled,
+		Variables:  report.Variables,
+		Results:    []runResultJSON{},
+	}
+	for _, r := range report.Results {
+		result := runResultJSON{
+			Iteration:    r.Iteration,
+			Name:         r.Name,
+			Method:       r.Method,
+			URL:          r.URL,
+			Passed:       r.Passed,
+			StatusFailed: statusFailed(r),
+			Error:        r.Error,
+			Assertions:   r.Assertions,
+			Extracted:    r.Extracted,
+		}
+		if r.Error == "" {
+			response := r.Response
+			result.Timestamp = &response.Timestamp
+			result.Status = response.Status
+			result.StatusCode = response.StatusCode
+			result.Proto = response.Proto
+			result.Size = response.Size
+			result.DecodedSize = response.DecodedSize
+			result.Headers = response.Headers
+			result.Redirects = response.Redirects
+			result.Timings = &response.Timings
+		}
+		doc.Results = append(doc.Results, result)
+	}
+
+	return marshalReport(doc)
+}
+
+// marshalReport записывает отчет в JSON с отступами, не экранируя символы адресов
+func marshalReport(doc interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// --- Отчет нагрузочного теста ---
+
+type loadReportJSON struct {
+	Name           string           `json:"name"`
+	Method         string           `json:"method"`
+	URL            string           `json:"url"`
+	Options        loadOptionsJSON  `json:"options"`
+	Started        time.Time        `json:"started"`
+	Duration       float64          `json:"duration"` // миллисекунды
+	Completed      int              `json:"completed"`
+	Failed         int              `json:"failed"`
+	Canceled       bool             `json:"canceled"`
+	Throughput     float64          `json:"throughput"` // запросов в секунду
+	BytesPerSecond float64          `json:"bytesPerSecond"`
+	Latency        loadLatencyJSON  `json:"latency"`
+	Histogram      []loadBucketJSON `json:"histogram"`
+	Statuses       []loadStatusJSON `json:"statuses"`
+	Errors         []loadErrorJSON  `json:"errors"`
+}
+
+type loadOptionsJSON struct {
+	Concurrency int     `json:"concurrency"`
+	Requests    int     `json:"requests"`
+	Duration    float64 `json:"duration"` // миллисекунды
+	RPS         float64 `json:"rps"`
+	FailOn      string  `json:"failOn"`
+}
+
+// loadLatencyJSON содержит время ответа в миллисекундах
+type loadLatencyJSON struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+type loadBucketJSON struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+type loadStatusJSON struct {
+	Status string `json:"status"`
+	Code   int    `json:"code"`
+	Count  int    `json:"count"`
+	Failed bool   `json:"failed"`
+}
+
+type loadErrorJSON struct {
+	Error string `json:"error"`
+	Count int    `json:"count"`
+}
+
+// ExportLoadReport преобразует итоги нагрузочного теста в отчет JSON. Время
+// указывается в миллисекундах, гистограмма содержит 10 интервалов, как на вкладке "Нагрузка".
+func ExportLoadReport(stats models.LoadStats) ([]byte, error) {
+	doc := loadReportJSON{
+		Name:   stats.Name,
+		Method: stats.Method,
+		URL:    stats.URL,
+		Options: loadOptionsJSON{
+			Concurrency: stats.Options.Concurrency,
+			Requests:    stats.Options.Requests,
+			Duration:    models.Milliseconds(stats.Options.Duration),
+			RPS:         stats.Options.RPS,
+			FailOn:      stats.Options.FailOn,
+		},
+		Started:        stats.Started,
+		Duration:       models.Milliseconds(stats.Elapsed),
+		Completed:      stats.Completed,
+		Failed:         stats.Failed,
+		Canceled:       stats.Canceled,
+		Throughput:     math.Round(stats.Throughput()*100) / 100,
+		BytesPerSecond: math.Round(stats.BytesPerSecond()),
+		Latency: loadLatencyJSON{
+			Min:  models.Milliseconds(stats.MinLatency()),
+			Mean: models.Milliseconds(stats.MeanLatency()),
+			P50:  models.Milliseconds(stats.Percentile(50)),
+			P90:  models.Milliseconds(stats.Percentile(90)),
+			P99:  models.Milliseconds(stats.Percentile(99)),
+			Max:  models.Milliseconds(stats.MaxLatency()),
+		},
+		Histogram: []loadBucketJSON{},
+		Statuses:  []loadStatusJSON{},
+		Errors:    []loadErrorJSON{},
+	}
+	for _, b := range stats.Histogram(10) {
+		doc.Histogram = append(doc.Histogram, loadBucketJSON{
+			From:  models.Milliseconds(b.From),
+			To:    models.Milliseconds(b.To),
+			Count: b.Count,
+		})
+	}
+	for _, c := range stats.StatusCounts() {
+		doc.Statuses = append(doc.Statuses, loadStatusJSON{Status: c.Name, Code: c.Code, Count: c.Count, Failed: c.Failed})
+	}
+	for _, c := range stats.ErrorCounts() {
+		doc.Errors = append(doc.Errors, loadErrorJSON{Error: c.Name, Count: c.Count})
+	}
+	return marshalReport(doc)
+}
@@ -0,0 +1,146 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// runReport возвращает итоги запуска из двух итераций со всеми видами результатов
+func runReport() models.RunReport {
+	started := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	response := func(status string, code int, offset, total time.Duration) models.ResponseData {
+		return models.ResponseData{
+			Status:     status,
+			StatusCode: code,
+			Time:       total.String(),
+			Proto:      "HTTP/1.1",
+			Timestamp:  started.Add(offset),
+			Timings:    models.Timings{Total: total},
+		}
+	}
+	return models.RunReport{
+		Name:       "Users",
+		Started:    started,
+		Duration:   1500 * time.Millisecond,
+		Iterations: 2,
+		Total:      6,
+		Results: []models.RunResult{
+			{
+				Iteration: 1, Name: "Users/login", Method: "POST", URL: "https://api.example.com/login", Passed: true,
+				Assertions: []models.AssertionResult{{Assertion: "status == 200", Passed: true, Actual: "200"}},
+				Extracted:  []models.ExtractionResult{{Variable: "token", Value: "t-1"}},
+				Response:   response("200 OK", 200, 0, 120*time.Millisecond),
+			},
+			{
+				Iteration: 1, Name: "Users/list", Method: "GET", URL: "https://api.example.com/users?page=1&size=<10>",
+				Assertions: []models.AssertionResult{
+					{Assertion: "status in 2xx", Passed: true, Actual: "200"},
+					{Assertion: `json $.items[0].name == "Ann"`, Actual: `"Bob"`},
+					{Assertion: "json $.total > 0", Error: "тело ответа не является JSON: неожиданный символ"},
+				},
+				Extracted: []models.ExtractionResult{{Variable: "first", Error: "значение не найдено"}},
+				Response:  response("200 OK", 200, 200*time.Millisecond, 80*time.Millisecond),
+			},
+			{
+				Iteration: 1, Name: "Users/delete", Method: "DELETE", URL: "https://api.example.com/users/1",
+				Response: response("503 Service Unavailable", 503, 300*time.Millisecond, 15*time.Millisecond),
+			},
+			{
+				Iteration: 2, Name: "Users/login", Method: "POST", URL: "https://api.example.com/login",
+				Error: `Post "https://api.example.com/login": dial tcp: connection refused`,
+			},
+		},
+		Variables: []models.Variable{{Key: "token", Value: "t-1"}},
+		Stopped:   true,
+		Finished:  true,
+	}
+}
+
+func TestExportJUnit(t *testing.T) {
+	data, err := ExportJUnit(runReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("testdata/run_report.junit.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("отчет JUnit отличается от testdata/run_report.junit.xml:\n%s", data)
+	}
+}
+
+func TestExportRunReport(t *testing.T) {
+	data, err := ExportRunReport(runReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc runReportJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Executed != 4 || doc.Passed != 1 || doc.Failed != 3 || doc.Assertions != (runAssertionsJSON{Passed: 2, Total: 4}) {
+		t.Errorf("итоги: выполнено %d, успешно %d, неуспешно %d, проверки %+v", doc.Executed, doc.Passed, doc.Failed, doc.Assertions)
+	}
+	if doc.Duration != 1500 || !doc.Stopped || doc.Canceled || len(doc.Variables) != 1 {
+		t.Errorf("отчет: %+v", doc)
+	}
+	deleted, failed := doc.Results[2], doc.Results[3]
+	if !deleted.StatusFailed || deleted.StatusCode != 503 || deleted.Timings == nil || deleted.Timings.Total != 15*time.Millisecond {
+		t.Errorf("результат с кодом из fail_on: %+v", deleted)
+	}
+	if failed.Error == "" || failed.Timestamp != nil || failed.Timings != nil || failed.StatusFailed {
+		t.Errorf("результат с ошибкой выполнения: %+v", failed)
+	}
+	// Символы адресов не экранируются
+	if !bytes.Contains(data, []byte(`"url": "https://api.example.com/users?page=1&size=<10>"`)) {
+		t.Errorf("адрес экранирован:\n%s", data)
+	}
+}
+
+func TestExportLoadReport(t *testing.T) {
+	opts := models.LoadOptions{Concurrency: 2, Requests: 4, FailOn: "5xx"}
+	stats := models.NewLoadStats("ping", "GET", "http://x/ping", opts)
+	for _, s := range []models.LoadSample{
+		{Latency: 10 * time.Millisecond, Status: "200 OK", Code: 200, Size: 100},
+		{Latency: 20 * time.Millisecond, Status: "200 OK", Code: 200, Size: 100},
+		{Latency: 40 * time.Millisecond, Status: "500 Internal Server Error", Code: 500, Size: 50},
+		{Latency: time.Second, Error: "dial tcp: connection refused"},
+	} {
+		stats.Add(s, opts.FailRanges())
+	}
+	stats = stats.Snapshot()
+	stats.Elapsed = 2 * time.Second
+	stats.Finished = true
+
+	data, err := ExportLoadReport(stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc loadReportJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	wantLatency := loadLatencyJSON{Min: 10, Mean: 23.333, P50: 20, P90: 40, P99: 40, Max: 40}
+	if doc.Latency != wantLatency {
+		t.Errorf("время ответа = %+v, ожидалось %+v", doc.Latency, wantLatency)
+	}
+	if doc.Completed != 4 || doc.Failed != 2 || doc.Throughput != 2 || doc.BytesPerSecond != 125 {
+		t.Errorf("итоги: выполнено %d, неуспешно %d, %v запр/с, %v Б/с", doc.Completed, doc.Failed, doc.Throughput, doc.BytesPerSecond)
+	}
+	wantStatuses := []loadStatusJSON{{Status: "200 OK", Code: 200, Count: 2}, {Status: "500 Internal Server Error", Code: 500, Count: 1, Failed: true}}
+	if len(doc.Statuses) != 2 || doc.Statuses[0] != wantStatuses[0] || doc.Statuses[1] != wantStatuses[1] {
+		t.Errorf("коды ответа = %+v", doc.Statuses)
+	}
+	if len(doc.Errors) != 1 || doc.Errors[0] != (loadErrorJSON{Error: "connection refused", Count: 1}) {
+		t.Errorf("ошибки = %+v", doc.Errors)
+	}
+	if len(doc.Histogram) != 10 || doc.Histogram[0].From != 10 || doc.Histogram[9].To != 40 {
+		t.Errorf("гистограмма = %+v", doc.Histogram)
+	}
+}
@@ -1,27 +1,112 @@
 package events
 
 import (
+	"context"
+	"fmt"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/KharpukhaevV/postui/converter"
 	"github.com/KharpukhaevV/postui/httpclient"
 	"github.com/KharpukhaevV/postui/models"
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // EventHandler обрабатывает события пользовательского ввода
 type EventHandler struct {
 	httpClient *httpclient.HTTPClient
+	// previewBuilder пересобирает содержимое предпросмотра при смене окружения
+	previewBuilder func() string
+	// inFlight содержит функции отмены выполняемых запросов по их идентификаторам.
+	// Используется только из цикла обработки сообщений, поэтому не требует блокировок.
+	inFlight      map[uint64]context.CancelFunc
+	nextRequestID uint64
+	// Выполняемый запуск папки или коллекции: отмена и канал его сообщений
+	runCancel  context.CancelFunc
+	runUpdates chan tea.Msg
+	nextRunID  uint64
+	// Выполняемый нагрузочный тест: отмена и канал снимков его статистики
+	loadCancel  context.CancelFunc
+	loadUpdates chan models.LoadStats
+	nextLoadID  uint64
 }
 
 // NewEventHandler создает новый обработчик событий
 func NewEventHandler() *EventHandler {
 	return &EventHandler{
 		httpClient: httpclient.NewHTTPClient(),
+		inFlight:   map[uint64]context.CancelFunc{},
+	}
+}
+
+// HandleResponse передает модели ответ на запрос и освобождает его контекст
+func (h *EventHandler) HandleResponse(model *models.AppModel, data models.ResponseData) {
+	h.finishRequest(data.RequestID)
+	model.SetResponseData(data)
+}
+
+// HandleRunResult передает модели результат запроса запуска и ожидает следующий
+func (h *EventHandler) HandleRunResult(model *models.AppModel, result models.RunResult) tea.Cmd {
+	model.AddRunResult(result)
+	return h.waitForRun()
+}
+
+// HandleRunFinished передает модели итоги запуска и освобождает его контекст
+func (h *EventHandler) HandleRunFinished(model *models.AppModel, report models.RunReport) {
+	if h.runCancel != nil {
+		h.runCancel()
+	}
+	h.runCancel, h.runUpdates = nil, nil
+	model.FinishRun(report)
+}
+
+// HandleLoadStats передает модели снимок статистики нагрузочного теста и ожидает
+// следующий, а после итогов освобождает контекст теста
+func (h *EventHandler) HandleLoadStats(model *models.AppModel, stats models.LoadStats) tea.Cmd {
+	model.UpdateLoad(stats)
+	if !stats.Finished {
+		return h.waitForLoad()
+	}
+	if h.loadCancel != nil {
+		h.loadCancel()
+	}
+	h.loadCancel, h.loadUpdates = nil, nil
+	return nil
+}
+
+// HandleError передает модели ошибку выполнения запроса и освобождает его контекст
+func (h *EventHandler) HandleError(model *models.AppModel, data models.ErrorData) {
+	h.finishRequest(data.RequestID)
+	model.SetError(data)
+}
+
+func (h *EventHandler) finishRequest(id uint64) {
+	if cancel, ok := h.inFlight[id]; ok {
+		cancel()
+		delete(h.inFlight, id)
 	}
 }
 
 // HandleKeyEvent обрабатывает события клавиш и возвращает флаг, если событие было "съедено"
 func (h *EventHandler) HandleKeyEvent(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
+	model.SetNotice("")
+
+	if msg.String() == "ctrl+x" {
+		if model.GetActiveTab() == models.TabRunner && h.runCancel != nil {
+			h.runCancel()
+			return model, nil, true
+		}
+		if model.GetActiveTab() == models.TabLoad && h.loadCancel != nil {
+			h.loadCancel()
+			return model, nil, true
+		}
+		h.cancelRequest(model)
+		return model, nil, true
+	}
+
 	// Глобальные обработчики (сохранение, удаление)
 	if model.IsSaving() {
 		return h.handleSaveAsPrompt(model, msg)
@@ -29,6 +114,43 @@ func (h *EventHandler) HandleKeyEvent(model *models.AppModel, msg tea.KeyMsg) (*
 	if model.IsDeleting() {
 		return h.handleDeleteConfirmation(model, msg)
 	}
+	if model.IsClosing() {
+		return h.handleCloseConfirmation(model, msg)
+	}
+	if model.IsImporting() {
+		return h.handleImportPrompt(model, msg)
+	}
+	if model.GetFolderPrompt() != models.FolderPromptNone {
+		return h.handleFolderPrompt(model, msg)
+	}
+	if model.GetSettingsPrompt() != models.SettingsPromptNone {
+		return h.handleSettingsPrompt(model, msg)
+	}
+	if model.IsAsserting() {
+		return h.handleAssertionPrompt(model, msg)
+	}
+	if model.IsExtracting() {
+		return h.handleExtractPrompt(model, msg)
+	}
+	if model.IsRunPrompt() {
+		return h.handleRunPrompt(model, msg)
+	}
+	if model.IsLoadPrompt() {
+		return h.handleLoadPrompt(model, msg)
+	}
+	if model.GetCookiePrompt() != models.CookiePromptNone {
+		return h.handleCookiePrompt(model, msg)
+	}
+	if model.GetResponsePrompt() != models.ResponsePromptNone {
+		return h.handleResponsePrompt(model, msg)
+	}
+	if model.GetPreview() != "" {
+		return h.handlePreview(model, msg)
+	}
+	if h.isFiltering(model) {
+		// Во время ввода фильтра все клавиши передаются списку
+		return model, nil, false
+	}
 
 	if !model.GetInputMode() {
 		return h.handleNavigationMode(model, msg)
@@ -46,26 +168,118 @@ func (h *EventHandler) handleNavigationMode(model *models.AppModel, msg tea.KeyM
 		h.updateFocus(model)
 		return model, nil, true
 	case "ctrl+s":
-		if model.GetActiveTab() == models.TabRequest {
+		if model.GetActiveTab() == models.TabRequest || model.GetActiveTab() == models.TabHistory {
 			model.SetIsSaving(true)
 			model.GetSaveNameInput().Focus()
 		}
 		return model, nil, true
 
+	// Вкладки запросов
+	case "ctrl+t":
+		model.NewSession()
+		return model, nil, true
+	case "ctrl+w":
+		if model.IsDirty() {
+			model.SetIsClosing(true)
+		} else {
+			h.closeSession(model)
+		}
+		return model, nil, true
+	case "]":
+		model.NextSession(1)
+		return model, nil, true
+	case "[":
+		model.NextSession(-1)
+		return model, nil, true
+	case "o":
+		if model.GetActiveTab() == models.TabSaved {
+			model.LoadRequestFromSaved(true)
+			return model, nil, true
+		}
+		if model.GetActiveTab() == models.TabRequest {
+			h.openSettingsPrompt(model, models.SettingsPromptRequest)
+			return model, nil, true
+		}
+	case "O":
+		h.openSettingsPrompt(model, models.SettingsPromptGlobal)
+		return model, nil, true
+	case "T":
+		if model.GetActiveTab() == models.TabRequest || model.GetActiveTab() == models.TabResponse {
+			input := model.GetAssertionInput()
+			input.SetValue(models.FormatAssertions(model.GetAssertions()))
+			input.CursorEnd()
+			input.Focus()
+			model.SetIsAsserting(true)
+			return model, nil, true
+		}
+	case "R":
+		if model.GetActiveTab() == models.TabSaved || model.GetActiveTab() == models.TabRunner {
+			if model.GetActiveTab() == models.TabSaved {
+				model.SelectRunTarget()
+			}
+			input := model.GetRunInput()
+			input.SetValue(models.FormatRunOptions(model.GetRunOptions()))
+			input.CursorEnd()
+			input.Focus()
+			model.SetIsRunPrompt(true)
+			return model, nil, true
+		}
+	case "L":
+		if model.GetActiveTab() == models.TabSaved || model.GetActiveTab() == models.TabLoad {
+			if model.GetActiveTab() == models.TabSaved && !model.SelectLoadTarget() {
+				model.SetNotice("Выберите запрос для нагрузочного теста")
+				return model, nil, true
+			}
+			input := model.GetLoadInput()
+			input.SetValue(models.FormatLoadOptions(model.GetLoadOptions()))
+			input.CursorEnd()
+			input.Focus()
+			model.SetIsLoadPrompt(true)
+			return model, nil, true
+		}
+	case "X":
+		if model.GetActiveTab() == models.TabRequest || model.GetActiveTab() == models.TabResponse {
+			input := model.GetExtractInput()
+			input.SetValue(models.FormatExtractions(model.GetExtractions()))
+			input.CursorEnd()
+			input.Focus()
+			model.SetIsExtracting(true)
+			return model, nil, true
+		}
+
+	case "s":
+		if model.GetActiveTab() == models.TabHistory {
+			model.SetIsSaving(true)
+			model.GetSaveNameInput().Focus()
+			return model, nil, true
+		}
+		if model.GetActiveTab() == models.TabResponse {
+			h.openResponsePrompt(model, models.ResponsePromptSave)
+			return model, nil, true
+		}
+
 	// Переключение вкладок и методов
 	case "left", "h":
 		if model.GetActiveTab() == models.TabRequest && model.GetActiveSection() == models.SectionMethod {
 			model.SetSelectedMethod(models.HTTPMethod((int(model.GetSelectedMethod()) - 1 + len(models.MethodNames)) % len(models.MethodNames)))
+		} else if model.GetActiveTab() == models.TabRequest && model.GetActiveSection() == models.SectionAuth {
+			model.SetAuthType(model.GetAuthType().Next(-1))
+		} else if model.GetActiveTab() == models.TabRequest && model.GetActiveSection() == models.SectionBody {
+			model.SetBodyType(model.GetBodyType().Next(-1))
 		} else {
-			currentTab := (int(model.GetActiveTab()) - 1 + 3) % 3
+			currentTab := (int(model.GetActiveTab()) - 1 + models.TabCount) % models.TabCount
 			model.SetActiveTab(models.Tab(currentTab))
 		}
 		return model, nil, true
 	case "right", "l":
 		if model.GetActiveTab() == models.TabRequest && model.GetActiveSection() == models.SectionMethod {
 			model.SetSelectedMethod(models.HTTPMethod((int(model.GetSelectedMethod()) + 1) % len(models.MethodNames)))
+		} else if model.GetActiveTab() == models.TabRequest && model.GetActiveSection() == models.SectionAuth {
+			model.SetAuthType(model.GetAuthType().Next(1))
+		} else if model.GetActiveTab() == models.TabRequest && model.GetActiveSection() == models.SectionBody {
+			model.SetBodyType(model.GetBodyType().Next(1))
 		} else {
-			currentTab := (int(model.GetActiveTab()) + 1) % 3
+			currentTab := (int(model.GetActiveTab()) + 1) % models.TabCount
 			model.SetActiveTab(models.Tab(currentTab))
 		}
 		return model, nil, true
@@ -73,33 +287,59 @@ func (h *EventHandler) handleNavigationMode(model *models.AppModel, msg tea.KeyM
 	// Навигация по секциям на вкладке "Запрос"
 	case "k", "up":
 		if model.GetActiveTab() == models.TabRequest {
-			model.SetActiveSection(models.Section((int(model.GetActiveSection()) + 4) % 5))
+			model.SetActiveSection(models.Section((int(model.GetActiveSection()) + models.SectionCount - 1) % models.SectionCount))
 			h.updateFocus(model)
 		} else if model.GetActiveTab() == models.TabSaved {
 			*model.GetSavedList(), _ = model.GetSavedList().Update(msg)
+			model.SyncSavedTree()
+		} else if model.GetActiveTab() == models.TabHistory {
+			*model.GetHistoryList(), _ = model.GetHistoryList().Update(msg)
+		} else if model.GetActiveTab() == models.TabCookies {
+			*model.GetCookieList(), _ = model.GetCookieList().Update(msg)
+		} else if model.GetActiveTab() == models.TabResponse {
+			h.scrollResponse(model, -1)
+		} else if model.GetActiveTab() == models.TabRunner {
+			model.GetRunVP().LineUp(1)
+		} else if model.GetActiveTab() == models.TabLoad {
+			model.GetLoadVP().LineUp(1)
 		}
 		return model, nil, true
 	case "j", "down":
 		if model.GetActiveTab() == models.TabRequest {
-			model.SetActiveSection(models.Section((int(model.GetActiveSection()) + 1) % 5))
+			model.SetActiveSection(models.Section((int(model.GetActiveSection()) + 1) % models.SectionCount))
 			h.updateFocus(model)
 		} else if model.GetActiveTab() == models.TabSaved {
 			*model.GetSavedList(), _ = model.GetSavedList().Update(msg)
+			model.SyncSavedTree()
+		} else if model.GetActiveTab() == models.TabHistory {
+			*model.GetHistoryList(), _ = model.GetHistoryList().Update(msg)
+		} else if model.GetActiveTab() == models.TabCookies {
+			*model.GetCookieList(), _ = model.GetCookieList().Update(msg)
+		} else if model.GetActiveTab() == models.TabResponse {
+			h.scrollResponse(model, 1)
+		} else if model.GetActiveTab() == models.TabRunner {
+			model.GetRunVP().LineDown(1)
+		} else if model.GetActiveTab() == models.TabLoad {
+			model.GetLoadVP().LineDown(1)
 		}
 		return model, nil, true
 	case "tab":
 		if model.GetActiveTab() == models.TabRequest {
-			model.SetActiveSection(models.Section((int(model.GetActiveSection()) + 1) % 5))
+			model.SetActiveSection(models.Section((int(model.GetActiveSection()) + 1) % models.SectionCount))
 			h.updateFocus(model)
+		} else if model.GetActiveTab() == models.TabResponse {
+			model.SetResponseView(models.ResponseView((int(model.GetResponseView()) + 1) % models.ResponseViewCount))
 		}
 		return model, nil, true
 	case "shift+tab":
 		if model.GetActiveTab() == models.TabRequest {
-			model.SetActiveSection(models.Section((int(model.GetActiveSection()) + 4) % 5))
+			model.SetActiveSection(models.Section((int(model.GetActiveSection()) + models.SectionCount - 1) % models.SectionCount))
 			h.updateFocus(model)
+		} else if model.GetActiveTab() == models.TabResponse {
+			model.SetResponseView(models.ResponseView((int(model.GetResponseView()) + models.ResponseViewCount - 1) % models.ResponseViewCount))
 		}
 		return model, nil, true
-	case "1", "2", "3", "4", "5":
+	case "1", "2", "3", "4", "5", "6":
 		if model.GetActiveTab() == models.TabRequest {
 			section := 0
 			switch msg.String() {
@@ -113,18 +353,133 @@ func (h *EventHandler) handleNavigationMode(model *models.AppModel, msg tea.KeyM
 				section = 3
 			case "5":
 				section = 4
+			case "6":
+				section = 5
 			}
 			model.SetActiveSection(models.Section(section))
 			h.updateFocus(model)
 		}
 		return model, nil, true
 
+	case "e":
+		model.NextEnvironment()
+		return model, nil, true
+	case "p":
+		if model.GetActiveTab() == models.TabRequest {
+			h.showPreview(model, "Предпросмотр запроса", func() string {
+				req := httpclient.NewHTTPRequest(model)
+				return req.String()
+			})
+		} else if model.GetActiveTab() == models.TabSaved {
+			model.MoveMarkedRequest()
+		}
+		return model, nil, true
+	case "c":
+		h.exportCurl(model)
+		return model, nil, true
+	case "I":
+		if model.GetActiveTab() == models.TabSaved {
+			model.SetIsImporting(true)
+			model.GetImportInput().Focus()
+		}
+		return model, nil, true
+	case "H":
+		h.exportHAR(model)
+		return model, nil, true
+	case "J":
+		if model.GetActiveTab() == models.TabRunner {
+			h.exportRunReports(model)
+		}
+		if model.GetActiveTab() == models.TabLoad {
+			h.exportLoadReport(model)
+		}
+		return model, nil, true
+
 	case "d":
 		if model.GetActiveTab() == models.TabSaved {
-			model.SetIsDeleting(true)
+			if _, ok := model.GetSelectedTreeItem(); ok {
+				model.SetIsDeleting(true)
+			}
+		}
+		if model.GetActiveTab() == models.TabCookies {
+			h.deleteCookies(model, false)
+		}
+		return model, nil, true
+	case "D":
+		if model.GetActiveTab() == models.TabCookies {
+			h.deleteCookies(model, true)
 		}
 		return model, nil, true
 
+	// Работа с папками на вкладке "Сохраненные"
+	case "n", "u", "t":
+		if model.GetActiveTab() == models.TabCookies && msg.String() == "n" {
+			h.openCookiePrompt(model, models.CookiePromptCreate)
+			return model, nil, true
+		}
+		if model.GetActiveTab() == models.TabResponse && msg.String() == "n" {
+			if !model.NextMatch(1) && model.GetSearch() != "" {
+				model.SetNotice("Не найдено: " + model.GetSearch())
+			}
+			return model, nil, true
+		}
+		if model.GetActiveTab() == models.TabResponse && msg.String() == "t" {
+			if err := model.ToggleTreeView(); err != nil {
+				model.SetNotice("Дерево недоступно: " + err.Error())
+			}
+			return model, nil, true
+		}
+		if model.GetActiveTab() == models.TabSaved {
+			h.openFolderPrompt(model, msg.String())
+			return model, nil, true
+		}
+	case "x":
+		if model.GetActiveTab() == models.TabSaved {
+			model.MarkSelectedRequest()
+			return model, nil, true
+		}
+	case "esc":
+		if _, ok := model.GetMarkedRequest(); ok && model.GetActiveTab() == models.TabSaved {
+			model.ClearMark()
+			return model, nil, true
+		}
+	// Поиск, фильтр, дерево и исходный вид на вкладке "Ответ"
+	case "N":
+		if model.GetActiveTab() == models.TabResponse {
+			model.NextMatch(-1)
+			return model, nil, true
+		}
+	case "f":
+		if model.GetActiveTab() == models.TabResponse {
+			h.openResponsePrompt(model, models.ResponsePromptFilter)
+			return model, nil, true
+		}
+	case "r":
+		if model.GetActiveTab() == models.TabResponse {
+			model.ToggleRawView()
+			return model, nil, true
+		}
+	case " ":
+		if model.IsTreeView() {
+			model.ToggleFold()
+			return model, nil, true
+		}
+	case "-", "+", "=":
+		if model.IsTreeView() {
+			model.FoldAll(msg.String() == "-")
+			return model, nil, true
+		}
+
+	case "/":
+		if model.GetActiveTab() == models.TabResponse {
+			h.openResponsePrompt(model, models.ResponsePromptSearch)
+			return model, nil, true
+		}
+		// Перед началом фильтрации показываем запросы во всех папках
+		if model.GetActiveTab() == models.TabSaved && model.GetSavedList().FilterState() == list.Unfiltered {
+			model.ShowAllSavedRequests()
+		}
+
 	case "enter":
 		model, cmd := h.handleEnterKey(model)
 		return model, cmd, true
@@ -152,6 +507,13 @@ func (h *EventHandler) handleInputMode(model *models.AppModel, msg tea.KeyMsg) (
 			model, cmd := h.handleEnterOnRequestTab(model)
 			return model, cmd, true // "Съедаем" Enter
 		}
+		// Вставленная в поле URL команда curl импортируется по Enter
+		if model.GetActiveSection() == models.SectionURL && converter.IsCurlCommand(model.URLInputValue()) {
+			h.importCurl(model)
+			model.SetInputMode(false)
+			h.updateFocus(model)
+			return model, nil, true
+		}
 	}
 	return model, nil, false // Ключ не обработан, передать компоненту
 }
@@ -163,7 +525,11 @@ func (h *EventHandler) handleSaveAsPrompt(model *models.AppModel, msg tea.KeyMsg
 	case "enter":
 		name := model.GetSaveNameInput().Value()
 		if name != "" {
-			model.AddNewSavedRequest(name)
+			if model.GetActiveTab() == models.TabHistory {
+				model.AddSavedRequestFromHistory(name)
+			} else {
+				model.AddNewSavedRequest(name)
+			}
 		}
 		model.GetSaveNameInput().SetValue("")
 		model.GetSaveNameInput().Blur()
@@ -181,7 +547,7 @@ func (h *EventHandler) handleSaveAsPrompt(model *models.AppModel, msg tea.KeyMsg
 func (h *EventHandler) handleDeleteConfirmation(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
 	switch strings.ToLower(msg.String()) {
 	case "y":
-		model.DeleteSelectedRequest()
+		model.DeleteSelectedItem()
 		model.SetIsDeleting(false)
 	case "n", "esc":
 		model.SetIsDeleting(false)
@@ -189,6 +555,414 @@ func (h *EventHandler) handleDeleteConfirmation(model *models.AppModel, msg tea.
 	return model, nil, true // "Съедаем" событие в любом случае
 }
 
+func (h *EventHandler) handleCloseConfirmation(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
+	switch strings.ToLower(msg.String()) {
+	case "y":
+		h.closeSession(model)
+		model.SetIsClosing(false)
+	case "n", "esc":
+		model.SetIsClosing(false)
+	}
+	return model, nil, true // "Съедаем" событие в любом случае
+}
+
+func (h *EventHandler) handleImportPrompt(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
+	switch msg.String() {
+	case "enter":
+		if path := strings.TrimSpace(model.GetImportInput().Value()); path != "" {
+			h.importFile(model, path)
+		}
+		model.GetImportInput().SetValue("")
+		model.GetImportInput().Blur()
+		model.SetIsImporting(false)
+		return model, nil, true
+	case "esc":
+		model.GetImportInput().SetValue("")
+		model.GetImportInput().Blur()
+		model.SetIsImporting(false)
+		return model, nil, true
+	}
+	*model.GetImportInput(), _ = model.GetImportInput().Update(msg)
+	return model, nil, true // "Съедаем" событие в любом случае
+}
+
+// openFolderPrompt открывает ввод имени новой папки, базового URL или заголовков выбранной папки
+func (h *EventHandler) openFolderPrompt(model *models.AppModel, key string) {
+	input := model.GetFolderInput()
+	switch key {
+	case "n":
+		model.SetFolderPrompt(models.FolderPromptCreate)
+		input.Placeholder = "Новая папка"
+		input.SetValue("")
+	case "u":
+		folder, ok := model.GetSelectedFolder()
+		if !ok {
+			return
+		}
+		model.SetFolderPrompt(models.FolderPromptBaseURL)
+		input.Placeholder = "https://api.example.com/v1"
+		input.SetValue(folder.BaseURL)
+	case "t":
+		folder, ok := model.GetSelectedFolder()
+		if !ok {
+			return
+		}
+		model.SetFolderPrompt(models.FolderPromptHeaders)
+		input.Placeholder = "Authorization=Bearer {{token}}; Accept=application/json"
+		input.SetValue(formatFolderHeaders(folder.Headers))
+	}
+	input.CursorEnd()
+	input.Focus()
+}
+
+func (h *EventHandler) handleFolderPrompt(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
+	input := model.GetFolderInput()
+	switch msg.String() {
+	case "enter":
+		value := strings.TrimSpace(input.Value())
+		switch model.GetFolderPrompt() {
+		case models.FolderPromptCreate:
+			if value != "" {
+				model.CreateFolder(value)
+			}
+		case models.FolderPromptBaseURL:
+			model.SetSelectedFolderBaseURL(value)
+		case models.FolderPromptHeaders:
+			model.SetSelectedFolderHeaders(parseFolderHeaders(value))
+		}
+		fallthrough
+	case "esc":
+		input.SetValue("")
+		input.Blur()
+		model.SetFolderPrompt(models.FolderPromptNone)
+		return model, nil, true
+	}
+	*input, _ = input.Update(msg)
+	return model, nil, true // "Съедаем" событие в любом случае
+}
+
+// openSettingsPrompt открывает ввод настроек клиента запроса активной вкладки или общих настроек
+func (h *EventHandler) openSettingsPrompt(model *models.AppModel, prompt models.SettingsPrompt) {
+	settings := model.GetClientSettings()
+	if prompt == models.SettingsPromptRequest {
+		settings = model.GetRequestSettings()
+	}
+	input := model.GetSettingsInput()
+	input.SetValue(models.FormatClientSettings(settings))
+	input.CursorEnd()
+	input.Focus()
+	model.SetSettingsPrompt(prompt)
+}
+
+func (h *EventHandler) handleSettingsPrompt(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
+	input := model.GetSettingsInput()
+	switch msg.String() {
+	case "enter":
+		settings, err := models.ParseClientSettings(input.Value())
+		if err != nil {
+			// Поле остается открытым, чтобы исправить ошибку
+			model.SetNotice("Ошибка в настройках: " + err.Error())
+			return model, nil, true
+		}
+		if model.GetSettingsPrompt() == models.SettingsPromptGlobal {
+			if err := model.SetClientSettings(settings); err != nil {
+				model.SetNotice("Не удалось сохранить настройки: " + err.Error())
+			}
+		} else {
+			model.SetRequestSettings(settings)
+		}
+		fallthrough
+	case "esc":
+		input.SetValue("")
+		input.Blur()
+		model.SetSettingsPrompt(models.SettingsPromptNone)
+		return model, nil, true
+	}
+	*input, _ = input.Update(msg)
+	return model, nil, true // "Съедаем" событие в любом случае
+}
+
+// handleAssertionPrompt обрабатывает ввод проверок ответа запроса активной вкладки
+func (h *EventHandler) handleAssertionPrompt(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
+	input := model.GetAssertionInput()
+	switch msg.String() {
+	case "enter":
+		assertions, err := models.ParseAssertions(input.Value())
+		if err != nil {
+			// Поле остается открытым, чтобы исправить ошибку
+			model.SetNotice("Ошибка в проверках: " + err.Error())
+			return model, nil, true
+		}
+		model.SetAssertions(assertions)
+		fallthrough
+	case "esc":
+		input.SetValue("")
+		input.Blur()
+		model.SetIsAsserting(false)
+		return model, nil, true
+	}
+	*input, _ = input.Update(msg)
+	return model, nil, true // "Съедаем" событие в любом случае
+}
+
+// handleExtractPrompt обрабатывает ввод правил извлечения переменных из ответа
+func (h *EventHandler) handleExtractPrompt(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
+	input := model.GetExtractInput()
+	switch msg.String() {
+	case "enter":
+		extractions, err := models.ParseExtractions(input.Value())
+		if err != nil {
+			// Поле остается открытым, чтобы исправить ошибку
+			model.SetNotice("Ошибка в правилах: " + err.Error())
+			return model, nil, true
+		}
+		model.SetExtractions(extractions)
+		fallthrough
+	case "esc":
+		input.SetValue("")
+		input.Blur()
+		model.SetIsExtracting(false)
+		return model, nil, true
+	}
+	*input, _ = input.Update(msg)
+	return model, nil, true // "Съедаем" событие в любом случае
+}
+
+// handleRunPrompt обрабатывает ввод параметров запуска и начинает запуск
+func (h *EventHandler) handleRunPrompt(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
+	input := model.GetRunInput()
+	switch msg.String() {
+	case "enter":
+		options, err := models.ParseRunOptions(input.Value())
+		if err != nil {
+			// Поле остается открытым, чтобы исправить ошибку
+			model.SetNotice("Ошибка в параметрах: " + err.Error())
+			return model, nil, true
+		}
+		var data []map[string]string
+		if options.DataFile != "" {
+			if data, err = models.LoadRunData(options.DataFile); err != nil {
+				model.SetNotice(fmt.Sprintf("Ошибка файла данных %s: %v", options.DataFile, err))
+				return model, nil, true
+			}
+		}
+		model.SetRunOptions(options)
+		input.SetValue("")
+		input.Blur()
+		model.SetIsRunPrompt(false)
+		return model, h.startRun(model, data), true
+	case "esc":
+		input.SetValue("")
+		input.Blur()
+		model.SetIsRunPrompt(false)
+		return model, nil, true
+	}
+	*input, _ = input.Update(msg)
+	return model, nil, true // "Съедаем" событие в любом случае
+}
+
+// handleLoadPrompt обрабатывает ввод параметров нагрузочного теста и начинает тест
+func (h *EventHandler) handleLoadPrompt(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
+	input := model.GetLoadInput()
+	switch msg.String() {
+	case "enter":
+		options, err := models.ParseLoadOptions(input.Value())
+		if err != nil {
+			// Поле остается открытым, чтобы исправить ошибку
+			model.SetNotice("Ошибка в параметрах: " + err.Error())
+			return model, nil, true
+		}
+		model.SetLoadOptions(options)
+		input.SetValue("")
+		input.Blur()
+		model.SetIsLoadPrompt(false)
+		return model, h.startLoad(model), true
+	case "esc":
+		input.SetValue("")
+		input.Blur()
+		model.SetIsLoadPrompt(false)
+		return model, nil, true
+	}
+	*input, _ = input.Update(msg)
+	return model, nil, true // "Съедаем" событие в любом случае
+}
+
+// openResponsePrompt открывает поиск по ответу или ввод фильтра тела
+func (h *EventHandler) openResponsePrompt(model *models.AppModel, prompt models.ResponsePrompt) {
+	input := model.GetResponseInput()
+	input.SetValue("")
+	input.Placeholder = "текст для поиска"
+	switch prompt {
+	case models.ResponsePromptFilter:
+		input.SetValue(model.GetBodyFilter())
+		input.Placeholder = "$.items[?(@.price < 10)].name или .items[] | select(.active) | .name"
+	case models.ResponsePromptSave:
+		input.SetValue(model.SuggestBodyFileName())
+		input.Placeholder = "путь к файлу"
+	}
+	input.CursorEnd()
+	input.Focus()
+	model.SetResponsePrompt(prompt)
+}
+
+// handleResponsePrompt обрабатывает ввод поиска (применяется при каждом нажатии),
+// фильтра и пути для сохранения тела
+func (h *EventHandler) handleResponsePrompt(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
+	input := model.GetResponseInput()
+	prompt := model.GetResponsePrompt()
+	switch msg.String() {
+	case "enter":
+		switch {
+		case prompt == models.ResponsePromptFilter:
+			if err := model.SetBodyFilter(input.Value()); err != nil {
+				// Поле остается открытым, чтобы исправить ошибку
+				model.SetNotice("Ошибка в фильтре: " + err.Error())
+				return model, nil, true
+			}
+		case prompt == models.ResponsePromptSave:
+			filename := strings.TrimSpace(input.Value())
+			if filename == "" {
+				return model, nil, true
+			}
+			written, err := model.SaveResponseBody(filename)
+			if err != nil {
+				model.SetNotice("Не удалось сохранить тело: " + err.Error())
+				return model, nil, true
+			}
+			model.SetNotice(fmt.Sprintf("Тело сохранено в %s (%s)", filename, models.FormatSize(written)))
+		case input.Value() != "":
+			if _, total := model.SearchStatus(); total == 0 {
+				model.SetNotice("Не найдено: " + input.Value())
+			}
+		}
+		input.Blur()
+		model.SetResponsePrompt(models.ResponsePromptNone)
+		return model, nil, true
+	case "esc":
+		if prompt == models.ResponsePromptSearch {
+			model.SetSearch("")
+		}
+		input.Blur()
+		model.SetResponsePrompt(models.ResponsePromptNone)
+		return model, nil, true
+	}
+	*input, _ = input.Update(msg)
+	if prompt == models.ResponsePromptSearch {
+		model.SetSearch(input.Value())
+	}
+	return model, nil, true // "Съедаем" событие в любом случае
+}
+
+// scrollResponse перемещает курсор дерева или прокручивает область ответа на строку
+func (h *EventHandler) scrollResponse(model *models.AppModel, delta int) {
+	if model.IsTreeView() {
+		model.MoveTreeCursor(delta)
+		return
+	}
+	if delta > 0 {
+		model.GetResponseVP().LineDown(delta)
+	} else {
+		model.GetResponseVP().LineUp(-delta)
+	}
+}
+
+// openCookiePrompt открывает ввод новой cookie или изменение выбранной
+func (h *EventHandler) openCookiePrompt(model *models.AppModel, prompt models.CookiePrompt) {
+	input := model.GetCookieInput()
+	input.SetValue("")
+	if prompt == models.CookiePromptEdit {
+		c, ok := model.GetSelectedCookie()
+		if !ok {
+			return
+		}
+		input.SetValue(models.FormatCookieLine(c))
+	}
+	model.SetCookiePrompt(prompt)
+	input.CursorEnd()
+	input.Focus()
+}
+
+func (h *EventHandler) handleCookiePrompt(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
+	input := model.GetCookieInput()
+	switch msg.String() {
+	case "enter":
+		c, err := models.ParseCookieLine(input.Value())
+		if err != nil {
+			// Поле остается открытым, чтобы исправить ошибку
+			model.SetNotice("Ошибка в cookie: " + err.Error())
+			return model, nil, true
+		}
+		if err := model.SaveCookie(model.GetEditedCookie(), c); err != nil {
+			model.SetNotice("Не удалось сохранить cookies: " + err.Error())
+		}
+		fallthrough
+	case "esc":
+		input.SetValue("")
+		input.Blur()
+		model.SetCookiePrompt(models.CookiePromptNone)
+		return model, nil, true
+	}
+	*input, _ = input.Update(msg)
+	return model, nil, true // "Съедаем" событие в любом случае
+}
+
+// deleteCookies удаляет выбранную cookie или все cookies ее домена
+func (h *EventHandler) deleteCookies(model *models.AppModel, allDomain bool) {
+	c, ok := model.GetSelectedCookie()
+	if !ok {
+		return
+	}
+	count, err := model.DeleteSelectedCookie(allDomain)
+	switch {
+	case err != nil:
+		model.SetNotice("Не удалось сохранить cookies: " + err.Error())
+	case allDomain:
+		model.SetNotice(fmt.Sprintf("Удалено cookies домена %s: %d", c.Domain, count))
+	default:
+		model.SetNotice(fmt.Sprintf("Cookie %s удалена", c.Name))
+	}
+}
+
+// formatFolderHeaders записывает заголовки папки в одну строку "Key=Value; Key2=Value2"
+func formatFolderHeaders(headers []models.Header) string {
+	pairs := make([]string, len(headers))
+	for i, header := range headers {
+		pairs[i] = header.Key + "=" + header.Value
+	}
+	return strings.Join(pairs, "; ")
+}
+
+func parseFolderHeaders(value string) []models.Header {
+	var headers []models.Header
+	for _, pair := range strings.Split(value, ";") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) != "" {
+			headers = append(headers, models.Header{Key: strings.TrimSpace(parts[0]), Value: strings.TrimSpace(parts[1])})
+		}
+	}
+	return headers
+}
+
+func (h *EventHandler) handlePreview(model *models.AppModel, msg tea.KeyMsg) (*models.AppModel, tea.Cmd, bool) {
+	switch msg.String() {
+	case "ctrl+c":
+		return model, tea.Quit, true
+	case "e":
+		// Позволяем переключать окружение, не закрывая предпросмотр
+		model.NextEnvironment()
+		model.SetPreview(model.GetPreviewTitle(), h.previewBuilder())
+	default:
+		model.SetPreview("", "")
+	}
+	return model, nil, true // "Съедаем" событие в любом случае
+}
+
+// showPreview открывает предпросмотр с содержимым, построенным функцией build
+func (h *EventHandler) showPreview(model *models.AppModel, title string, build func() string) {
+	h.previewBuilder = build
+	model.SetPreview(title, build())
+}
+
 // --- Основные действия ---
 
 func (h *EventHandler) handleEnterKey(model *models.AppModel) (*models.AppModel, tea.Cmd) {
@@ -196,7 +970,18 @@ func (h *EventHandler) handleEnterKey(model *models.AppModel) (*models.AppModel,
 	case models.TabRequest:
 		return h.handleEnterOnRequestTab(model)
 	case models.TabSaved:
-		model.LoadRequestFromSaved()
+		if !model.ToggleSelectedFolder() {
+			model.LoadRequestFromSaved(false)
+		}
+		return model, nil
+	case models.TabHistory:
+		model.LoadRequestFromHistory()
+		return model, nil
+	case models.TabCookies:
+		h.openCookiePrompt(model, models.CookiePromptEdit)
+		return model, nil
+	case models.TabResponse:
+		model.ToggleFold()
 		return model, nil
 	}
 	return model, nil
@@ -221,8 +1006,11 @@ func (h *EventHandler) handleEnterOnRequestTab(model *models.AppModel) (*models.
 			}
 		}
 	default:
+		if converter.IsCurlCommand(model.URLInputValue()) {
+			h.importCurl(model)
+			return model, nil
+		}
 		if model.URLInputValue() != "" {
-			model.SetLoading(true)
 			return model, h.sendRequest(model)
 		}
 	}
@@ -246,22 +1034,317 @@ func (h *EventHandler) handleBackspaceKey(model *models.AppModel) (*models.AppMo
 	return model, nil
 }
 
+// sendRequest отправляет текущий запрос. Запрос полностью собирается до запуска
+// команды, поэтому горутина команды не обращается к модели.
 func (h *EventHandler) sendRequest(model *models.AppModel) tea.Cmd {
+	req := httpclient.NewHTTPRequest(model)
+	h.nextRequestID++
+	id := h.nextRequestID
+	ctx, cancel := context.WithCancel(context.Background())
+	h.inFlight[id] = cancel
+	model.StartRequest(id)
+
+	client := h.httpClient
 	return func() tea.Msg {
-		req := httpclient.NewHTTPRequest(model)
-		response, err := h.httpClient.SendRequest(&req)
+		response, err := client.SendRequest(ctx, &req)
 		if err != nil {
-			return models.ErrorData{Message: err.Error()}
+			data := models.ErrorData{RequestID: id, Message: err.Error(), Request: req.Snapshot()}
+			if ctx.Err() == context.Canceled {
+				data.Message, data.Canceled = "запрос отменен", true
+			}
+			return data
 		}
+		req.CheckResponse(&response)
+		response.RequestID = id
 		return response
 	}
 }
 
+// startRun запускает запросы выбранной папки или коллекции. Запуск выполняется
+// в горутине и передает результаты через канал, который читает waitForRun.
+func (h *EventHandler) startRun(model *models.AppModel, data []map[string]string) tea.Cmd {
+	if h.runCancel != nil {
+		model.SetNotice("Запуск уже выполняется (ctrl+x на вкладке \"Запуск\" - остановить)")
+		return nil
+	}
+	requests, err := model.RunRequests()
+	if err != nil {
+		model.SetNotice("Запуск невозможен: " + err.Error())
+		return nil
+	}
+
+	options := model.GetRunOptions()
+	runner := &httpclient.Runner{
+		Client:    h.httpClient,
+		Settings:  model.GetClientSettings(),
+		Cookies:   model.CookieJar(),
+		Variables: model.GetActiveVariables(),
+	}
+	h.nextRunID++
+	id := h.nextRunID
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan tea.Msg)
+	h.runCancel, h.runUpdates = cancel, updates
+	iterations := options.IterationCount(len(data))
+	model.StartRun(id, iterations, iterations*len(requests))
+
+	name := model.RunTargetName()
+	go func() {
+		report := runner.Run(ctx, name, requests, options, data, func(result models.RunResult) {
+			result.RunID = id
+			updates <- result
+		})
+		report.RunID = id
+		updates <- report
+	}()
+	return h.waitForRun()
+}
+
+// waitForRun возвращает команду, ожидающую следующее сообщение запуска
+func (h *EventHandler) waitForRun() tea.Cmd {
+	updates := h.runUpdates
+	if updates == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// startLoad запускает нагрузочный тест выбранного запроса. Тест выполняется
+// в горутине и передает снимки статистики через канал, который читает waitForLoad.
+func (h *EventHandler) startLoad(model *models.AppModel) tea.Cmd {
+	if h.loadCancel != nil {
+		model.SetNotice("Нагрузочный тест уже выполняется (ctrl+x на вкладке \"Нагрузка\" - остановить)")
+		return nil
+	}
+	sr, err := model.LoadRequest()
+	if err != nil {
+		model.SetNotice("Тест невозможен: " + err.Error())
+		return nil
+	}
+
+	options := model.GetLoadOptions()
+	tester := &httpclient.LoadTester{
+		Client:    h.httpClient,
+		Settings:  model.GetClientSettings(),
+		Cookies:   model.CookieJar(),
+		Variables: model.GetActiveVariables(),
+	}
+	h.nextLoadID++
+	id := h.nextLoadID
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan models.LoadStats)
+	h.loadCancel, h.loadUpdates = cancel, updates
+	stats := models.NewLoadStats(sr.Name, models.MethodNames[sr.Method], sr.URL, options)
+	stats.LoadID = id
+	model.StartLoad(stats)
+
+	go func() {
+		result := tester.Run(ctx, sr, options, func(snapshot models.LoadStats) {
+			snapshot.LoadID = id
+			updates <- snapshot
+		})
+		result.LoadID = id
+		updates <- result
+	}()
+	return h.waitForLoad()
+}
+
+// waitForLoad возвращает команду, ожидающую следующий снимок статистики теста
+func (h *EventHandler) waitForLoad() tea.Cmd {
+	updates := h.loadUpdates
+	if updates == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// cancelRequest отменяет запрос активной вкладки
+func (h *EventHandler) cancelRequest(model *models.AppModel) {
+	if !model.GetLoading() {
+		return
+	}
+	if cancel, ok := h.inFlight[model.GetActiveRequestID()]; ok {
+		cancel()
+	}
+}
+
+// closeSession закрывает активную вкладку запроса, отменяя ее выполняемый запрос
+func (h *EventHandler) closeSession(model *models.AppModel) {
+	h.cancelRequest(model)
+	model.CloseSession()
+}
+
+// importCurl заменяет текущий запрос командой curl из поля URL
+func (h *EventHandler) importCurl(model *models.AppModel) {
+	sr, err := converter.ParseCurl(model.URLInputValue())
+	if err != nil {
+		model.SetNotice("Ошибка импорта curl: " + err.Error())
+		return
+	}
+	model.ImportRequest(sr)
+	model.SetNotice("Запрос импортирован из curl")
+}
+
+// exportCurl показывает текущий или выбранный сохраненный запрос как команду curl
+// и копирует ее в буфер обмена
+func (h *EventHandler) exportCurl(model *models.AppModel) {
+	var build func() string
+	switch model.GetActiveTab() {
+	case models.TabRequest:
+		build = func() string {
+			req := httpclient.NewHTTPRequest(model)
+			return converter.ToCurl(&req)
+		}
+	case models.TabSaved:
+		sr, ok := model.GetSelectedSavedRequest()
+		if !ok {
+			return
+		}
+		build = func() string {
+			req := httpclient.NewHTTPRequestFromSaved(sr, model.GetActiveVariables())
+			return converter.ToCurl(&req)
+		}
+	default:
+		return
+	}
+
+	h.showPreview(model, "Команда curl", build)
+	if err := clipboard.WriteAll(model.GetPreview()); err != nil {
+		model.SetNotice("Не удалось скопировать в буфер обмена: " + err.Error())
+		return
+	}
+	model.SetNotice("Команда curl скопирована в буфер обмена")
+}
+
+// importFile добавляет в сохраненные запросы из файла Postman, OpenAPI, HAR или .http
+func (h *EventHandler) importFile(model *models.AppModel, path string) {
+	result, err := converter.ImportFile(path)
+	if err != nil {
+		model.SetNotice("Ошибка импорта: " + err.Error())
+		return
+	}
+	folder := result.Folder()
+	model.AddFolder(folder)
+
+	notice := fmt.Sprintf("Импортировано запросов в папку %q: %d", folder.Name, folder.Count())
+	if len(result.Variables) > 0 {
+		if err := model.MergeEnvironmentVariables(result.Name, result.Variables); err != nil {
+			notice += "; не удалось сохранить окружение: " + err.Error()
+		} else {
+			notice += fmt.Sprintf("; переменные сохранены в окружение %q", result.Name)
+		}
+	}
+	if len(result.Warnings) > 0 {
+		notice += fmt.Sprintf("; предупреждений: %d (%s)", len(result.Warnings), result.Warnings[0])
+	}
+	model.SetNotice(notice)
+}
+
+// exportHAR сохраняет в файл HAR текущую пару запрос/ответ (вкладка "Ответ")
+// или всю историю (вкладка "История")
+func (h *EventHandler) exportHAR(model *models.AppModel) {
+	var entries []models.HistoryEntry
+	switch model.GetActiveTab() {
+	case models.TabResponse:
+		data := model.GetResponseData()
+		if data.Timestamp.IsZero() {
+			model.SetNotice("Нет ответа для экспорта")
+			return
+		}
+		entries = []models.HistoryEntry{models.NewHistoryEntry(data)}
+	case models.TabHistory:
+		entries = model.GetHistory()
+		if len(entries) == 0 {
+			model.SetNotice("История пуста")
+			return
+		}
+	default:
+		return
+	}
+
+	data, err := converter.ExportHAR(entries)
+	if err != nil {
+		model.SetNotice("Ошибка экспорта HAR: " + err.Error())
+		return
+	}
+	path := fmt.Sprintf("postui-%s.har", time.Now().Format("20060102-150405"))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		model.SetNotice("Ошибка экспорта HAR: " + err.Error())
+		return
+	}
+	model.SetNotice("HAR сохранен в " + path)
+}
+
+// exportRunReports сохраняет итоги завершенного запуска в отчеты JUnit XML и JSON
+func (h *EventHandler) exportRunReports(model *models.AppModel) {
+	report := model.GetRunReport()
+	if !report.Finished || len(report.Results) == 0 {
+		model.SetNotice("Нет завершенного запуска для отчета")
+		return
+	}
+
+	base := fmt.Sprintf("postui-run-%s", time.Now().Format("20060102-150405"))
+	junit, err := converter.ExportJUnit(report)
+	if err == nil {
+		err = os.WriteFile(base+".xml", junit, 0644)
+	}
+	if err != nil {
+		model.SetNotice("Ошибка сохранения отчета JUnit: " + err.Error())
+		return
+	}
+	data, err := converter.ExportRunReport(report)
+	if err == nil {
+		err = os.WriteFile(base+".json", data, 0644)
+	}
+	if err != nil {
+		model.SetNotice("Ошибка сохранения отчета JSON: " + err.Error())
+		return
+	}
+	model.SetNotice(fmt.Sprintf("Отчеты сохранены в %s.xml и %s.json", base, base))
+}
+
+// exportLoadReport сохраняет итоги завершенного нагрузочного теста в отчет JSON
+func (h *EventHandler) exportLoadReport(model *models.AppModel) {
+	stats := model.GetLoadStats()
+	if !stats.Finished {
+		model.SetNotice("Нет завершенного нагрузочного теста для отчета")
+		return
+	}
+	data, err := converter.ExportLoadReport(stats)
+	path := fmt.Sprintf("postui-load-%s.json", time.Now().Format("20060102-150405"))
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		model.SetNotice("Ошибка сохранения отчета: " + err.Error())
+		return
+	}
+	model.SetNotice("Отчет сохранен в " + path)
+}
+
+// isFiltering сообщает, вводится ли сейчас фильтр в списке активной вкладки
+func (h *EventHandler) isFiltering(model *models.AppModel) bool {
+	switch model.GetActiveTab() {
+	case models.TabSaved:
+		return model.GetSavedList().FilterState() == list.Filtering
+	case models.TabHistory:
+		return model.GetHistoryList().FilterState() == list.Filtering
+	case models.TabCookies:
+		return model.GetCookieList().FilterState() == list.Filtering
+	}
+	return false
+}
+
 func (h *EventHandler) updateFocus(model *models.AppModel) {
 	model.GetURLInput().Blur()
 	model.GetHeaderInput().Blur()
 	model.GetBodyInput().Blur()
 	model.GetParamInput().Blur()
+	model.GetAuthInput().Blur()
 
 	if model.GetInputMode() && model.GetActiveTab() == models.TabRequest {
 		switch model.GetActiveSection() {
@@ -273,6 +1356,8 @@ func (h *EventHandler) updateFocus(model *models.AppModel) {
 			model.GetBodyInput().Focus()
 		case models.SectionParams:
 			model.GetParamInput().Focus()
+		case models.SectionAuth:
+			model.GetAuthInput().Focus()
 		}
 	}
 }
@@ -297,6 +1382,9 @@ func (h *EventHandler) UpdateComponents(model *models.AppModel, msg tea.Msg) (*m
 			case models.SectionParams:
 				*model.GetParamInput(), cmd = model.GetParamInput().Update(msg)
 				cmds = append(cmds, cmd)
+			case models.SectionAuth:
+				*model.GetAuthInput(), cmd = model.GetAuthInput().Update(msg)
+				cmds = append(cmds, cmd)
 			}
 		}
 	case models.TabResponse:
@@ -305,6 +1393,19 @@ func (h *EventHandler) UpdateComponents(model *models.AppModel, msg tea.Msg) (*m
 	case models.TabSaved:
 		*model.GetSavedList(), cmd = model.GetSavedList().Update(msg)
 		cmds = append(cmds, cmd)
+		model.SyncSavedTree()
+	case models.TabHistory:
+		*model.GetHistoryList(), cmd = model.GetHistoryList().Update(msg)
+		cmds = append(cmds, cmd)
+	case models.TabCookies:
+		*model.GetCookieList(), cmd = model.GetCookieList().Update(msg)
+		cmds = append(cmds, cmd)
+	case models.TabRunner:
+		*model.GetRunVP(), cmd = model.GetRunVP().Update(msg)
+		cmds = append(cmds, cmd)
+	case models.TabLoad:
+		*model.GetLoadVP(), cmd = model.GetLoadVP().Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	return model, tea.Batch(cmds...)
@@ -0,0 +1,356 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// tokenRefreshMargin - за сколько до истечения срока токен OAuth2 считается устаревшим
+const tokenRefreshMargin = 30 * time.Second
+
+// applyAuth добавляет к запросу заголовки и параметры авторизации, не требующие
+// обращения к серверу (Basic, Bearer, API Key). Явно заданная авторизация
+// заменяет одноименный заголовок запроса.
+func (r *HTTPRequest) applyAuth() {
+	auth := r.Auth
+	switch auth.Type {
+	case models.AuthBasic:
+		credentials := base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+		r.setHeader("Authorization", "Basic "+credentials)
+	case models.AuthBearer:
+		r.setHeader("Authorization", "Bearer "+auth.Token)
+	case models.AuthAPIKey:
+		if auth.Key == "" {
+			return
+		}
+		if strings.EqualFold(auth.In, models.APIKeyInQuery) {
+			r.Params = append(r.Params, models.Param{Key: auth.Key, Value: auth.Value})
+		} else {
+			r.setHeader(auth.Key, auth.Value)
+		}
+	}
+}
+
+// setHeader заменяет все заголовки с указанным именем одним значением
+func (r *HTTPRequest) setHeader(key, value string) {
+	headers := r.Headers[:0:0]
+	for _, h := range r.Headers {
+		if !strings.EqualFold(h.Key, key) {
+			headers = append(headers, h)
+		}
+	}
+	r.Headers = append(headers, models.Header{Key: key, Value: value})
+}
+
+// --- OAuth2 ---
+
+// oauthToken хранит полученный токен доступа OAuth2
+type oauthToken struct {
+	accessToken  string
+	refreshToken string
+	expires      time.Time // нулевое значение, если сервер не указал срок действия
+}
+
+func (t oauthToken) valid() bool {
+	return t.expires.IsZero() || time.Now().Add(tokenRefreshMargin).Before(t.expires)
+}
+
+// tokenCache хранит токены OAuth2 между запросами. Запросы выполняются
+// параллельно, поэтому доступ к кэшу защищен мьютексом, а получение токена
+// с одним ключом выполняется только одним запросом одновременно.
+type tokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]oauthToken
+	locks  map[string]chan struct{}
+}
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{tokens: map[string]oauthToken{}, locks: map[string]chan struct{}{}}
+}
+
+func (c *tokenCache) get(key string) (oauthToken, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	token, ok := c.tokens[key]
+	return token, ok
+}
+
+func (c *tokenCache) put(key string, token oauthToken) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[key] = token
+}
+
+// lock захватывает ключ на время получения токена и возвращает функцию освобождения.
+// Ожидание прерывается отменой контекста.
+func (c *tokenCache) lock(ctx context.Context, key string) (func(), error) {
+	c.mu.Lock()
+	l, ok := c.locks[key]
+	if !ok {
+		l = make(chan struct{}, 1)
+		c.locks[key] = l
+	}
+	c.mu.Unlock()
+
+	select {
+	case l <- struct{}{}:
+		return func() { <-l }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// tokenKey возвращает ключ кэша токена: хэш всех данных, по которым он получен,
+// чтобы смена секрета или пароля приводила к новому запросу токена
+func tokenKey(auth models.Auth, grant string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		auth.TokenURL, grant, auth.ClientID, auth.ClientSecret, auth.Scope, auth.Username, auth.Password,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// oauth2Token возвращает действующий токен доступа: из кэша, обновленный
+// по refresh_token или полученный заново
+func (c *HTTPClient) oauth2Token(ctx context.Context, client *http.Client, auth models.Auth) (string, error) {
+	if auth.TokenURL == "" {
+		return "", fmt.Errorf("не указан token_url")
+	}
+	grant := auth.Grant
+	if grant == "" {
+		grant = models.GrantClientCredentials
+	}
+	key := tokenKey(auth, grant)
+
+	// Параллельные запросы ждут токен, полученный первым из них
+	unlock, err := c.tokens.lock(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	cached, ok := c.tokens.get(key)
+	if ok && cached.valid() {
+		return cached.accessToken, nil
+	}
+	if ok && cached.refreshToken != "" {
+		form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {cached.refreshToken}}
+		if token, err := c.requestToken(ctx, client, auth, form); err == nil {
+			if token.refreshToken == "" {
+				token.refreshToken = cached.refreshToken
+			}
+			c.tokens.put(key, token)
+			return token.accessToken, nil
+		}
+		// Если обновить токен не удалось, запрашиваем новый
+	}
+
+	form := url.Values{"grant_type": {grant}}
+	switch grant {
+	case models.GrantClientCredentials:
+	case models.GrantPassword:
+		form.Set("username", auth.Username)
+		form.Set("password", auth.Password)
+	default:
+		return "", fmt.Errorf("неподдерживаемый тип получения токена %q", grant)
+	}
+	token, err := c.requestToken(ctx, client, auth, form)
+	if err != nil {
+		return "", err
+	}
+	c.tokens.put(key, token)
+	return token.accessToken, nil
+}
+
+// requestToken запрашивает токен у сервера авторизации (RFC 6749, раздел 4).
+// Данные клиента передаются в заголовке Authorization по схеме Basic.
+func (c *HTTPClient) requestToken(ctx context.Context, client *http.Client, auth models.Auth, form url.Values) (oauthToken, error) {
+	if auth.Scope != "" {
+		form.Set("scope", auth.Scope)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, auth.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oauthToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if auth.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(auth.ClientID), url.QueryEscape(auth.ClientSecret))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return oauthToken{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return oauthToken{}, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return oauthToken{}, fmt.Errorf("сервер авторизации вернул %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return oauthToken{}, fmt.Errorf("неверный ответ сервера авторизации: %w", err)
+	}
+	if payload.AccessToken == "" {
+		return oauthToken{}, fmt.Errorf("сервер авторизации не вернул access_token")
+	}
+	token := oauthToken{accessToken: payload.AccessToken, refreshToken: payload.RefreshToken}
+	if payload.ExpiresIn > 0 {
+		token.expires = time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// --- Digest ---
+
+// digestChallenge содержит параметры заголовка WWW-Authenticate схемы Digest (RFC 7616)
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+}
+
+// parseDigestChallenge ищет среди заголовков WWW-Authenticate вызов схемы Digest
+func parseDigestChallenge(values []string) (digestChallenge, bool) {
+	for _, value := range values {
+		scheme, rest, _ := strings.Cut(strings.TrimSpace(value), " ")
+		if !strings.EqualFold(scheme, "Digest") {
+			continue
+		}
+		params := parseAuthParams(rest)
+		challenge := digestChallenge{
+			realm:     params["realm"],
+			nonce:     params["nonce"],
+			opaque:    params["opaque"],
+			algorithm: params["algorithm"],
+		}
+		// Из предложенных вариантов qop выбираем auth, если он есть
+		for _, qop := range strings.Split(params["qop"], ",") {
+			qop = strings.TrimSpace(qop)
+			if qop == "auth" || (qop == "auth-int" && challenge.qop == "") {
+				challenge.qop = qop
+			}
+		}
+		return challenge, challenge.nonce != ""
+	}
+	return digestChallenge{}, false
+}
+
+// parseAuthParams разбирает список параметров вида name=token, name="quoted string"
+func parseAuthParams(input string) map[string]string {
+	params := map[string]string{}
+	for input != "" {
+		input = strings.TrimLeft(input, " \t,")
+		name, rest, ok := strings.Cut(input, "=")
+		if !ok {
+			break
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		rest = strings.TrimLeft(rest, " \t")
+
+		var value strings.Builder
+		if strings.HasPrefix(rest, `"`) {
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				value.WriteByte(rest[i])
+			}
+			input = rest[min(i+1, len(rest)):]
+		} else {
+			end := strings.IndexByte(rest, ',')
+			if end < 0 {
+				end = len(rest)
+			}
+			value.WriteString(strings.TrimSpace(rest[:end]))
+			input = rest[end:]
+		}
+		params[name] = value.String()
+	}
+	return params
+}
+
+// authorize вычисляет значение заголовка Authorization для ответа на вызов
+func (d digestChallenge) authorize(auth models.Auth, method, uri string, body []byte) (string, error) {
+	algorithm := strings.ToUpper(d.algorithm)
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(algorithm, "-SESS") {
+	case "", "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("неподдерживаемый алгоритм Digest %q", d.algorithm)
+	}
+	h := func(parts ...string) string {
+		sum := newHash()
+		sum.Write([]byte(strings.Join(parts, ":")))
+		return hex.EncodeToString(sum.Sum(nil))
+	}
+
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return "", err
+	}
+	cnonce := hex.EncodeToString(nonceBytes)
+	const nc = "00000001"
+
+	ha1 := h(auth.Username, d.realm, auth.Password)
+	if strings.HasSuffix(algorithm, "-SESS") {
+		ha1 = h(ha1, d.nonce, cnonce)
+	}
+	ha2 := h(method, uri)
+	if d.qop == "auth-int" {
+		ha2 = h(method, uri, h(string(body)))
+	}
+
+	var response string
+	if d.qop == "" {
+		response = h(ha1, d.nonce, ha2)
+	} else {
+		response = h(ha1, d.nonce, nc, cnonce, d.qop, ha2)
+	}
+
+	fields := []string{
+		fmt.Sprintf("username=%q", auth.Username),
+		fmt.Sprintf("realm=%q", d.realm),
+		fmt.Sprintf("nonce=%q", d.nonce),
+		fmt.Sprintf("uri=%q", uri),
+		fmt.Sprintf("response=%q", response),
+	}
+	if d.algorithm != "" {
+		fields = append(fields, "algorithm="+d.algorithm)
+	}
+	if d.opaque != "" {
+		fields = append(fields, fmt.Sprintf("opaque=%q", d.opaque))
+	}
+	if d.qop != "" {
+		fields = append(fields, "qop="+d.qop, "nc="+nc, fmt.Sprintf("cnonce=%q", cnonce))
+	}
+	return "Digest " + strings.Join(fields, ", "), nil
+}
@@ -0,0 +1,328 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// tokenServer - сервер авторизации OAuth2, выдающий токены token-1, token-2, ...
+type tokenServer struct {
+	*httptest.Server
+	expiresIn    int64
+	failRefresh  bool
+	mu           sync.Mutex
+	grants       []string // grant_type запросов в порядке поступления
+	refreshToken string   // refresh_token последнего запроса обновления
+}
+
+func newTokenServer(t *testing.T, expiresIn int64) *tokenServer {
+	s := &tokenServer{expiresIn: expiresIn}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, ok := r.BasicAuth(); !ok || id != "client" || secret != "secret" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("неверное тело запроса токена: %v", err)
+		}
+		if scope := r.PostForm.Get("scope"); scope != "read" {
+			t.Errorf("scope = %q, ожидался read", scope)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		grant := r.PostForm.Get("grant_type")
+		s.grants = append(s.grants, grant)
+		if grant == "refresh_token" {
+			s.refreshToken = r.PostForm.Get("refresh_token")
+			if s.failRefresh {
+				http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+				return
+			}
+		}
+		payload := map[string]any{
+			"access_token": fmt.Sprintf("token-%d", len(s.grants)),
+			"token_type":   "Bearer",
+			"expires_in":   s.expiresIn,
+		}
+		if grant != "refresh_token" {
+			payload["refresh_token"] = "refresh"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(payload)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *tokenServer) requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.grants...)
+}
+
+// authEchoServer возвращает в теле ответа заголовок Authorization запроса
+func authEchoServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Authorization"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func sendOAuth2(t *testing.T, client *HTTPClient, api, tokenURL string) string {
+	t.Helper()
+	req := HTTPRequest{
+		Method: "GET",
+		URL:    api,
+		Auth: models.Auth{
+			Type:         models.AuthOAuth2,
+			TokenURL:     tokenURL,
+			ClientID:     "client",
+			ClientSecret: "secret",
+			Scope:        "read",
+		},
+	}
+	data, err := client.SendRequest(context.Background(), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data.Body
+}
+
+func TestOAuth2ClientCredentialsCachesToken(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	api := authEchoServer(t)
+	client := NewHTTPClient()
+
+	for i := 0; i < 3; i++ {
+		if got := sendOAuth2(t, client, api.URL, tokens.URL); got != "Bearer token-1" {
+			t.Errorf("запрос %d: Authorization = %q, ожидалось Bearer token-1", i+1, got)
+		}
+	}
+	if got := tokens.requests(); len(got) != 1 || got[0] != "client_credentials" {
+		t.Errorf("запросы токена = %v, ожидался один client_credentials", got)
+	}
+
+	// Кэш принадлежит клиенту: другой клиент получает свой токен
+	if got := sendOAuth2(t, NewHTTPClient(), api.URL, tokens.URL); got != "Bearer token-2" {
+		t.Errorf("Authorization нового клиента = %q, ожидалось Bearer token-2", got)
+	}
+}
+
+func TestOAuth2ConcurrentRequestsShareToken(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	api := authEchoServer(t)
+	client := NewHTTPClient()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := sendOAuth2(t, client, api.URL, tokens.URL); got != "Bearer token-1" {
+				t.Errorf("Authorization = %q, ожидалось Bearer token-1", got)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := tokens.requests(); len(got) != 1 {
+		t.Errorf("запросы токена = %v, ожидался один", got)
+	}
+}
+
+func TestOAuth2TokenKeyIncludesSecrets(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	api := authEchoServer(t)
+	client := NewHTTPClient()
+
+	send := func(password string) string {
+		t.Helper()
+		req := HTTPRequest{
+			Method: "GET",
+			URL:    api.URL,
+			Auth: models.Auth{
+				Type: models.AuthOAuth2, Grant: models.GrantPassword, TokenURL: tokens.URL,
+				ClientID: "client", ClientSecret: "secret", Scope: "read", Username: "bob", Password: password,
+			},
+		}
+		data, err := client.SendRequest(context.Background(), &req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data.Body
+	}
+	// Токен, полученный со старым паролем, после его смены не используется
+	for i, tc := range []struct{ password, want string }{
+		{"old", "Bearer token-1"},
+		{"new", "Bearer token-2"},
+		{"new", "Bearer token-2"},
+		{"old", "Bearer token-1"},
+	} {
+		if got := send(tc.password); got != tc.want {
+			t.Errorf("запрос %d с паролем %s: Authorization = %q, ожидалось %q", i+1, tc.password, got, tc.want)
+		}
+	}
+	if key := tokenKey(models.Auth{ClientSecret: "a"}, ""); key == tokenKey(models.Auth{ClientSecret: "b"}, "") {
+		t.Error("ключ кэша не зависит от client_secret")
+	}
+}
+
+func TestOAuth2RefreshesExpiringToken(t *testing.T) {
+	// Срок действия меньше tokenRefreshMargin: токен устаревает сразу после получения
+	tokens := newTokenServer(t, 10)
+	api := authEchoServer(t)
+	client := NewHTTPClient()
+
+	if got := sendOAuth2(t, client, api.URL, tokens.URL); got != "Bearer token-1" {
+		t.Fatalf("Authorization = %q, ожидалось Bearer token-1", got)
+	}
+	if got := sendOAuth2(t, client, api.URL, tokens.URL); got != "Bearer token-2" {
+		t.Errorf("Authorization после обновления = %q, ожидалось Bearer token-2", got)
+	}
+	// Сервер не вернул новый refresh_token при обновлении - используется прежний
+	if got := sendOAuth2(t, client, api.URL, tokens.URL); got != "Bearer token-3" {
+		t.Errorf("Authorization после второго обновления = %q, ожидалось Bearer token-3", got)
+	}
+	tokens.mu.Lock()
+	if tokens.refreshToken != "refresh" {
+		t.Errorf("refresh_token = %q, ожидался refresh", tokens.refreshToken)
+	}
+	// Если обновить токен не удалось, он запрашивается заново
+	tokens.failRefresh = true
+	tokens.mu.Unlock()
+	if got := sendOAuth2(t, client, api.URL, tokens.URL); got != "Bearer token-5" {
+		t.Errorf("Authorization после ошибки обновления = %q, ожидалось Bearer token-5", got)
+	}
+	want := []string{"client_credentials", "refresh_token", "refresh_token", "refresh_token", "client_credentials"}
+	if got := tokens.requests(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("запросы токена = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestOAuth2TokenError(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	req := HTTPRequest{
+		Method: "GET",
+		URL:    authEchoServer(t).URL,
+		Auth:   models.Auth{Type: models.AuthOAuth2, TokenURL: tokens.URL, ClientID: "client", ClientSecret: "wrong"},
+	}
+	_, err := NewHTTPClient().SendRequest(context.Background(), &req)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("ошибка = %v, ожидался ответ 401 сервера авторизации", err)
+	}
+}
+
+// digestServer проверяет ответ на вызов Digest по RFC 7616 для пользователя alice:secret
+func digestServer(t *testing.T, algorithm, qop string, requests *int) *httptest.Server {
+	const realm, nonce, opaque = "test@example.com", "dcd98b7102dd2f0e8b11d0f600bfb0c093", "5ccc069c403ebaf9f0171e9517f40e41"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		scheme, rest, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if scheme != "Digest" {
+			challenge := fmt.Sprintf(`Digest realm=%q, nonce=%q, opaque=%q, algorithm=%s`, realm, nonce, opaque, algorithm)
+			if qop != "" {
+				challenge += fmt.Sprintf(", qop=%q", qop)
+			}
+			w.Header().Add("WWW-Authenticate", `Basic realm="other"`)
+			w.Header().Add("WWW-Authenticate", challenge)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		params := parseAuthParams(rest)
+		newHash := md5.New
+		if algorithm == "SHA-256" {
+			newHash = sha256.New
+		}
+		h := func(parts ...string) string {
+			var sum hash.Hash = newHash()
+			sum.Write([]byte(strings.Join(parts, ":")))
+			return hex.EncodeToString(sum.Sum(nil))
+		}
+		ha1 := h(params["username"], realm, "secret")
+		ha2 := h(r.Method, r.URL.RequestURI())
+		want := h(ha1, nonce, ha2)
+		if qop != "" {
+			want = h(ha1, nonce, params["nc"], params["cnonce"], params["qop"], ha2)
+		}
+
+		switch {
+		case params["realm"] != realm || params["nonce"] != nonce || params["opaque"] != opaque:
+			t.Errorf("параметры вызова не переданы: %v", params)
+		case params["uri"] != r.URL.RequestURI():
+			t.Errorf("uri = %q, ожидался %q", params["uri"], r.URL.RequestURI())
+		case qop != "" && (params["qop"] != "auth" || params["nc"] != "00000001" || params["cnonce"] == ""):
+			t.Errorf("параметры qop = %v", params)
+		}
+		if params["username"] != "alice" || params["response"] != want {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDigestAuth(t *testing.T) {
+	for _, tc := range []struct {
+		algorithm, qop string
+	}{
+		{"MD5", "auth"},
+		{"MD5", ""},
+		{"SHA-256", "auth,auth-int"},
+	} {
+		t.Run(tc.algorithm+"/"+tc.qop, func(t *testing.T) {
+			var requests int
+			server := digestServer(t, tc.algorithm, tc.qop, &requests)
+			req := HTTPRequest{
+				Method: "POST",
+				URL:    server.URL + "/dir/index.html",
+				Params: []models.Param{{Key: "q", Value: "a b"}},
+				Body:   []byte(`{"a":1}`),
+				Auth:   models.Auth{Type: models.AuthDigest, Username: "alice", Password: "secret"},
+			}
+			data, err := NewHTTPClient().SendRequest(context.Background(), &req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data.StatusCode != http.StatusOK || data.Body != "ok" {
+				t.Errorf("ответ = %d %q, ожидался 200 ok", data.StatusCode, data.Body)
+			}
+			if requests != 2 {
+				t.Errorf("запросов к серверу = %d, ожидалось 2 (вызов и ответ на него)", requests)
+			}
+		})
+	}
+}
+
+func TestDigestAuthWrongPassword(t *testing.T) {
+	var requests int
+	server := digestServer(t, "MD5", "auth", &requests)
+	req := HTTPRequest{
+		Method: "GET",
+		URL:    server.URL,
+		Auth:   models.Auth{Type: models.AuthDigest, Username: "alice", Password: "wrong"},
+	}
+	data, err := NewHTTPClient().SendRequest(context.Background(), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ответ на вызов отправляется один раз, повторный 401 возвращается как есть
+	if data.StatusCode != http.StatusUnauthorized || requests != 2 {
+		t.Errorf("ответ = %d после %d запросов, ожидался 401 после 2", data.StatusCode, requests)
+	}
+}
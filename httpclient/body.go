@@ -0,0 +1,124 @@
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// quoteEscaper экранирует кавычки в параметрах Content-Disposition, как mime/multipart
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// setBody заполняет тело запроса в соответствии с типом тела сохраненного запроса.
+// Тела multipart и binary читают файлы, поэтому формируются при отправке (см. payload).
+func (r *HTTPRequest) setBody(sr models.SavedRequest, vars map[string]string) {
+	r.BodyType = sr.BodyType
+	switch sr.BodyType {
+	case models.BodyNone:
+	case models.BodyForm:
+		r.Form = models.ExpandFormFields(sr.Form, vars)
+		// Порядок полей сохраняется, поэтому url.Values.Encode (сортирующий ключи) не используется
+		pairs := make([]string, len(r.Form))
+		for i, f := range r.Form {
+			pairs[i] = url.QueryEscape(f.Key) + "=" + url.QueryEscape(f.Value)
+		}
+		r.Body = []byte(strings.Join(pairs, "&"))
+		if !strings.Contains(strings.ToLower(r.header("Content-Type")), "x-www-form-urlencoded") {
+			r.setHeader("Content-Type", "application/x-www-form-urlencoded")
+		}
+	case models.BodyMultipart:
+		// Content-Type с границей частей задается при отправке
+		r.Form = models.ExpandFormFields(sr.Form, vars)
+	case models.BodyBinary:
+		r.BodyFile = strings.TrimSpace(models.ExpandVariables(sr.Body, vars))
+		if r.header("Content-Type") == "" {
+			r.setHeader("Content-Type", fileContentType(r.BodyFile, ""))
+		}
+	default:
+		if sr.Body != "" {
+			r.Body = []byte(models.ExpandVariables(sr.Body, vars))
+		}
+		if sr.BodyType == models.BodyJSON && r.header("Content-Type") == "" {
+			r.setHeader("Content-Type", "application/json")
+		}
+	}
+}
+
+// payload возвращает тело для отправки. Для multipart также возвращается
+// Content-Type с границей частей, заменяющий заголовок запроса.
+func (r *HTTPRequest) payload() (body []byte, contentType string, err error) {
+	switch r.BodyType {
+	case models.BodyBinary:
+		if r.BodyFile == "" {
+			return nil, "", fmt.Errorf("не указан файл тела запроса")
+		}
+		body, err = os.ReadFile(r.BodyFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("не удалось прочитать файл тела: %w", err)
+		}
+		return body, "", nil
+	case models.BodyMultipart:
+		return encodeMultipart(r.Form)
+	}
+	return r.Body, "", nil
+}
+
+// encodeMultipart формирует тело multipart/form-data, читая файлы полей
+func encodeMultipart(fields []models.FormField) ([]byte, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, f := range fields {
+		if !f.File {
+			if err := writer.WriteField(f.Key, f.Value); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+		content, err := os.ReadFile(f.Value)
+		if err != nil {
+			return nil, "", fmt.Errorf("поле %s: не удалось прочитать файл: %w", f.Key, err)
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(f.Key), quoteEscaper.Replace(filepath.Base(f.Value))))
+		header.Set("Content-Type", fileContentType(f.Value, f.ContentType))
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		part.Write(content)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// fileContentType возвращает явно заданный тип содержимого или определяет его по расширению файла
+func fileContentType(path, explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(path)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// header возвращает значение первого заголовка с указанным именем
+func (r *HTTPRequest) header(key string) string {
+	for _, h := range r.Headers {
+		if strings.EqualFold(h.Key, key) {
+			return h.Value
+		}
+	}
+	return ""
+}
@@ -2,9 +2,14 @@ package httpclient
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/KharpukhaevV/postui/models"
@@ -12,86 +17,400 @@ import (
 
 // HTTPClient обрабатывает HTTP запросы
 type HTTPClient struct {
-	client *http.Client
+	settings models.ClientSettings // общие настройки, которые дополняет запрос
+	clients  *clientPool
+	tokens   *tokenCache // токены OAuth2, полученные этим клиентом
 }
 
+// redirectsKey ключ контекста, по которому хранится цепочка перенаправлений запроса
+type redirectsKey struct{}
+
 // NewHTTPClient создает новый HTTP клиент с настройками по умолчанию
 func NewHTTPClient() *HTTPClient {
+	return NewHTTPClientWithSettings(models.ClientSettings{})
+}
+
+// NewHTTPClientWithSettings создает HTTP клиент с общими настройками,
+// дополняющими настройки по умолчанию. Настройки запроса имеют приоритет над общими.
+func NewHTTPClientWithSettings(settings models.ClientSettings) *HTTPClient {
 	return &HTTPClient{
-		client: &http.Client{Timeout: 30 * time.Second},
+		settings: models.DefaultClientSettings().Merge(settings),
+		clients:  newClientPool(),
+		tokens:   newTokenCache(),
+	}
+}
+
+// recordRedirect возвращает функцию CheckRedirect, которая ограничивает количество
+// перенаправлений и сохраняет каждое из них в цепочку из контекста запроса.
+// Если перенаправления отключены, возвращается ответ с кодом 3xx.
+func recordRedirect(follow bool, maxRedirects int) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if !follow {
+			return http.ErrUseLastResponse
+		}
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("превышено количество перенаправлений (%d)", maxRedirects)
+		}
+		if hops, ok := req.Context().Value(redirectsKey{}).(*[]models.RedirectHop); ok && req.Response != nil {
+			previous := via[len(via)-1]
+			*hops = append(*hops, models.RedirectHop{
+				Method:     previous.Method,
+				URL:        previous.URL.String(),
+				Status:     req.Response.Status,
+				StatusCode: req.Response.StatusCode,
+				Location:   req.URL.String(),
+				Headers:    sortedHeaders(req.Response.Header),
+			})
+		}
+		return nil
 	}
 }
 
-// SendRequest отправляет HTTP запрос и возвращает данные ответа
-func (c *HTTPClient) SendRequest(req *HTTPRequest) (models.ResponseData, error) {
+// SendRequest отправляет HTTP запрос и возвращает данные ответа.
+// Отмена ctx прерывает запрос на любом этапе, включая чтение тела.
+// Токен OAuth2 запрашивается перед отправкой, на вызов Digest запрос повторяется с ответом.
+func (c *HTTPClient) SendRequest(ctx context.Context, req *HTTPRequest) (models.ResponseData, error) {
 	start := time.Now()
 
-	parsedURL, err := url.Parse(req.URL)
+	fullURL, err := req.BuildURL()
 	if err != nil {
-		return models.ResponseData{}, fmt.Errorf("неверный URL: %w", err)
+		return models.ResponseData{}, err
 	}
 
-	// Добавляем параметры запроса
-	query := parsedURL.Query()
-	for _, p := range req.Params {
-		query.Add(p.Key, p.Value)
+	settings := c.settings.Merge(req.Settings)
+	client, err := c.clients.get(settings)
+	if err != nil {
+		return models.ResponseData{}, fmt.Errorf("неверные настройки клиента: %w", err)
+	}
+	maxBody, err := settings.MaxBodyBytes()
+	if err != nil {
+		return models.ResponseData{}, fmt.Errorf("неверные настройки клиента: %w", err)
+	}
+	if req.Cookies != nil && settings.CookiesEnabled() {
+		// Копия клиента использует тот же транспорт и пул соединений
+		withJar := *client
+		withJar.Jar = cookieJar{jar: req.Cookies}
+		client = &withJar
+	}
+
+	var authorization string
+	if req.Auth.Type == models.AuthOAuth2 {
+		token, err := c.oauth2Token(ctx, client, req.Auth)
+		if err != nil {
+			return models.ResponseData{}, fmt.Errorf("не удалось получить токен OAuth2: %w", err)
+		}
+		authorization = "Bearer " + token
 	}
-	parsedURL.RawQuery = query.Encode()
 
-	// Создаем запрос
-	httpReq, err := http.NewRequest(req.Method, parsedURL.String(), bytes.NewReader(req.Body))
+	payload, contentType, err := req.payload()
 	if err != nil {
-		return models.ResponseData{}, fmt.Errorf("не удалось создать запрос: %w", err)
+		return models.ResponseData{}, err
 	}
 
-	// Добавляем заголовки
+	// Создаем и выполняем запрос
+	var redirects []models.RedirectHop
+	tracer := &phaseTracer{}
+	ctx = context.WithValue(ctx, redirectsKey{}, &redirects)
+	ctx = httptrace.WithClientTrace(ctx, tracer.clientTrace())
+	resp, err := c.do(ctx, client, req, fullURL, payload, contentType, authorization)
+	if err != nil {
+		return models.ResponseData{}, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && req.Auth.Type == models.AuthDigest {
+		if challenge, ok := parseDigestChallenge(resp.Header.Values("WWW-Authenticate")); ok {
+			resp.Body.Close()
+			redirects = nil
+			authorization, err = challenge.authorize(req.Auth, req.Method, requestURI(fullURL), payload)
+			if err != nil {
+				return models.ResponseData{}, err
+			}
+			if resp, err = c.do(ctx, client, req, fullURL, payload, contentType, authorization); err != nil {
+				return models.ResponseData{}, err
+			}
+		}
+	}
+	defer resp.Body.Close()
+
+	// Читаем ответ
+	data := models.ResponseData{Encoding: resp.Header.Get("Content-Encoding")}
+	if err := readBody(resp, maxBody, &data); err != nil {
+		return models.ResponseData{}, fmt.Errorf("не удалось прочитать ответ: %w", err)
+	}
+
+	end := time.Now()
+	elapsed := end.Sub(start).Round(time.Millisecond)
+
+	data.Status = resp.Status
+	data.StatusCode = resp.StatusCode
+	data.Proto = resp.Proto
+	data.URL = resp.Request.URL.String()
+	data.Time = elapsed.String()
+	data.Headers = sortedHeaders(resp.Header)
+	data.Cookies = responseCookies(resp)
+	data.Redirects = redirects
+	data.Timings = tracer.timings(start, end)
+	data.Timestamp = start
+	data.Request = req.Snapshot()
+	data.Settings = settings
+	return data, nil
+}
+
+// do создает и выполняет запрос. Непустые contentType и authorization заменяют
+// одноименные заголовки запроса.
+func (c *HTTPClient) do(ctx context.Context, client *http.Client, req *HTTPRequest, fullURL string, body []byte, contentType, authorization string) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, fullURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать запрос: %w", err)
+	}
 	for _, h := range req.Headers {
 		httpReq.Header.Add(h.Key, h.Value)
 	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	if authorization != "" {
+		httpReq.Header.Set("Authorization", authorization)
+	}
+	if httpReq.Header.Get("Accept-Encoding") == "" {
+		httpReq.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
 
-	// Выполняем запрос
-	resp, err := c.client.Do(httpReq)
+	resp, err := client.Do(httpReq)
 	if err != nil {
-		return models.ResponseData{}, fmt.Errorf("запрос не выполнен: %w", err)
+		return nil, fmt.Errorf("запрос не выполнен: %w", err)
 	}
-	defer resp.Body.Close()
+	return resp, nil
+}
 
-	// Читаем ответ
-	buf := new(bytes.Buffer)
-	buf.ReadFrom(resp.Body)
-	responseBody := buf.String()
+// requestURI возвращает путь и строку запроса URL, как они передаются в строке запроса
+func requestURI(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return parsed.RequestURI()
+}
+
+// phaseTracer запоминает моменты начала и окончания этапов запроса через httptrace
+type phaseTracer struct {
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	gotConn, wroteRequest     time.Time
+	firstByte                 time.Time
+	reused                    bool
+}
 
-	elapsed := time.Since(start).Round(time.Millisecond)
+func (p *phaseTracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		// Каждое перенаправление начинается с получения соединения: учитываем только последний запрос
+		GetConn:  func(string) { *p = phaseTracer{} },
+		DNSStart: func(httptrace.DNSStartInfo) { p.dnsStart = time.Now() },
+		DNSDone:  func(httptrace.DNSDoneInfo) { p.dnsDone = time.Now() },
+		ConnectStart: func(string, string) {
+			if p.connectStart.IsZero() {
+				p.connectStart = time.Now()
+			}
+		},
+		ConnectDone:          func(string, string, error) { p.connectDone = time.Now() },
+		TLSHandshakeStart:    func() { p.tlsStart = time.Now() },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { p.tlsDone = time.Now() },
+		GotConn:              func(info httptrace.GotConnInfo) { p.gotConn, p.reused = time.Now(), info.Reused },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { p.wroteRequest = time.Now() },
+		GotFirstResponseByte: func() { p.firstByte = time.Now() },
+	}
+}
+
+// timings вычисляет длительность этапов; end - момент окончания чтения тела
+func (p *phaseTracer) timings(start, end time.Time) models.Timings {
+	between := func(from, to time.Time) time.Duration {
+		if from.IsZero() || to.IsZero() || to.Before(from) {
+			return 0
+		}
+		return to.Sub(from)
+	}
+	return models.Timings{
+		DNS:      between(p.dnsStart, p.dnsDone),
+		Connect:  between(p.connectStart, p.connectDone),
+		TLS:      between(p.tlsStart, p.tlsDone),
+		Send:     between(p.gotConn, p.wroteRequest),
+		TTFB:     between(p.wroteRequest, p.firstByte),
+		Download: between(p.firstByte, end),
+		Total:    end.Sub(start),
+		Reused:   p.reused,
+	}
+}
 
-	return models.ResponseData{
-		Body:       responseBody,
-		Status:     resp.Status,
-		StatusCode: resp.StatusCode,
-		Time:       elapsed.String(),
-	}, nil
+// sortedHeaders возвращает заголовки, отсортированные по имени
+func sortedHeaders(header http.Header) []models.Header {
+	var headers []models.Header
+	for key, values := range header {
+		for _, value := range values {
+			headers = append(headers, models.Header{Key: key, Value: value})
+		}
+	}
+	sort.SliceStable(headers, func(i, j int) bool { return headers[i].Key < headers[j].Key })
+	return headers
+}
+
+// responseCookies возвращает cookies, установленные ответом
+func responseCookies(resp *http.Response) []models.Cookie {
+	var cookies []models.Cookie
+	for _, c := range resp.Cookies() {
+		cookie := models.Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			MaxAge:   c.MaxAge,
+			Secure:   c.Secure,
+			HTTPOnly: c.HttpOnly,
+		}
+		if !c.Expires.IsZero() {
+			cookie.Expires = c.Expires.Format(time.RFC1123)
+		}
+		switch c.SameSite {
+		case http.SameSiteLaxMode:
+			cookie.SameSite = "Lax"
+		case http.SameSiteStrictMode:
+			cookie.SameSite = "Strict"
+		case http.SameSiteNoneMode:
+			cookie.SameSite = "None"
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies
 }
 
 // HTTPRequest представляет HTTP запрос
 type HTTPRequest struct {
-	Method  string
-	URL     string
-	Headers []models.Header
-	Params  []models.Param
-	Body    []byte
+	Method     string
+	URL        string
+	Headers    []models.Header
+	Params     []models.Param
+	Body       []byte // тело raw, json и form
+	BodyType   models.BodyType
+	Form       []models.FormField    // поля form и multipart с подставленными переменными
+	BodyFile   string                // файл тела binary
+	Auth       models.Auth           // авторизация с подставленными переменными
+	Settings   models.ClientSettings // настройки клиента, переопределяющие общие
+	Cookies    *models.CookieJar     // cookies окружения (nil - не отправлять и не сохранять)
+	Assertions []models.Assertion    // проверки ответа с подставленными переменными (см. CheckResponse)
+	Extract    []models.Extraction   // правила извлечения переменных из ответа
 }
 
-// NewHTTPRequest создает новый HTTP запрос из модели приложения
+// BuildURL возвращает URL запроса с добавленными параметрами
+func (r *HTTPRequest) BuildURL() (string, error) {
+	parsedURL, err := url.Parse(r.URL)
+	if err != nil {
+		return "", fmt.Errorf("неверный URL: %w", err)
+	}
+
+	// Добавляем параметры запроса
+	if len(r.Params) > 0 {
+		query := parsedURL.Query()
+		for _, p := range r.Params {
+			query.Add(p.Key, p.Value)
+		}
+		parsedURL.RawQuery = query.Encode()
+	}
+	return parsedURL.String(), nil
+}
+
+// Snapshot возвращает копию запроса для истории
+func (r *HTTPRequest) Snapshot() models.RequestSnapshot {
+	return models.RequestSnapshot{
+		Method:   r.Method,
+		URL:      r.URL,
+		Headers:  r.Headers,
+		Params:   r.Params,
+		Body:     r.bodyText(),
+		BodyType: r.BodyType,
+		Form:     r.Form,
+	}
+}
+
+// bodyText возвращает текстовое представление тела: поля multipart
+// в форме curl -F и путь к файлу тела binary в форме "< path"
+func (r *HTTPRequest) bodyText() string {
+	switch r.BodyType {
+	case models.BodyMultipart:
+		return models.FormatFormFields(r.Form)
+	case models.BodyBinary:
+		return r.BodyFile
+	}
+	return string(r.Body)
+}
+
+// String возвращает текстовое представление запроса в формате HTTP
+func (r *HTTPRequest) String() string {
+	var sb strings.Builder
+	fullURL, err := r.BuildURL()
+	if err != nil {
+		fullURL = r.URL
+	}
+	sb.WriteString(fmt.Sprintf("%s %s\n", r.Method, fullURL))
+	for _, h := range r.Headers {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", h.Key, h.Value))
+	}
+	switch {
+	case r.BodyType == models.BodyMultipart:
+		sb.WriteString("Content-Type: multipart/form-data\n\n")
+		sb.WriteString(models.FormatFormFields(r.Form))
+	case r.BodyType == models.BodyBinary:
+		sb.WriteString("\n< " + r.BodyFile)
+	case len(r.Body) > 0:
+		sb.WriteString("\n")
+		sb.Write(r.Body)
+	}
+	return sb.String()
+}
+
+// NewHTTPRequest создает новый HTTP запрос из модели приложения с учетом
+// значений папки запроса и общих настроек клиента, подставляя переменные активного окружения.
+// Запрос использует cookies активного окружения.
 func NewHTTPRequest(model *models.AppModel) HTTPRequest {
-	var bodyBytes []byte
-	if model.BodyInputValue() != "" {
-		bodyBytes = []byte(model.BodyInputValue())
+	req := NewHTTPRequestFromSaved(model.ResolvedRequest(), model.GetActiveVariables())
+	req.Settings = model.GetClientSettings().Expand(model.GetActiveVariables()).Merge(req.Settings)
+	req.Cookies = model.CookieJar()
+	return req
+}
+
+// NewHTTPRequestFromSaved создает HTTP запрос из сохраненного запроса,
+// подставляя значения переменных (в пути и строке запроса URL - в закодированном виде)
+func NewHTTPRequestFromSaved(sr models.SavedRequest, vars map[string]string) HTTPRequest {
+	headers := make([]models.Header, len(sr.Headers))
+	for i, h := range sr.Headers {
+		headers[i] = models.Header{Key: h.Key, Value: models.ExpandVariables(h.Value, vars)}
+	}
+
+	params := make([]models.Param, len(sr.Params))
+	for i, p := range sr.Params {
+		params[i] = models.Param{Key: p.Key, Value: models.ExpandVariables(p.Value, vars)}
 	}
 
-	return HTTPRequest{
-		Method:  model.GetCurrentMethod(),
-		URL:     model.URLInputValue(),
-		Headers: model.GetHeaders(),
-		Params:  model.GetParams(),
-		Body:    bodyBytes,
+	req := HTTPRequest{
+		Method:   models.MethodNames[sr.Method],
+		URL:      models.ExpandURLVariables(sr.URL, vars),
+		Headers:  headers,
+		Params:   params,
+		Auth:     sr.Auth.Expand(vars),
+		Settings: sr.Settings.Expand(vars),
 	}
+	for _, a := range sr.Assertions {
+		req.Assertions = append(req.Assertions, a.Expand(vars))
+	}
+	for _, e := range sr.Extract {
+		req.Extract = append(req.Extract, e.Expand(vars))
+	}
+	req.setBody(sr, vars)
+	req.applyAuth()
+	return req
+}
+
+// CheckResponse выполняет проверки запроса над полученным ответом и извлекает
+// из него значения переменных
+func (r *HTTPRequest) CheckResponse(data *models.ResponseData) {
+	data.Assertions = models.EvaluateAssertions(r.Assertions, *data)
+	data.Extracted = models.ExtractVariables(r.Extract, *data)
 }
@@ -0,0 +1,145 @@
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/KharpukhaevV/postui/models"
+	"golang.org/x/net/publicsuffix"
+)
+
+// cookieJar реализует http.CookieJar поверх сохраняемых cookies окружения.
+// Правила выбора домена и пути соответствуют RFC 6265; cookies для публичных
+// суффиксов (com, co.uk, github.io) отбрасываются по списку Public Suffix List.
+type cookieJar struct {
+	jar *models.CookieJar
+}
+
+// SetCookies сохраняет cookies ответа, отбрасывая установленные для чужого домена
+func (j cookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	host := canonicalHost(u)
+	now := time.Now()
+	var stored []models.StoredCookie
+	for _, c := range cookies {
+		sc := models.StoredCookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HTTPOnly: c.HttpOnly,
+		}
+
+		domain := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+		switch {
+		case domain == "":
+			sc.Domain, sc.HostOnly = host, true
+		case isPublicSuffix(domain) && net.ParseIP(domain) == nil:
+			// Cookie для публичного суффикса допустима только для самого хоста
+			if domain != host {
+				continue
+			}
+			sc.Domain, sc.HostOnly = host, true
+		case domainMatch(host, domain) && net.ParseIP(host) == nil:
+			sc.Domain = domain
+		case domain == host:
+			sc.Domain, sc.HostOnly = host, true
+		default:
+			continue
+		}
+		if !strings.HasPrefix(sc.Path, "/") {
+			sc.Path = defaultCookiePath(u.Path)
+		}
+
+		switch {
+		case c.MaxAge < 0:
+			sc.Expires = now.Add(-time.Second) // удаляет сохраненную cookie
+		case c.MaxAge > 0:
+			sc.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		case !c.Expires.IsZero():
+			sc.Expires = c.Expires
+		}
+
+		switch c.SameSite {
+		case http.SameSiteLaxMode:
+			sc.SameSite = "Lax"
+		case http.SameSiteStrictMode:
+			sc.SameSite = "Strict"
+		case http.SameSiteNoneMode:
+			sc.SameSite = "None"
+		}
+		stored = append(stored, sc)
+	}
+	if len(stored) > 0 {
+		// Ошибка записи файла не должна прерывать запрос: cookies остаются в памяти
+		j.jar.Set(stored...)
+	}
+}
+
+// Cookies возвращает cookies для отправки по адресу u, более длинные пути первыми
+func (j cookieJar) Cookies(u *url.URL) []*http.Cookie {
+	host := canonicalHost(u)
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	secure := u.Scheme == "https" || u.Scheme == "wss"
+
+	var matched []models.StoredCookie
+	for _, c := range j.jar.All() {
+		if c.HostOnly && c.Domain != host || !c.HostOnly && !domainMatch(host, c.Domain) {
+			continue
+		}
+		if !pathMatch(path, c.Path) || c.Secure && !secure {
+			continue
+		}
+		matched = append(matched, c)
+	}
+	sort.SliceStable(matched, func(a, b int) bool { return len(matched[a].Path) > len(matched[b].Path) })
+
+	cookies := make([]*http.Cookie, len(matched))
+	for i, c := range matched {
+		cookies[i] = &http.Cookie{Name: c.Name, Value: c.Value}
+	}
+	return cookies
+}
+
+// canonicalHost возвращает имя хоста без порта в нижнем регистре
+func canonicalHost(u *url.URL) string {
+	return strings.ToLower(u.Hostname())
+}
+
+// domainMatch сообщает, совпадает ли хост с доменом или является его поддоменом
+func domainMatch(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// pathMatch сообщает, относится ли путь запроса к пути cookie (RFC 6265, раздел 5.1.4)
+func pathMatch(requestPath, cookiePath string) bool {
+	if requestPath == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(requestPath, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || requestPath[len(cookiePath)] == '/'
+}
+
+// isPublicSuffix сообщает, является ли домен публичным суффиксом, для которого
+// нельзя устанавливать cookies (RFC 6265, раздел 5.3, шаг 5)
+func isPublicSuffix(domain string) bool {
+	suffix, _ := publicsuffix.PublicSuffix(domain)
+	return suffix == domain
+}
+
+// defaultCookiePath возвращает путь cookie по умолчанию - каталог пути запроса
+func defaultCookiePath(requestPath string) string {
+	i := strings.LastIndex(requestPath, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return requestPath[:i]
+}
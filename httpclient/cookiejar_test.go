@@ -0,0 +1,170 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// newTestJar возвращает пустое хранилище cookies во временном каталоге конфигурации
+func newTestJar(t *testing.T) cookieJar {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	store, err := models.LoadCookieStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cookieJar{jar: store.Jar("")}
+}
+
+func mustURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+// cookieNames возвращает имена cookies, отправляемых по адресу, через запятую.
+// Cookies с одинаковой длиной пути идут в порядке домена и имени.
+func cookieNames(t *testing.T, jar cookieJar, raw string) string {
+	t.Helper()
+	var names []string
+	for _, c := range jar.Cookies(mustURL(t, raw)) {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestCookieJarDomain(t *testing.T) {
+	jar := newTestJar(t)
+	jar.SetCookies(mustURL(t, "https://www.example.com/"), []*http.Cookie{
+		{Name: "host", Value: "1"},
+		{Name: "domain", Value: "2", Domain: "example.com"},
+		{Name: "dot", Value: "3", Domain: ".www.example.com"},
+		{Name: "foreign", Value: "4", Domain: "example.org"},
+		{Name: "child", Value: "5", Domain: "api.www.example.com"},
+	})
+
+	for raw, want := range map[string]string{
+		"https://www.example.com/":      "domain,dot,host",
+		"https://WWW.Example.com:8443/": "domain,dot,host",
+		"https://api.www.example.com/":  "domain,dot",
+		"https://example.com/":          "domain",
+		"https://notexample.com/":       "",
+		"https://example.org/":          "",
+	} {
+		if got := cookieNames(t, jar, raw); got != want {
+			t.Errorf("%s: cookies = %q, ожидалось %q", raw, got, want)
+		}
+	}
+}
+
+func TestCookieJarPath(t *testing.T) {
+	jar := newTestJar(t)
+	jar.SetCookies(mustURL(t, "http://example.com/api/v1/users"), []*http.Cookie{
+		{Name: "root", Value: "1", Path: "/"},
+		{Name: "api", Value: "2", Path: "/api"},
+		{Name: "default", Value: "3"},
+		{Name: "relative", Value: "4", Path: "api"},
+	})
+
+	// Путь по умолчанию и неверный путь заменяются каталогом пути запроса (/api/v1)
+	for raw, want := range map[string]string{
+		"http://example.com/api/v1/users": "default,relative,api,root",
+		"http://example.com/api/v1":       "default,relative,api,root",
+		"http://example.com/api/v10":      "api,root",
+		"http://example.com/api":          "api,root",
+		"http://example.com/apix":         "root",
+		"http://example.com":              "root",
+	} {
+		if got := cookieNames(t, jar, raw); got != want {
+			t.Errorf("%s: cookies = %q, ожидалось %q", raw, got, want)
+		}
+	}
+}
+
+func TestCookieJarSecure(t *testing.T) {
+	jar := newTestJar(t)
+	jar.SetCookies(mustURL(t, "https://example.com/"), []*http.Cookie{
+		{Name: "secure", Value: "1", Secure: true},
+		{Name: "plain", Value: "2"},
+	})
+	if got := cookieNames(t, jar, "https://example.com/"); got != "plain,secure" {
+		t.Errorf("https: cookies = %q", got)
+	}
+	if got := cookieNames(t, jar, "http://example.com/"); got != "plain" {
+		t.Errorf("http: cookies = %q, Secure cookie не должна отправляться", got)
+	}
+}
+
+func TestCookieJarExpiry(t *testing.T) {
+	jar := newTestJar(t)
+	u := mustURL(t, "https://example.com/")
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "session", Value: "1"},
+		{Name: "max-age", Value: "2", MaxAge: 3600},
+		{Name: "expires", Value: "3", Expires: time.Now().Add(time.Hour)},
+		{Name: "expired", Value: "4", Expires: time.Now().Add(-time.Hour)},
+	})
+	if got := cookieNames(t, jar, u.String()); got != "expires,max-age,session" {
+		t.Fatalf("cookies = %q", got)
+	}
+
+	// Max-Age < 0 и истекший Expires удаляют сохраненные cookies
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "session", MaxAge: -1},
+		{Name: "expires", Expires: time.Now().Add(-time.Minute)},
+	})
+	if got := cookieNames(t, jar, u.String()); got != "max-age" {
+		t.Errorf("cookies после удаления = %q, ожидалось max-age", got)
+	}
+	for _, c := range jar.jar.All() {
+		if c.Name == "max-age" && time.Until(c.Expires) < 59*time.Minute {
+			t.Errorf("срок действия max-age = %v", c.Expires)
+		}
+	}
+}
+
+func TestCookieJarPublicSuffix(t *testing.T) {
+	for _, tc := range []struct {
+		url, domain string
+		want        string // домен сохраненной cookie ("" - отброшена)
+		hostOnly    bool
+	}{
+		{"https://www.example.com/", "com", "", false},
+		{"https://www.example.co.uk/", "co.uk", "", false},
+		{"https://shop.example.com.de/", "com.de", "", false},
+		{"https://alice.blogspot.com/", "blogspot.com", "", false},
+		{"https://my-app.appspot.com/", "appspot.com", "", false},
+		{"https://app.herokuapp.com/", "herokuapp.com", "", false},
+		{"https://user.github.io/", "github.io", "", false},
+		// Публичный суффикс, совпадающий с хостом, дает cookie только для хоста
+		{"https://github.io/", "github.io", "github.io", true},
+		{"http://localhost:8080/", "localhost", "localhost", true},
+		// Регистрируемые домены публичными суффиксами не являются
+		{"https://app.herokuapp.com/", "app.herokuapp.com", "app.herokuapp.com", false},
+		{"https://www.example.co.uk/", "example.co.uk", "example.co.uk", false},
+		// Для IP-адреса Domain допустим только совпадающий с ним
+		{"http://127.0.0.1/", "127.0.0.1", "127.0.0.1", true},
+		{"http://127.0.0.1/", "0.1", "", false},
+	} {
+		jar := newTestJar(t)
+		jar.SetCookies(mustURL(t, tc.url), []*http.Cookie{{Name: "c", Value: "1", Domain: tc.domain}})
+		stored := jar.jar.All()
+		switch {
+		case tc.want == "" && len(stored) != 0:
+			t.Errorf("%s Domain=%s: cookie сохранена %+v, ожидалось отбросить", tc.url, tc.domain, stored[0])
+		case tc.want != "" && len(stored) != 1:
+			t.Errorf("%s Domain=%s: cookie отброшена", tc.url, tc.domain)
+		case tc.want != "" && (stored[0].Domain != tc.want || stored[0].HostOnly != tc.hostOnly):
+			t.Errorf("%s Domain=%s: домен %s, только хост %v; ожидалось %s, %v",
+				tc.url, tc.domain, stored[0].Domain, stored[0].HostOnly, tc.want, tc.hostOnly)
+		}
+	}
+}
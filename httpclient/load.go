@@ -0,0 +1,161 @@
+package httpclient
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// loadProgressInterval период, с которым нагрузочный тест передает снимки статистики
+const loadProgressInterval = 250 * time.Millisecond
+
+// LoadTester многократно выполняет сохраненный запрос параллельно и собирает
+// статистику времени ответа, кодов ответа и ошибок
+type LoadTester struct {
+	Client    *HTTPClient
+	Settings  models.ClientSettings // общие настройки, которые дополняет запрос
+	Override  models.ClientSettings // настройки, заменяющие настройки запроса
+	Cookies   *models.CookieJar     // cookies окружения (nil - не отправлять)
+	Variables map[string]string     // переменные коллекции и окружения
+}
+
+// Run выполняет запрос opts.Concurrency потоками, пока не будет выполнено opts.Requests
+// запросов или не истечет opts.Duration. Снимки статистики передаются в progress
+// во время теста, итоги возвращаются после завершения всех запросов.
+// Проверки и извлечение переменных запроса не выполняются, тела ответов не сохраняются.
+func (t *LoadTester) Run(ctx context.Context, sr models.SavedRequest, opts models.LoadOptions,
+	progress func(models.LoadStats)) models.LoadStats {
+	req := NewHTTPRequestFromSaved(sr, t.Variables)
+	req.Settings = t.Settings.Expand(t.Variables).Merge(req.Settings).Merge(t.Override)
+	// Cookies окружения отправляются, но ответы теста их не меняют: иначе хранилище
+	// сохранялось бы на диск после каждого ответа
+	if t.Cookies != nil && t.Client.settings.Merge(req.Settings).CookiesEnabled() {
+		req.Headers = append(req.Headers, t.cookieHeader(&req)...)
+	}
+
+	stats := models.NewLoadStats(sr.Name, req.Method, req.URL, opts)
+	failRanges := opts.FailRanges()
+	var mu sync.Mutex
+
+	// Запросы выдаются потокам по одному, с паузой при заданном rps.
+	// По истечении длительности новые запросы не выдаются, начатые завершаются.
+	jobs := make(chan struct{})
+	go func() {
+		defer close(jobs)
+		var deadline <-chan time.Time
+		if opts.Duration > 0 {
+			timer := time.NewTimer(opts.Duration)
+			defer timer.Stop()
+			deadline = timer.C
+		}
+		var tick <-chan time.Time
+		if opts.RPS > 0 {
+			ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.RPS))
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for i := 0; opts.Requests == 0 || i < opts.Requests; i++ {
+			if tick != nil && i > 0 {
+				select {
+				case <-tick:
+				case <-deadline:
+					return
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case jobs <- struct{}{}:
+			case <-deadline:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var workers sync.WaitGroup
+	for range max(opts.Concurrency, 1) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for range jobs {
+				sample, ok := t.send(ctx, req)
+				if !ok {
+					continue
+				}
+				mu.Lock()
+				stats.Add(sample, failRanges)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	ticker := time.NewTicker(loadProgressInterval)
+	defer ticker.Stop()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		case <-ticker.C:
+			if progress != nil {
+				mu.Lock()
+				snapshot := stats.Snapshot()
+				mu.Unlock()
+				progress(snapshot)
+			}
+		}
+	}
+
+	result := stats.Snapshot()
+	result.Canceled = ctx.Err() != nil
+	result.Finished = true
+	return result
+}
+
+// send выполняет один запрос теста. Запрос, прерванный отменой теста, не учитывается.
+func (t *LoadTester) send(ctx context.Context, req HTTPRequest) (models.LoadSample, bool) {
+	start := time.Now()
+	response, err := t.Client.SendRequest(ctx, &req)
+	sample := models.LoadSample{Latency: time.Since(start)}
+	if err != nil {
+		if ctx.Err() != nil {
+			return sample, false
+		}
+		sample.Error = err.Error()
+		return sample, true
+	}
+	models.RemoveBodyFile(response)
+	sample.Status, sample.Code, sample.Size = response.Status, response.StatusCode, response.Size
+	return sample, true
+}
+
+// cookieHeader возвращает заголовок Cookie с cookies окружения для адреса запроса
+func (t *LoadTester) cookieHeader(req *HTTPRequest) []models.Header {
+	fullURL, err := req.BuildURL()
+	if err != nil {
+		return nil
+	}
+	u, err := url.Parse(fullURL)
+	if err != nil {
+		return nil
+	}
+	cookies := cookieJar{jar: t.Cookies}.Cookies(u)
+	if len(cookies) == 0 {
+		return nil
+	}
+	pairs := make([]string, len(cookies))
+	for i, c := range cookies {
+		pairs[i] = c.Name + "=" + c.Value
+	}
+	return []models.Header{{Key: "Cookie", Value: strings.Join(pairs, "; ")}}
+}
@@ -0,0 +1,91 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+func TestLoadTesterRequests(t *testing.T) {
+	var count, active, peak atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(5 * time.Millisecond)
+		if r.Header.Get("X-User") != "ann" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// Каждый пятый ответ - ошибка сервера
+		if count.Add(1)%5 == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	tester := LoadTester{Client: NewHTTPClient(), Variables: map[string]string{"base": server.URL, "user": "ann"}}
+	sr := models.SavedRequest{
+		Name:    "ping",
+		Method:  models.MethodGET,
+		URL:     "{{base}}/ping",
+		Headers: []models.Header{{Key: "X-User", Value: "{{user}}"}},
+	}
+	opts := models.LoadOptions{Concurrency: 4, Requests: 20, FailOn: "5xx"}
+	stats := tester.Run(context.Background(), sr, opts, nil)
+
+	if !stats.Finished || stats.Canceled || stats.Completed != 20 || stats.Failed != 4 || stats.Bytes != 32 {
+		t.Errorf("выполнено %d, неуспешно %d, байт %d, завершен %v", stats.Completed, stats.Failed, stats.Bytes, stats.Finished)
+	}
+	if count.Load() != 20 || peak.Load() > 4 {
+		t.Errorf("сервер получил %d запросов, одновременно до %d", count.Load(), peak.Load())
+	}
+	wantStatuses := map[string]int{"200 OK": 16, "503 Service Unavailable": 4}
+	if !reflect.DeepEqual(stats.Statuses, wantStatuses) {
+		t.Errorf("коды ответа = %v", stats.Statuses)
+	}
+	if len(stats.Latencies) != 20 || stats.Percentile(50) < 5*time.Millisecond || stats.MinLatency() > stats.Percentile(99) {
+		t.Errorf("время ответов: %v", stats.Latencies)
+	}
+	if stats.URL != server.URL+"/ping" || stats.Method != "GET" {
+		t.Errorf("запрос теста: %s %s", stats.Method, stats.URL)
+	}
+}
+
+func TestLoadTesterDurationAndCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	tester := LoadTester{Client: NewHTTPClient()}
+	sr := models.SavedRequest{Name: "ping", Method: models.MethodGET, URL: server.URL}
+
+	// Частота ограничена rps, тест заканчивается по истечении длительности
+	var snapshots atomic.Int64
+	started := time.Now()
+	stats := tester.Run(context.Background(), sr, models.LoadOptions{Concurrency: 2, Duration: 600 * time.Millisecond, RPS: 20},
+		func(models.LoadStats) { snapshots.Add(1) })
+	if elapsed := time.Since(started); elapsed < 600*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("тест длился %v", elapsed)
+	}
+	if stats.Completed < 5 || stats.Completed > 14 || stats.Canceled {
+		t.Errorf("выполнено %d запросов за 600ms при rps 20", stats.Completed)
+	}
+	if snapshots.Load() == 0 {
+		t.Error("снимки статистики не передавались")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	stats = tester.Run(ctx, sr, models.LoadOptions{Concurrency: 2, Duration: time.Minute, RPS: 10}, nil)
+	if !stats.Canceled || !stats.Finished || stats.Completed > 4 {
+		t.Errorf("отмененный тест: отменен %v, выполнено %d", stats.Canceled, stats.Completed)
+	}
+}
@@ -0,0 +1,179 @@
+package httpclient
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/KharpukhaevV/postui/models"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// bodyBuffer накапливает тело ответа в памяти, а после limit байт переносит
+// его во временный файл. В памяти остаются первые limit байт для показа.
+type bodyBuffer struct {
+	limit int64
+	mem   bytes.Buffer
+	file  *os.File
+	size  int64
+}
+
+func (b *bodyBuffer) Write(p []byte) (int, error) {
+	b.size += int64(len(p))
+	if b.file == nil {
+		if int64(b.mem.Len()+len(p)) <= b.limit {
+			return b.mem.Write(p)
+		}
+		file, err := os.CreateTemp("", "postui-body-*")
+		if err != nil {
+			return 0, err
+		}
+		b.file = file
+		if _, err := file.Write(b.mem.Bytes()); err != nil {
+			return 0, err
+		}
+		b.mem.Write(p[:b.limit-int64(b.mem.Len())])
+	}
+	return b.file.Write(p)
+}
+
+// reader возвращает все тело с начала
+func (b *bodyBuffer) reader() (io.Reader, error) {
+	if b.file == nil {
+		return bytes.NewReader(b.mem.Bytes()), nil
+	}
+	if _, err := b.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return b.file, nil
+}
+
+// finish закрывает временный файл и возвращает его путь ("" для тела в памяти)
+func (b *bodyBuffer) finish() string {
+	if b.file == nil {
+		return ""
+	}
+	b.file.Close()
+	return b.file.Name()
+}
+
+// discard удаляет временный файл
+func (b *bodyBuffer) discard() {
+	if b.file != nil {
+		b.file.Close()
+		os.Remove(b.file.Name())
+	}
+}
+
+// transform пропускает тело через преобразование (распаковку, перекодировку)
+// в новый буфер
+func (b *bodyBuffer) transform(wrap func(io.Reader) (io.Reader, error)) (*bodyBuffer, error) {
+	source, err := b.reader()
+	if err != nil {
+		return nil, err
+	}
+	reader, err := wrap(source)
+	if err != nil {
+		return nil, err
+	}
+	result := &bodyBuffer{limit: b.limit}
+	if _, err := io.Copy(result, reader); err != nil {
+		result.discard()
+		return nil, err
+	}
+	return result, nil
+}
+
+// readBody читает тело ответа, распаковывает его в соответствии с Content-Encoding
+// и перекодирует текст в UTF-8 по charset из Content-Type. Тело больше limit
+// сохраняется во временный файл, а в data.Body остается его начало.
+func readBody(resp *http.Response, limit int64, data *models.ResponseData) error {
+	raw := &bodyBuffer{limit: limit}
+	if _, err := io.Copy(raw, resp.Body); err != nil {
+		raw.discard()
+		return err
+	}
+	data.Size = raw.size
+
+	body := raw
+	if decompress := decompressor(data.Encoding); decompress != nil {
+		// Тело, которое не удалось распаковать, показываем как есть
+		if decoded, err := raw.transform(decompress); err == nil {
+			raw.discard()
+			body = decoded
+		}
+	}
+	data.DecodedSize = body.size
+
+	// Текст в другой кодировке проверяется после перекодировки начала тела
+	contentType := resp.Header.Get("Content-Type")
+	enc, name := charsetEncoding(contentType)
+	sample := body.mem.Bytes()
+	if enc != nil {
+		if decoded, err := enc.NewDecoder().Bytes(sample[:min(len(sample), 8*1024)]); err == nil {
+			sample = decoded
+		}
+	}
+	data.Binary = models.IsBinaryContent(contentType, sample)
+	if enc != nil && !data.Binary {
+		text, err := body.transform(func(r io.Reader) (io.Reader, error) {
+			return enc.NewDecoder().Reader(r), nil
+		})
+		if err == nil {
+			body.discard()
+			body = text
+			data.Charset = name
+		}
+	}
+
+	data.Body = body.mem.String()
+	data.BodyFile = body.finish()
+	return nil
+}
+
+// decompressor возвращает распаковку для Content-Encoding или nil, если тело не сжато
+func decompressor(contentEncoding string) func(io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "gzip", "x-gzip":
+		return func(r io.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		}
+	case "deflate":
+		return func(r io.Reader) (io.Reader, error) {
+			// Серверы отправляют deflate как с заголовком zlib, так и без него
+			br := bufio.NewReader(r)
+			if header, err := br.Peek(2); err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+				return zlib.NewReader(br)
+			}
+			return flate.NewReader(br), nil
+		}
+	}
+	return nil
+}
+
+// charsetEncoding возвращает кодировку из параметра charset Content-Type и ее
+// название. Для UTF-8 и неизвестных кодировок возвращается nil.
+func charsetEncoding(contentType string) (encoding.Encoding, string) {
+	_, params, err := mime.ParseMediaType(contentType)
+	charset := strings.ToLower(params["charset"])
+	if err != nil || charset == "" || charset == "us-ascii" || charset == "ascii" {
+		return nil, ""
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, ""
+	}
+	name, _ := htmlindex.Name(enc)
+	if name == "utf-8" {
+		return nil, ""
+	}
+	return enc, name
+}
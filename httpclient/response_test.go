@@ -0,0 +1,200 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/KharpukhaevV/postui/models"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// readTestBody читает тело ответа с указанными заголовками так же, как клиент
+func readTestBody(t *testing.T, body []byte, contentType, contentEncoding string, limit int64) models.ResponseData {
+	t.Helper()
+	resp := &http.Response{Header: http.Header{}, Body: io.NopCloser(bytes.NewReader(body))}
+	if contentType != "" {
+		resp.Header.Set("Content-Type", contentType)
+	}
+	data := models.ResponseData{Encoding: contentEncoding}
+	if err := readBody(resp, limit, &data); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { models.RemoveBodyFile(data) })
+	return data
+}
+
+// fullBody возвращает все тело ответа вместе с частью во временном файле
+func fullBody(t *testing.T, data models.ResponseData) string {
+	t.Helper()
+	var sb strings.Builder
+	if _, err := models.CopyResponseBody(&sb, data); err != nil {
+		t.Fatal(err)
+	}
+	return sb.String()
+}
+
+func compress(t *testing.T, encoding string, body []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "zlib":
+		w = zlib.NewWriter(&buf)
+	case "flate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	}
+	w.Write(body)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestReadBodyInMemory(t *testing.T) {
+	data := readTestBody(t, []byte(`{"ok": true}`), "application/json", "", 1024)
+	if data.Body != `{"ok": true}` || data.BodyFile != "" || data.Binary || data.Charset != "" {
+		t.Errorf("ответ: %+v", data)
+	}
+	if data.Size != 12 || data.DecodedSize != 12 {
+		t.Errorf("размер %d, после распаковки %d", data.Size, data.DecodedSize)
+	}
+}
+
+func TestReadBodySpillsToFile(t *testing.T) {
+	body := strings.Repeat("0123456789", 100)
+	data := readTestBody(t, []byte(body), "text/plain", "", 256)
+	if data.BodyFile == "" {
+		t.Fatal("тело больше лимита не сохранено во временный файл")
+	}
+	if data.Body != body[:256] || data.Size != 1000 || data.DecodedSize != 1000 {
+		t.Errorf("в памяти %d байт, размер %d", len(data.Body), data.Size)
+	}
+	if got := fullBody(t, data); got != body {
+		t.Errorf("полное тело из файла: %d байт, ожидалось %d", len(got), len(body))
+	}
+
+	// Тело ровно в лимит остается в памяти
+	if exact := readTestBody(t, []byte(body[:256]), "text/plain", "", 256); exact.BodyFile != "" || exact.Body != body[:256] {
+		t.Errorf("тело в лимит: файл %q, в памяти %d байт", exact.BodyFile, len(exact.Body))
+	}
+
+	models.RemoveBodyFile(data)
+	if _, err := os.Stat(data.BodyFile); !os.IsNotExist(err) {
+		t.Errorf("временный файл не удален: %v", err)
+	}
+}
+
+func TestReadBodyDecompress(t *testing.T) {
+	body := strings.Repeat(`{"name": "value"}`, 50)
+	for _, tc := range []struct {
+		contentEncoding, format string
+	}{
+		{"gzip", "gzip"},
+		{"x-gzip", "gzip"},
+		{" GZIP ", "gzip"},
+		// deflate отправляется как с заголовком zlib, так и без него
+		{"deflate", "zlib"},
+		{"deflate", "flate"},
+	} {
+		compressed := compress(t, tc.format, []byte(body))
+		data := readTestBody(t, compressed, "application/json", tc.contentEncoding, 1<<20)
+		if data.Body != body || data.Size != int64(len(compressed)) || data.DecodedSize != int64(len(body)) {
+			t.Errorf("%s (%s): тело %d байт, размер %d, после распаковки %d",
+				tc.contentEncoding, tc.format, len(data.Body), data.Size, data.DecodedSize)
+		}
+	}
+
+	// Распакованное тело больше лимита сохраняется в файл целиком
+	compressed := compress(t, "gzip", []byte(body))
+	data := readTestBody(t, compressed, "application/json", "gzip", 100)
+	if data.BodyFile == "" || data.Body != body[:100] || fullBody(t, data) != body {
+		t.Errorf("распакованное тело больше лимита: файл %q, в памяти %q", data.BodyFile, data.Body)
+	}
+
+	// Тело, которое не удалось распаковать, показывается как есть
+	data = readTestBody(t, []byte("not gzip"), "text/plain", "gzip", 1024)
+	if data.Body != "not gzip" || data.DecodedSize != 8 {
+		t.Errorf("неверное сжатое тело: %q", data.Body)
+	}
+}
+
+func TestReadBodyCharset(t *testing.T) {
+	text := "Привет, мир! Ёлка"
+	encoded, err := charmap.Windows1251.NewEncoder().String(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, contentType := range []string{"text/plain; charset=windows-1251", `text/html; charset="CP1251"`} {
+		data := readTestBody(t, []byte(encoded), contentType, "", 1024)
+		if data.Body != text || data.Charset != "windows-1251" || data.Binary {
+			t.Errorf("%s: тело %q, кодировка %q, двоичное %v", contentType, data.Body, data.Charset, data.Binary)
+		}
+		// Размеры относятся к телу до перекодировки
+		if data.DecodedSize != int64(len(encoded)) {
+			t.Errorf("%s: размер после распаковки %d, ожидалось %d", contentType, data.DecodedSize, len(encoded))
+		}
+	}
+
+	// Перекодированное тело больше лимита сохраняется в файл в UTF-8
+	long := strings.Repeat(text+"\n", 40)
+	encoded, _ = charmap.Windows1251.NewEncoder().String(long)
+	data := readTestBody(t, []byte(encoded), "text/plain; charset=windows-1251", "", 200)
+	if data.BodyFile == "" || fullBody(t, data) != long || data.Body != long[:200] {
+		t.Errorf("перекодированное тело больше лимита: файл %q, в памяти %q", data.BodyFile, data.Body)
+	}
+
+	// UTF-8, ASCII и неизвестная кодировка не перекодируются
+	for _, contentType := range []string{"text/plain; charset=utf-8", "text/plain; charset=us-ascii", "text/plain; charset=x-unknown"} {
+		if data := readTestBody(t, []byte("plain"), contentType, "", 1024); data.Charset != "" || data.Body != "plain" {
+			t.Errorf("%s: кодировка %q, тело %q", contentType, data.Charset, data.Body)
+		}
+	}
+}
+
+func TestReadBodyBinary(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	data := readTestBody(t, png, "image/png", "", 1024)
+	if !data.Binary || data.Body != string(png) || data.Charset != "" {
+		t.Errorf("изображение: двоичное %v, тело %q", data.Binary, data.Body)
+	}
+	// Двоичное тело с charset не перекодируется
+	data = readTestBody(t, []byte("a\x00b\x00c"), "text/plain; charset=windows-1251", "", 1024)
+	if !data.Binary || data.Charset != "" || data.Body != "a\x00b\x00c" {
+		t.Errorf("двоичное тело с charset: %+v", data)
+	}
+}
+
+func TestSendRequestGzipResponse(t *testing.T) {
+	body := strings.Repeat("hello ", 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip, deflate" {
+			t.Errorf("Accept-Encoding = %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(compress(t, "gzip", []byte(body)))
+	}))
+	defer server.Close()
+
+	req := HTTPRequest{Method: "GET", URL: server.URL, Settings: models.ClientSettings{MaxBodySize: "100B"}}
+	data, err := NewHTTPClient().SendRequest(context.Background(), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer models.RemoveBodyFile(data)
+	if data.Encoding != "gzip" || data.DecodedSize != int64(len(body)) || data.Size >= data.DecodedSize {
+		t.Errorf("сжатие %q, размер %d, после распаковки %d", data.Encoding, data.Size, data.DecodedSize)
+	}
+	if data.Body != body[:100] || data.BodyFile == "" || fullBody(t, data) != body {
+		t.Errorf("тело больше max_body: в памяти %d байт, файл %q", len(data.Body), data.BodyFile)
+	}
+}
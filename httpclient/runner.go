@@ -0,0 +1,113 @@
+package httpclient
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// Runner выполняет запросы папки или коллекции по порядку. Значения, извлеченные
+// из ответов, доступны следующим запросам запуска как переменные.
+type Runner struct {
+	Client    *HTTPClient
+	Settings  models.ClientSettings // общие настройки, которые дополняет запрос
+	Override  models.ClientSettings // настройки, заменяющие настройки запроса
+	Cookies   *models.CookieJar     // cookies окружения (nil - не отправлять и не сохранять)
+	Variables map[string]string     // переменные коллекции и окружения
+}
+
+// Run выполняет запросы opts.IterationCount раз. Строки data по очереди становятся
+// переменными итераций. Каждый результат передается в progress сразу после получения.
+func (r *Runner) Run(ctx context.Context, name string, requests []models.SavedRequest, opts models.RunOptions,
+	data []map[string]string, progress func(models.RunResult)) models.RunReport {
+	report := models.RunReport{
+		Name:       name,
+		Started:    time.Now(),
+		Iterations: opts.IterationCount(len(data)),
+	}
+	report.Total = report.Iterations * len(requests)
+	failRanges := opts.FailRanges()
+	extracted := map[string]string{}
+	var extractedOrder []string
+
+run:
+	for iteration := 1; iteration <= report.Iterations; iteration++ {
+		for i, sr := range requests {
+			if (iteration > 1 || i > 0) && opts.Delay > 0 {
+				select {
+				case <-ctx.Done():
+				case <-time.After(opts.Delay):
+				}
+			}
+			if ctx.Err() != nil {
+				report.Canceled = true
+				break run
+			}
+
+			// Извлеченные значения переопределяют переменные строки данных и окружения
+			vars := maps.Clone(r.Variables)
+			if vars == nil {
+				vars = map[string]string{}
+			}
+			if len(data) > 0 {
+				maps.Copy(vars, data[(iteration-1)%len(data)])
+			}
+			maps.Copy(vars, extracted)
+
+			result := r.runRequest(ctx, sr, vars, failRanges)
+			if ctx.Err() != nil {
+				report.Canceled = true
+				break run
+			}
+			result.Iteration = iteration
+			for _, v := range models.ExtractedVariables(result.Extracted) {
+				if _, ok := extracted[v.Key]; !ok {
+					extractedOrder = append(extractedOrder, v.Key)
+				}
+				extracted[v.Key] = v.Value
+			}
+			report.Results = append(report.Results, result)
+			if progress != nil {
+				progress(result)
+			}
+			if !result.Passed && opts.StopOnFailure {
+				report.Stopped = true
+				break run
+			}
+		}
+	}
+
+	for _, key := range extractedOrder {
+		report.Variables = append(report.Variables, models.Variable{Key: key, Value: extracted[key]})
+	}
+	report.Duration = time.Since(report.Started)
+	report.Finished = true
+	return report
+}
+
+// runRequest выполняет запрос и проверяет ответ. Тело ответа не сохраняется.
+func (r *Runner) runRequest(ctx context.Context, sr models.SavedRequest, vars map[string]string, failRanges []models.StatusRange) models.RunResult {
+	req := NewHTTPRequestFromSaved(sr, vars)
+	req.Settings = r.Settings.Expand(vars).Merge(req.Settings).Merge(r.Override)
+	req.Cookies = r.Cookies
+
+	result := models.RunResult{Name: sr.Name, Method: req.Method, URL: req.URL}
+	response, err := r.Client.SendRequest(ctx, &req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	req.CheckResponse(&response)
+	models.RemoveBodyFile(response)
+	response.Body, response.BodyFile = "", ""
+
+	result.URL = response.URL
+	result.Assertions = response.Assertions
+	result.Extracted = response.Extracted
+	result.Response = response
+	result.Passed = models.CountPassed(response.Assertions) == len(response.Assertions) &&
+		!models.StatusInRanges(failRanges, response.StatusCode)
+	return result
+}
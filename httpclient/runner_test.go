@@ -0,0 +1,184 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// runServer выдает токен на /login?user=name и возвращает пользователя токена на /me.
+// Запросы записываются в порядке поступления.
+type runServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []string
+}
+
+func newRunServer(t *testing.T) *runServer {
+	s := &runServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.URL.RequestURI())
+		s.mu.Unlock()
+		switch r.URL.Path {
+		case "/login":
+			fmt.Fprintf(w, `{"token": "tok-%s"}`, r.URL.Query().Get("user"))
+		case "/me":
+			user, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer tok-")
+			if !ok {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintf(w, `{"user": %q}`, user)
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *runServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+var (
+	runLogin = models.SavedRequest{
+		Name:    "auth/login",
+		Method:  models.MethodPOST,
+		URL:     "{{base}}/login",
+		Params:  []models.Param{{Key: "user", Value: "{{user}}"}},
+		Extract: []models.Extraction{{Variable: "token", Source: models.ExtractJSON, Expression: "$.token"}},
+	}
+	runMe = models.SavedRequest{
+		Name:    "me",
+		Method:  models.MethodGET,
+		URL:     "{{base}}/me",
+		Headers: []models.Header{{Key: "Authorization", Value: "Bearer {{token}}"}},
+		Assertions: []models.Assertion{
+			{Subject: models.AssertStatus, Operator: "==", Value: "200"},
+			{Subject: models.AssertJSON, Target: "$.user", Operator: "==", Value: "{{user}}"},
+		},
+	}
+	runFail = models.SavedRequest{Name: "fail", Method: models.MethodGET, URL: "{{base}}/fail"}
+)
+
+func TestRunnerDataRowsAndExtractedVariables(t *testing.T) {
+	server := newRunServer(t)
+	runner := Runner{Client: NewHTTPClient(), Variables: map[string]string{"base": server.URL, "user": "env"}}
+	data := []map[string]string{{"user": "ann"}, {"user": "bob"}}
+
+	var progress []string
+	report := runner.Run(context.Background(), "api", []models.SavedRequest{runLogin, runMe}, models.DefaultRunOptions(), data,
+		func(r models.RunResult) { progress = append(progress, fmt.Sprintf("%d %s", r.Iteration, r.Name)) })
+
+	// Строка данных переопределяет переменную окружения, токен первого запроса
+	// передается следующему запросу итерации
+	wantRequests := []string{"/login?user=ann", "/me", "/login?user=bob", "/me"}
+	if got := server.received(); !reflect.DeepEqual(got, wantRequests) {
+		t.Errorf("запросы = %q, ожидалось %q", got, wantRequests)
+	}
+	if report.Iterations != 2 || report.Total != 4 || len(report.Results) != 4 || report.Failed() != 0 {
+		t.Fatalf("итерации %d, всего %d, результатов %d, неуспешных %d: %+v",
+			report.Iterations, report.Total, len(report.Results), report.Failed(), report.Results)
+	}
+	if want := []string{"1 auth/login", "1 me", "2 auth/login", "2 me"}; !reflect.DeepEqual(progress, want) {
+		t.Errorf("ход запуска = %q, ожидалось %q", progress, want)
+	}
+	me := report.Results[3]
+	if me.Response.Body != "" || len(me.Assertions) != 2 || me.Assertions[1].Assertion != `json $.user == bob` {
+		t.Errorf("результат me: %+v", me)
+	}
+	if want := []models.Variable{{Key: "token", Value: "tok-bob"}}; !reflect.DeepEqual(report.Variables, want) {
+		t.Errorf("переменные = %+v, ожидалось %+v", report.Variables, want)
+	}
+	if !report.Finished || report.Stopped || report.Canceled {
+		t.Errorf("состояние запуска: %+v", report)
+	}
+}
+
+func TestRunnerCarriesVariablesAcrossIterations(t *testing.T) {
+	server := newRunServer(t)
+	runner := Runner{Client: NewHTTPClient(), Variables: map[string]string{"base": server.URL, "user": "ann"}}
+
+	// Запрос me второй итерации выполняется раньше login и использует токен первой
+	opts := models.RunOptions{Iterations: 2}
+	report := runner.Run(context.Background(), "api", []models.SavedRequest{runMe, runLogin}, opts, nil, nil)
+	var passed []bool
+	for _, r := range report.Results {
+		passed = append(passed, r.Passed)
+	}
+	if want := []bool{false, true, true, true}; !reflect.DeepEqual(passed, want) {
+		t.Errorf("успешность запросов = %v, ожидалось %v", passed, want)
+	}
+	if first := report.Results[0]; first.Response.StatusCode != http.StatusUnauthorized ||
+		first.Assertions[0].Actual != "401" || first.Assertions[1].Error == "" {
+		t.Errorf("первый запрос без токена: %+v", first)
+	}
+}
+
+func TestRunnerStopOnFailure(t *testing.T) {
+	server := newRunServer(t)
+	requests := []models.SavedRequest{runLogin, runFail, runMe}
+	vars := map[string]string{"base": server.URL, "user": "ann"}
+
+	// Без остановки код 500 из fail_on считается ошибкой, но запуск продолжается
+	report := (&Runner{Client: NewHTTPClient(), Variables: vars}).Run(context.Background(), "api", requests,
+		models.DefaultRunOptions(), nil, nil)
+	if len(report.Results) != 3 || report.Failed() != 1 || report.Results[1].Passed || report.Stopped {
+		t.Errorf("запуск без остановки: результатов %d, неуспешных %d, остановлен %v", len(report.Results), report.Failed(), report.Stopped)
+	}
+
+	server = newRunServer(t)
+	vars["base"] = server.URL
+	opts := models.DefaultRunOptions()
+	opts.StopOnFailure = true
+	opts.Iterations = 3
+	report = (&Runner{Client: NewHTTPClient(), Variables: vars}).Run(context.Background(), "api", requests, opts, nil, nil)
+	if len(report.Results) != 2 || !report.Stopped || !report.Finished || report.Total != 9 {
+		t.Errorf("запуск с остановкой: результатов %d, остановлен %v, всего %d", len(report.Results), report.Stopped, report.Total)
+	}
+	if got := server.received(); !reflect.DeepEqual(got, []string{"/login?user=ann", "/fail"}) {
+		t.Errorf("запросы после остановки = %q", got)
+	}
+
+	// Без fail_on код 500 ошибкой не считается
+	opts.FailOn = ""
+	report = (&Runner{Client: NewHTTPClient(), Variables: vars}).Run(context.Background(), "api", requests[:2], opts, nil, nil)
+	if report.Failed() != 0 || report.Stopped || len(report.Results) != 6 {
+		t.Errorf("запуск без fail_on: результатов %d, неуспешных %d", len(report.Results), report.Failed())
+	}
+}
+
+func TestRunnerRequestError(t *testing.T) {
+	server := newRunServer(t)
+	url := server.URL
+	server.Close()
+
+	opts := models.RunOptions{StopOnFailure: true}
+	report := (&Runner{Client: NewHTTPClient(), Variables: map[string]string{"base": url}}).Run(context.Background(), "api",
+		[]models.SavedRequest{runFail, runMe}, opts, nil, nil)
+	if len(report.Results) != 1 || report.Results[0].Error == "" || report.Results[0].Passed || !report.Stopped {
+		t.Errorf("результаты = %+v", report.Results)
+	}
+}
+
+func TestRunnerCanceled(t *testing.T) {
+	server := newRunServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := Runner{Client: NewHTTPClient(), Variables: map[string]string{"base": server.URL, "user": "ann"}}
+	report := runner.Run(ctx, "api", []models.SavedRequest{runLogin, runMe}, models.RunOptions{Iterations: 5}, nil,
+		func(models.RunResult) { cancel() })
+	if !report.Canceled || len(report.Results) != 1 || !report.Finished {
+		t.Errorf("отмененный запуск: отменен %v, результатов %d", report.Canceled, len(report.Results))
+	}
+}
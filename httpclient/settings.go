@@ -0,0 +1,131 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/KharpukhaevV/postui/models"
+)
+
+// tlsVersions сопоставляет допустимые значения minTlsVersion версиям TLS
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// clientPool хранит http.Client для каждого набора настроек, чтобы запросы
+// с одинаковыми настройками повторно использовали соединения
+type clientPool struct {
+	mu      sync.Mutex
+	clients map[string]*http.Client
+}
+
+func newClientPool() *clientPool {
+	return &clientPool{clients: map[string]*http.Client{}}
+}
+
+// get возвращает клиент для настроек, создавая его при первом обращении
+func (p *clientPool) get(settings models.ClientSettings) (*http.Client, error) {
+	key := models.FormatClientSettings(settings)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if client, ok := p.clients[key]; ok {
+		return client, nil
+	}
+	client, err := newClient(settings)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[key] = client
+	return client, nil
+}
+
+// newClient создает http.Client с указанными настройками.
+// Автоматическая распаковка отключена, чтобы знать размер ответа, переданного по сети.
+func newClient(settings models.ClientSettings) (*http.Client, error) {
+	timeout, err := settings.TimeoutDuration()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := newTLSConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableCompression = true
+	// Параллельные запросы нагрузочного теста повторно используют соединения,
+	// а не открывают новые сверх двух, которые транспорт хранит по умолчанию
+	transport.MaxIdleConnsPerHost = 100
+	transport.TLSClientConfig = tlsConfig
+	if settings.Proxy != "" {
+		proxyURL, err := url.Parse(settings.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("неверный адрес прокси: %w", err)
+		}
+		switch strings.ToLower(proxyURL.Scheme) {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("неподдерживаемая схема прокси %q (http, https, socks5, socks5h)", proxyURL.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	follow := settings.FollowRedirects == nil || *settings.FollowRedirects
+	return &http.Client{
+		Timeout:       timeout,
+		Transport:     transport,
+		CheckRedirect: recordRedirect(follow, settings.RedirectLimit()),
+	}, nil
+}
+
+// newTLSConfig создает настройки TLS: проверку сертификата сервера,
+// дополнительные корневые сертификаты, клиентский сертификат и минимальную версию
+func newTLSConfig(settings models.ClientSettings) (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: settings.Insecure != nil && *settings.Insecure}
+
+	if settings.MinTLSVersion != "" {
+		version, ok := tlsVersions[settings.MinTLSVersion]
+		if !ok {
+			return nil, fmt.Errorf("неверная версия TLS %q (1.0, 1.1, 1.2, 1.3)", settings.MinTLSVersion)
+		}
+		config.MinVersion = version
+	}
+
+	if settings.CACert != "" {
+		pem, err := os.ReadFile(settings.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать сертификаты CA: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("в файле %s нет сертификатов PEM", settings.CACert)
+		}
+		config.RootCAs = pool
+	}
+
+	if settings.ClientCert != "" || settings.ClientKey != "" {
+		keyFile := settings.ClientKey
+		if keyFile == "" {
+			// Ключ может храниться в одном файле с сертификатом
+			keyFile = settings.ClientCert
+		}
+		cert, err := tls.LoadX509KeyPair(settings.ClientCert, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось загрузить клиентский сертификат: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
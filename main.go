@@ -2,7 +2,10 @@ package main
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/KharpukhaevV/postui/cli"
+	"github.com/KharpukhaevV/postui/converter"
 	"github.com/KharpukhaevV/postui/events"
 	"github.com/KharpukhaevV/postui/models"
 	"github.com/KharpukhaevV/postui/ui"
@@ -17,9 +20,9 @@ type App struct {
 }
 
 // NewApp создает новый экземпляр приложения
-func NewApp() *App {
+func NewApp(model *models.AppModel) *App {
 	return &App{
-		model:        models.NewAppModel(),
+		model:        model,
 		eventHandler: events.NewEventHandler(),
 		uiRenderer:   ui.NewUIRenderer(),
 	}
@@ -50,10 +53,19 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case models.ResponseData:
-		a.model.SetResponseData(msg)
+		a.eventHandler.HandleResponse(a.model, msg)
 
 	case models.ErrorData:
-		a.model.SetError(msg)
+		a.eventHandler.HandleError(a.model, msg)
+
+	case models.RunResult:
+		cmds = append(cmds, a.eventHandler.HandleRunResult(a.model, msg))
+
+	case models.RunReport:
+		a.eventHandler.HandleRunFinished(a.model, msg)
+
+	case models.LoadStats:
+		cmds = append(cmds, a.eventHandler.HandleLoadStats(a.model, msg))
 
 	default:
 		// Все остальные сообщения передаем компонентам
@@ -70,10 +82,40 @@ func (a *App) View() string {
 }
 
 func main() {
-	app := NewApp()
+	// Безинтерфейсный режим для скриптов и CI
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
+	model, err := newModel(os.Args[1:])
+	if err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		os.Exit(1)
+	}
+
+	app := NewApp(model)
 	program := tea.NewProgram(app, tea.WithAltScreen())
 
 	if _, err := program.Run(); err != nil {
 		fmt.Printf("Ошибка: %v", err)
 	}
+	// Открытые вкладки запросов восстанавливаются при следующем запуске
+	model.SaveWorkspace()
+	model.RemoveBodyFiles()
+}
+
+// newModel создает модель приложения. Если передан путь к файлу .http / .rest,
+// запросы загружаются из него и сохраняются обратно в том же формате.
+func newModel(args []string) (*models.AppModel, error) {
+	if len(args) == 0 {
+		return models.NewAppModel(), nil
+	}
+	if !converter.IsHTTPFile(args[0]) {
+		return nil, fmt.Errorf("неизвестный аргумент %q (ожидается команда или файл .http)", args[0])
+	}
+	store := converter.NewHTTPFileStore(args[0])
+	if _, err := store.Load(); err != nil {
+		return nil, fmt.Errorf("не удалось прочитать %s: %w", args[0], err)
+	}
+	return models.NewAppModelWithStore(store), nil
 }
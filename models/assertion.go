@@ -0,0 +1,590 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AssertionSubject определяет, какая часть ответа проверяется
+type AssertionSubject string
+
+const (
+	AssertStatus  AssertionSubject = "status"
+	AssertHeader  AssertionSubject = "header"
+	AssertJSON    AssertionSubject = "json" // значение по выражению JSONPath или jq
+	AssertBody    AssertionSubject = "body"
+	AssertLatency AssertionSubject = "time" // общее время выполнения запроса
+)
+
+// assertionOperators перечисляет операторы, допустимые для каждой части ответа
+var assertionOperators = map[AssertionSubject][]string{
+	AssertStatus:  {"==", "!=", "<", "<=", ">", ">=", "in", "!in"},
+	AssertHeader:  {"==", "!=", "contains", "!contains", "matches", "!matches", "exists", "!exists"},
+	AssertJSON:    {"==", "!=", "<", "<=", ">", ">=", "contains", "!contains", "matches", "!matches", "exists", "!exists"},
+	AssertBody:    {"contains", "!contains", "matches", "!matches"},
+	AssertLatency: {"<", "<=", ">", ">="},
+}
+
+// isAssertionOperator сообщает, является ли слово оператором проверки
+func isAssertionOperator(word string) bool {
+	for _, op := range assertionOperators[AssertJSON] {
+		if word == op {
+			return true
+		}
+	}
+	return word == "in" || word == "!in"
+}
+
+// Assertion описывает проверку ответа. В строковой записи части разделяются
+// пробелами: status in 200-299, header Content-Type contains json,
+// json $.items[0].id == 42, body matches ^ok, time < 500ms.
+type Assertion struct {
+	Subject  AssertionSubject `json:"subject"`
+	Target   string           `json:"target,omitempty"` // имя заголовка или выражение JSONPath / jq
+	Operator string           `json:"op"`
+	Value    string           `json:"value,omitempty"`
+}
+
+// AssertionResult содержит результат проверки ответа
+type AssertionResult struct {
+	Assertion string `json:"assertion"` // запись проверки
+	Passed    bool   `json:"passed"`
+	Actual    string `json:"actual,omitempty"` // фактическое значение
+	Error     string `json:"error,omitempty"`  // проверку не удалось выполнить
+}
+
+// AssertionPlaceholder содержит пример записи проверок для поля ввода
+const AssertionPlaceholder = "status in 2xx; header Content-Type contains json; json $.id exists; time < 500ms"
+
+// ParseAssertion разбирает проверку из строки "часть [заголовок|выражение] оператор [значение]".
+// Для кода ответа оператор можно опустить: status 200, status 2xx.
+func ParseAssertion(line string) (Assertion, error) {
+	line = strings.TrimSpace(line)
+	word, rest := cutWord(line)
+	a := Assertion{Subject: AssertionSubject(strings.ToLower(word))}
+	if _, ok := assertionOperators[a.Subject]; !ok {
+		return a, fmt.Errorf("неизвестная проверка %q, ожидается status, header, json, body или time", word)
+	}
+
+	switch a.Subject {
+	case AssertHeader:
+		if a.Target, rest = cutWord(rest); a.Target == "" {
+			return a, fmt.Errorf("%s: не указано имя заголовка", line)
+		}
+	case AssertJSON:
+		if a.Target, rest = cutExpression(rest); a.Target == "" {
+			return a, fmt.Errorf("%s: не указано выражение JSONPath или jq", line)
+		}
+	}
+
+	a.Operator, rest = cutWord(rest)
+	if a.Subject == AssertStatus && a.Operator != "" && !isAssertionOperator(a.Operator) {
+		// Сокращенная запись: status 200, status 2xx, status 200-299
+		a.Operator, rest = "==", a.Operator+" "+rest
+		if strings.ContainsAny(rest, "x-,X") {
+			a.Operator = "in"
+		}
+	}
+	if a.Operator == "" {
+		return a, fmt.Errorf("%s: не указан оператор", line)
+	}
+	if !validOperator(a.Subject, a.Operator) {
+		return a, fmt.Errorf("%s: оператор %s не применим к %s, допустимы: %s",
+			line, a.Operator, a.Subject, strings.Join(assertionOperators[a.Subject], " "))
+	}
+
+	a.Value = strings.TrimSpace(rest)
+	if a.Subject != AssertJSON {
+		// Значение JSON записывается литералом JSON, кавычки остальных значений снимаются
+		a.Value = unquoteValue(a.Value)
+	}
+	if a.Operator == "exists" || a.Operator == "!exists" {
+		if a.Value != "" {
+			return a, fmt.Errorf("%s: у оператора %s нет значения", line, a.Operator)
+		}
+		return a, nil
+	}
+	if a.Value == "" && a.Subject != AssertHeader && a.Subject != AssertBody {
+		return a, fmt.Errorf("%s: не указано значение", line)
+	}
+	return a, a.validate()
+}
+
+// validate проверяет значение, которое разбирается при каждой проверке.
+// Значения с переменными проверяются только после подстановки.
+func (a Assertion) validate() error {
+	if strings.Contains(a.Target+a.Value, "{{") {
+		return nil
+	}
+	switch {
+	case a.Operator == "matches" || a.Operator == "!matches":
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("%s: неверное регулярное выражение: %v", a, err)
+		}
+	case a.Subject == AssertStatus && (a.Operator == "in" || a.Operator == "!in"):
+		if _, err := ParseStatusRanges(a.Value); err != nil {
+			return fmt.Errorf("%s: %v", a, err)
+		}
+	case a.Subject == AssertStatus:
+		if _, err := strconv.Atoi(a.Value); err != nil {
+			return fmt.Errorf("%s: код ответа должен быть числом", a)
+		}
+	case a.Subject == AssertLatency:
+		if _, err := parseLatency(a.Value); err != nil {
+			return fmt.Errorf("%s: %v", a, err)
+		}
+	case a.Subject == AssertJSON:
+		if _, err := QueryJSON(&JSONValue{Kind: JSONNull}, a.Target); err != nil {
+			return fmt.Errorf("%s: %v", a, err)
+		}
+	}
+	return nil
+}
+
+func validOperator(subject AssertionSubject, op string) bool {
+	for _, allowed := range assertionOperators[subject] {
+		if op == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// String возвращает запись проверки, из которой ее можно снова разобрать
+func (a Assertion) String() string {
+	parts := []string{string(a.Subject)}
+	if a.Target != "" {
+		parts = append(parts, a.Target)
+	}
+	parts = append(parts, a.Operator)
+	if a.Value != "" || a.Subject == AssertHeader && a.Operator != "exists" && a.Operator != "!exists" {
+		value := a.Value
+		if a.Subject != AssertJSON {
+			value = quoteValue(value)
+		}
+		parts = append(parts, value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Expand возвращает проверку с подставленными значениями переменных
+func (a Assertion) Expand(vars map[string]string) Assertion {
+	a.Target = ExpandVariables(a.Target, vars)
+	a.Value = ExpandVariables(a.Value, vars)
+	return a
+}
+
+// FormatAssertions записывает проверки в строку "проверка; проверка2"
+func FormatAssertions(assertions []Assertion) string {
+	parts := make([]string, len(assertions))
+	for i, a := range assertions {
+		parts[i] = a.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ParseAssertions разбирает проверки, разделенные точкой с запятой. Точка
+// с запятой в двойных кавычках и скобках не разделяет проверки.
+func ParseAssertions(input string) ([]Assertion, error) {
+	var assertions []Assertion
+	for _, part := range splitAssertions(input) {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		a, err := ParseAssertion(part)
+		if err != nil {
+			return nil, err
+		}
+		assertions = append(assertions, a)
+	}
+	return assertions, nil
+}
+
+func splitAssertions(input string) []string {
+	var parts []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(input); i++ {
+		switch c := input[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == ';' && depth <= 0:
+			parts = append(parts, input[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, input[start:])
+}
+
+// cutWord отделяет первое слово строки
+func cutWord(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if end := strings.IndexAny(s, " \t"); end >= 0 {
+		return s[:end], strings.TrimSpace(s[end:])
+	}
+	return s, ""
+}
+
+// cutExpression отделяет выражение JSONPath или jq, которое может содержать
+// пробелы: выражение заканчивается перед первым оператором проверки вне
+// двойных кавычек и скобок
+func cutExpression(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	var quote byte
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case (c == ' ' || c == '\t') && depth <= 0:
+			if word, _ := cutWord(s[i:]); isAssertionOperator(word) {
+				return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i:])
+			}
+		}
+	}
+	return s, ""
+}
+
+// unquoteValue снимает двойные кавычки со значения
+func unquoteValue(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// quoteValue заключает в кавычки значение, которое иначе не разобрать
+func quoteValue(value string) string {
+	if value == "" || strings.ContainsAny(value, ";\"") || strings.TrimSpace(value) != value {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// parseLatency разбирает время в формате Go (500ms, 1.5s) или в миллисекундах (500)
+func parseLatency(value string) (time.Duration, error) {
+	if ms, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(ms * float64(time.Millisecond)), nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("неверное время %q, ожидается например 500ms или 2s", value)
+	}
+	return d, nil
+}
+
+// --- Выполнение проверок ---
+
+// EvaluateAssertions проверяет ответ. Тело JSON разбирается один раз для всех проверок.
+func EvaluateAssertions(assertions []Assertion, data ResponseData) []AssertionResult {
+	if len(assertions) == 0 {
+		return nil
+	}
+	var (
+		root     *JSONValue
+		parseErr error
+		parsed   bool
+	)
+	document := func() (*JSONValue, error) {
+		if !parsed {
+			parsed = true
+			switch {
+			case data.Binary:
+				parseErr = fmt.Errorf("тело ответа не является текстом")
+			case data.BodyFile != "":
+				parseErr = fmt.Errorf("тело ответа больше лимита max_body (%s)", FormatSize(int64(len(data.Body))))
+			default:
+				if root, parseErr = ParseJSON(data.Body); parseErr != nil {
+					parseErr = fmt.Errorf("тело ответа не является JSON: %v", parseErr)
+				}
+			}
+		}
+		return root, parseErr
+	}
+
+	results := make([]AssertionResult, len(assertions))
+	for i, a := range assertions {
+		result := AssertionResult{Assertion: a.String()}
+		var err error
+		switch a.Subject {
+		case AssertStatus:
+			result.Actual = strconv.Itoa(data.StatusCode)
+			result.Passed, err = checkStatus(a, data.StatusCode)
+		case AssertHeader:
+			result.Passed, result.Actual, err = checkHeader(a, data.Headers)
+		case AssertJSON:
+			var doc *JSONValue
+			if doc, err = document(); err == nil {
+				result.Passed, result.Actual, err = checkJSON(a, doc)
+			}
+		case AssertBody:
+			result.Passed, err = checkText(a.Operator, data.Body, a.Value)
+		case AssertLatency:
+			result.Actual = formatDuration(data.Timings.Total)
+			result.Passed, err = checkLatency(a, data.Timings.Total)
+		default:
+			err = fmt.Errorf("неизвестная проверка %q", a.Subject)
+		}
+		if err != nil {
+			result.Passed, result.Error = false, err.Error()
+		}
+		results[i] = result
+	}
+	return results
+}
+
+// CountPassed возвращает количество пройденных проверок
+func CountPassed(results []AssertionResult) int {
+	passed := 0
+	for _, r := range results {
+		if r.Passed {
+			passed++
+		}
+	}
+	return passed
+}
+
+// FormatAssertionResults форматирует результаты проверок по одной на строке
+func FormatAssertionResults(results []AssertionResult) string {
+	if len(results) == 0 {
+		return "Проверки не заданы"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Пройдено %d из %d\n\n", CountPassed(results), len(results))
+	for _, r := range results {
+		mark := "✓"
+		if !r.Passed {
+			mark = "✗"
+		}
+		fmt.Fprintf(&sb, "%s %s\n", mark, r.Assertion)
+		switch {
+		case r.Error != "":
+			fmt.Fprintf(&sb, "    ошибка: %s\n", r.Error)
+		case !r.Passed && r.Actual != "":
+			fmt.Fprintf(&sb, "    получено: %s\n", r.Actual)
+		}
+	}
+	return sb.String()
+}
+
+func checkStatus(a Assertion, code int) (bool, error) {
+	if a.Operator == "in" || a.Operator == "!in" {
+		ranges, err := ParseStatusRanges(a.Value)
+		if err != nil {
+			return false, err
+		}
+		return StatusInRanges(ranges, code) == (a.Operator == "in"), nil
+	}
+	expected, err := strconv.Atoi(a.Value)
+	if err != nil {
+		return false, fmt.Errorf("код ответа должен быть числом")
+	}
+	actual := &JSONValue{Kind: JSONNumber, Scalar: strconv.Itoa(code)}
+	return compareJSON(actual, a.Operator, &JSONValue{Kind: JSONNumber, Scalar: strconv.Itoa(expected)}), nil
+}
+
+func checkHeader(a Assertion, headers []Header) (bool, string, error) {
+	var values []string
+	for _, h := range headers {
+		if strings.EqualFold(h.Key, a.Target) {
+			values = append(values, h.Value)
+		}
+	}
+	switch a.Operator {
+	case "exists":
+		return len(values) > 0, "", nil
+	case "!exists":
+		return len(values) == 0, strings.Join(values, ", "), nil
+	}
+	if len(values) == 0 {
+		return false, "заголовка нет", nil
+	}
+	actual := strings.Join(values, ", ")
+	switch a.Operator {
+	case "==":
+		return actual == a.Value, actual, nil
+	case "!=":
+		return actual != a.Value, actual, nil
+	}
+	passed, err := checkText(a.Operator, actual, a.Value)
+	return passed, actual, err
+}
+
+// checkText выполняет проверки contains и matches над текстом
+func checkText(op, text, value string) (bool, error) {
+	switch op {
+	case "contains", "!contains":
+		return strings.Contains(text, value) == (op == "contains"), nil
+	case "matches", "!matches":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return false, fmt.Errorf("неверное регулярное выражение: %v", err)
+		}
+		return re.MatchString(text) == (op == "matches"), nil
+	}
+	return false, fmt.Errorf("оператор %s не применим к тексту", op)
+}
+
+// checkJSON проверяет первое найденное выражением значение. Значение
+// проверки разбирается как литерал JSON, а если это не JSON - как строка.
+func checkJSON(a Assertion, root *JSONValue) (bool, string, error) {
+	found, err := QueryJSON(root, a.Target)
+	if err != nil {
+		return false, "", err
+	}
+	if strings.HasPrefix(strings.TrimSpace(a.Target), "$") {
+		// Результат JSONPath - массив найденных значений
+		found = found[0].Items
+	}
+	var actual *JSONValue
+	if len(found) > 0 {
+		actual = found[0]
+	}
+	exists := actual != nil && actual.Kind != JSONNull
+	switch a.Operator {
+	case "exists":
+		return exists, compactJSON(actual), nil
+	case "!exists":
+		return !exists, compactJSON(actual), nil
+	}
+	if actual == nil {
+		return false, "значение не найдено", nil
+	}
+
+	expected, err := ParseJSON(a.Value)
+	if err != nil {
+		expected = &JSONValue{Kind: JSONString, Scalar: a.Value}
+	}
+	text := actual.Scalar
+	if actual.Kind != JSONString {
+		text = actual.Compact()
+	}
+	switch a.Operator {
+	case "contains", "!contains":
+		contains := false
+		switch actual.Kind {
+		case JSONArray:
+			for _, item := range actual.Items {
+				contains = contains || compareJSON(item, "==", expected)
+			}
+		case JSONObject:
+			_, contains = actual.Field(unquoteValue(a.Value))
+		default:
+			contains = strings.Contains(text, unquoteValue(a.Value))
+		}
+		return contains == (a.Operator == "contains"), compactJSON(actual), nil
+	case "matches", "!matches":
+		passed, err := checkText(a.Operator, text, unquoteValue(a.Value))
+		return passed, compactJSON(actual), err
+	}
+	if a.Operator != "==" && a.Operator != "!=" {
+		if _, ok := actual.Number(); !ok && actual.Kind != JSONString {
+			return false, compactJSON(actual), fmt.Errorf("значение %s нельзя сравнить оператором %s", compactJSON(actual), a.Operator)
+		}
+	}
+	return compareJSON(actual, a.Operator, expected), compactJSON(actual), nil
+}
+
+func checkLatency(a Assertion, total time.Duration) (bool, error) {
+	limit, err := parseLatency(a.Value)
+	if err != nil {
+		return false, err
+	}
+	switch a.Operator {
+	case "<":
+		return total < limit, nil
+	case "<=":
+		return total <= limit, nil
+	case ">":
+		return total > limit, nil
+	case ">=":
+		return total >= limit, nil
+	}
+	return false, fmt.Errorf("оператор %s не применим к времени", a.Operator)
+}
+
+// compactJSON записывает значение в одну строку, длинные значения сокращаются
+func compactJSON(v *JSONValue) string {
+	if v == nil {
+		return "значение не найдено"
+	}
+	text := v.Compact()
+	if runes := []rune(text); len(runes) > 120 {
+		text = string(runes[:119]) + "…"
+	}
+	return text
+}
+
+// --- Диапазоны кодов ответа ---
+
+// StatusRange представляет диапазон кодов ответа
+type StatusRange struct {
+	From, To int
+}
+
+// Contains сообщает, попадает ли код в диапазон
+func (r StatusRange) Contains(code int) bool {
+	return code >= r.From && code <= r.To
+}
+
+// StatusInRanges сообщает, попадает ли код хотя бы в один из диапазонов
+func StatusInRanges(ranges []StatusRange, code int) bool {
+	for _, r := range ranges {
+		if r.Contains(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseStatusRanges разбирает список диапазонов вида "400-499,503" или "2xx,304"
+func ParseStatusRanges(spec string) ([]StatusRange, error) {
+	var ranges []StatusRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) == 3 && strings.HasSuffix(strings.ToLower(part), "xx") && part[0] >= '1' && part[0] <= '5' {
+			from := int(part[0]-'0') * 100
+			ranges = append(ranges, StatusRange{From: from, To: from + 99})
+			continue
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		from, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return nil, fmt.Errorf("неверный диапазон кодов %q", part)
+		}
+		to := from
+		if len(bounds) == 2 {
+			if to, err = strconv.Atoi(strings.TrimSpace(bounds[1])); err != nil {
+				return nil, fmt.Errorf("неверный диапазон кодов %q", part)
+			}
+		}
+		ranges = append(ranges, StatusRange{From: from, To: to})
+	}
+	return ranges, nil
+}
@@ -0,0 +1,217 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAssertion(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		want Assertion
+	}{
+		{"status 200", Assertion{Subject: AssertStatus, Operator: "==", Value: "200"}},
+		{"status 2xx", Assertion{Subject: AssertStatus, Operator: "in", Value: "2xx"}},
+		{"status 200-299, 304", Assertion{Subject: AssertStatus, Operator: "in", Value: "200-299, 304"}},
+		{"STATUS != 500", Assertion{Subject: AssertStatus, Operator: "!=", Value: "500"}},
+		{"status !in 4xx,5xx", Assertion{Subject: AssertStatus, Operator: "!in", Value: "4xx,5xx"}},
+		{"header Content-Type contains json", Assertion{Subject: AssertHeader, Target: "Content-Type", Operator: "contains", Value: "json"}},
+		{"header X-Id exists", Assertion{Subject: AssertHeader, Target: "X-Id", Operator: "exists"}},
+		{`header X-Empty == ""`, Assertion{Subject: AssertHeader, Target: "X-Empty", Operator: "=="}},
+		{`header X-Msg == "a; b"`, Assertion{Subject: AssertHeader, Target: "X-Msg", Operator: "==", Value: "a; b"}},
+		// Выражение может содержать пробелы, значение JSON остается литералом
+		{"json $.items[?(@.price < 10)].name == \"pen\"", Assertion{Subject: AssertJSON, Target: "$.items[?(@.price < 10)].name", Operator: "==", Value: `"pen"`}},
+		{"json .items[] | select(.active) | .id >= 2", Assertion{Subject: AssertJSON, Target: ".items[] | select(.active) | .id", Operator: ">=", Value: "2"}},
+		{"json $.id !exists", Assertion{Subject: AssertJSON, Target: "$.id", Operator: "!exists"}},
+		{"json $.name matches {{pattern}}", Assertion{Subject: AssertJSON, Target: "$.name", Operator: "matches", Value: "{{pattern}}"}},
+		{"body !contains error", Assertion{Subject: AssertBody, Operator: "!contains", Value: "error"}},
+		{"time < 500ms", Assertion{Subject: AssertLatency, Operator: "<", Value: "500ms"}},
+	} {
+		got, err := ParseAssertion(tc.line)
+		if err != nil {
+			t.Errorf("%s: ошибка %v", tc.line, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: разобрано %+v, ожидалось %+v", tc.line, got, tc.want)
+		}
+		// Запись проверки разбирается в ту же проверку
+		if again, err := ParseAssertion(got.String()); err != nil || again != got {
+			t.Errorf("%s: повторный разбор %q = %+v, %v", tc.line, got.String(), again, err)
+		}
+	}
+}
+
+func TestParseAssertionErrors(t *testing.T) {
+	for line, want := range map[string]string{
+		"cookie a == b":           "неизвестная проверка",
+		".id >= 2":                "неизвестная проверка",
+		"header":                  "не указано имя заголовка",
+		"json":                    "не указано выражение",
+		"status":                  "не указан оператор",
+		"body == ok":              "оператор == не применим к body",
+		"time contains 5":         "оператор contains не применим к time",
+		"header X exists yes":     "у оператора exists нет значения",
+		"status ==":               "не указано значение",
+		"status == abc":           "код ответа должен быть числом",
+		"status in 2xx,abc":       `неверный диапазон кодов "abc"`,
+		"time < soon":             `неверное время "soon"`,
+		"body matches (":          "неверное регулярное выражение",
+		"json $.items[a] == 1":    "ожидается индекс",
+		"json .a | unknown == 1":  "неизвестное выражение",
+		"header X-Id matches [a-": "неверное регулярное выражение",
+	} {
+		_, err := ParseAssertion(line)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: ошибка %v, ожидалось %q", line, err, want)
+		}
+	}
+}
+
+func TestParseAssertions(t *testing.T) {
+	input := `status 2xx; json $.items[?(@.name == "a;b")] exists; header X-Msg == "c; d";; body contains ok`
+	assertions, err := ParseAssertions(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"status in 2xx",
+		`json $.items[?(@.name == "a;b")] exists`,
+		`header X-Msg == "c; d"`,
+		"body contains ok",
+	}
+	var got []string
+	for _, a := range assertions {
+		got = append(got, a.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("проверки = %q, ожидалось %q", got, want)
+	}
+	if FormatAssertions(assertions) != strings.Join(want, "; ") {
+		t.Errorf("запись проверок = %q", FormatAssertions(assertions))
+	}
+}
+
+func TestEvaluateAssertions(t *testing.T) {
+	data := ResponseData{
+		StatusCode: 201,
+		Headers: []Header{
+			{Key: "Content-Type", Value: "application/json"},
+			{Key: "Set-Cookie", Value: "a=1"},
+			{Key: "set-cookie", Value: "b=2"},
+		},
+		Body:    `{"id": 42, "name": "pen", "price": 9.5, "tags": ["a", "b"], "meta": {"k": 1}, "none": null, "ok": true, "code": "007"}`,
+		Timings: Timings{Total: 300 * time.Millisecond},
+	}
+	for _, tc := range []struct {
+		line   string
+		passed bool
+		actual string
+		err    string
+	}{
+		{"status 201", true, "201", ""},
+		{"status 2xx", true, "201", ""},
+		{"status !in 200,204", true, "201", ""},
+		{"status > 201", false, "201", ""},
+		{"header content-type == application/json", true, "application/json", ""},
+		{"header Set-Cookie == a=1, b=2", true, "a=1, b=2", ""},
+		{"header Content-Type matches ^application/", true, "application/json", ""},
+		{"header X-Missing exists", false, "", ""},
+		{"header X-Missing !exists", true, "", ""},
+		{"header X-Missing == a", false, "заголовка нет", ""},
+		{"body contains \"name\"", true, "", ""},
+		{`body !matches "error|fail"`, true, "", ""},
+		{"time < 500ms", true, "300ms", ""},
+		{"time >= 1", true, "300ms", ""},
+		{"time > 1s", false, "300ms", ""},
+		// Значение JSON разбирается как литерал, иначе как строка
+		{"json $.id == 42", true, "42", ""},
+		{"json $.id == 42.0", true, "42", ""},
+		{`json $.id == "42"`, false, "42", ""},
+		{"json $.name == pen", true, `"pen"`, ""},
+		{`json $.name == "pen"`, true, `"pen"`, ""},
+		{"json $.price < 10", true, "9.5", ""},
+		{"json $.name < q", true, `"pen"`, ""},
+		{"json $.code == 7", false, `"007"`, ""},
+		{"json $.ok == true", true, "true", ""},
+		{"json .tags | length == 2", true, "2", ""},
+		{"json $.tags[1] == b", true, `"b"`, ""},
+		{"json $.tags[-1] == b", true, `"b"`, ""},
+		{"json $.tags[5] exists", false, "значение не найдено", ""},
+		{"json $.tags contains a", true, `["a","b"]`, ""},
+		{"json $.tags !contains c", true, `["a","b"]`, ""},
+		{"json $.meta contains k", true, `{"k":1}`, ""},
+		{"json $.name contains en", true, `"pen"`, ""},
+		{"json $.name matches ^p.n$", true, `"pen"`, ""},
+		{"json $.id matches ^4", true, "42", ""},
+		// null и отсутствующее значение не существуют
+		{"json $.none exists", false, "null", ""},
+		{"json $.none == null", true, "null", ""},
+		{"json $.missing !exists", true, "значение не найдено", ""},
+		{"json $.missing == 1", false, "значение не найдено", ""},
+		{"json .missing.deep == 1", false, "значение не найдено", ""},
+		// Несовместимые типы: объект не сравнивается по порядку
+		{"json $.meta > 1", false, `{"k":1}`, "значение {\"k\":1} нельзя сравнить оператором >"},
+		{"json $.ok < 1", false, "true", "нельзя сравнить"},
+		{"json $.name > 1", false, `"pen"`, ""},
+		{"json $.meta == {\"k\": 1}", true, `{"k":1}`, ""},
+	} {
+		a, err := ParseAssertion(tc.line)
+		if err != nil {
+			t.Errorf("%s: ошибка разбора %v", tc.line, err)
+			continue
+		}
+		r := EvaluateAssertions([]Assertion{a}, data)[0]
+		if r.Passed != tc.passed || r.Actual != tc.actual || !strings.Contains(r.Error, tc.err) || tc.err == "" && r.Error != "" {
+			t.Errorf("%s: пройдена %v, получено %q, ошибка %q; ожидалось %v, %q, %q",
+				tc.line, r.Passed, r.Actual, r.Error, tc.passed, tc.actual, tc.err)
+		}
+	}
+}
+
+func TestEvaluateAssertionsBodyErrors(t *testing.T) {
+	a, err := ParseAssertions("json $.id exists; body contains x; status 200")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		data ResponseData
+		err  string
+	}{
+		{ResponseData{StatusCode: 200, Body: "<html>x</html>"}, "тело ответа не является JSON"},
+		{ResponseData{StatusCode: 200, Body: "\x00x", Binary: true}, "тело ответа не является текстом"},
+		{ResponseData{StatusCode: 200, Body: `{"id": 1} x`, BodyFile: "/tmp/body"}, "тело ответа больше лимита max_body"},
+	} {
+		results := EvaluateAssertions(a, tc.data)
+		if !strings.Contains(results[0].Error, tc.err) || results[0].Passed {
+			t.Errorf("json: результат %+v, ожидалась ошибка %q", results[0], tc.err)
+		}
+		// Остальные проверки выполняются независимо от тела JSON
+		if !results[1].Passed || !results[2].Passed || CountPassed(results) != 2 {
+			t.Errorf("результаты = %+v", results)
+		}
+	}
+}
+
+func TestParseStatusRanges(t *testing.T) {
+	ranges, err := ParseStatusRanges("2xx, 304,400 - 404")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []StatusRange{{200, 299}, {304, 304}, {400, 404}}
+	if !reflect.DeepEqual(ranges, want) {
+		t.Errorf("диапазоны = %+v, ожидалось %+v", ranges, want)
+	}
+	for code, in := range map[int]bool{199: false, 200: true, 299: true, 304: true, 305: false, 404: true, 405: false} {
+		if StatusInRanges(ranges, code) != in {
+			t.Errorf("код %d в диапазонах: %v", code, !in)
+		}
+	}
+	for _, spec := range []string{"6xx", "abc", "200-", ""} {
+		if _, err := ParseStatusRanges(spec); err == nil {
+			t.Errorf("%q: ожидалась ошибка", spec)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"strings"
+)
+
+// AuthType определяет способ авторизации запроса
+type AuthType string
+
+const (
+	AuthNone   AuthType = ""
+	AuthBasic  AuthType = "basic"
+	AuthBearer AuthType = "bearer"
+	AuthAPIKey AuthType = "apikey"
+	AuthDigest AuthType = "digest"
+	AuthOAuth2 AuthType = "oauth2"
+)
+
+// AuthTypes перечисляет способы авторизации в порядке переключения в интерфейсе
+var AuthTypes = []AuthType{AuthNone, AuthBasic, AuthBearer, AuthAPIKey, AuthDigest, AuthOAuth2}
+
+// Name возвращает название способа авторизации для интерфейса
+func (t AuthType) Name() string {
+	switch t {
+	case AuthBasic:
+		return "Basic"
+	case AuthBearer:
+		return "Bearer"
+	case AuthAPIKey:
+		return "API Key"
+	case AuthDigest:
+		return "Digest"
+	case AuthOAuth2:
+		return "OAuth2"
+	}
+	return "Нет"
+}
+
+// Next возвращает следующий (delta > 0) или предыдущий способ авторизации
+func (t AuthType) Next(delta int) AuthType {
+	index := 0
+	for i, at := range AuthTypes {
+		if at == t {
+			index = i
+		}
+	}
+	count := len(AuthTypes)
+	return AuthTypes[((index+delta)%count+count)%count]
+}
+
+// Расположение ключа API
+const (
+	APIKeyInHeader = "header"
+	APIKeyInQuery  = "query"
+)
+
+// Типы получения токена OAuth2
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantPassword          = "password"
+)
+
+// Auth описывает авторизацию запроса. Используются только поля выбранного способа,
+// во всех значениях подставляются переменные окружения.
+type Auth struct {
+	Type AuthType `json:"type,omitempty"`
+
+	// Basic, Digest и OAuth2 (password)
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// Bearer
+	Token string `json:"token,omitempty"`
+
+	// API Key
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value,omitempty"`
+	In    string `json:"in,omitempty"` // header (по умолчанию) или query
+
+	// OAuth2
+	Grant        string `json:"grant,omitempty"` // client_credentials (по умолчанию) или password
+	TokenURL     string `json:"tokenUrl,omitempty"`
+	ClientID     string `json:"clientId,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// authFields перечисляет параметры каждого способа авторизации в порядке вывода
+var authFields = map[AuthType][]string{
+	AuthBasic:  {"username", "password"},
+	AuthBearer: {"token"},
+	AuthAPIKey: {"key", "value", "in"},
+	AuthDigest: {"username", "password"},
+	AuthOAuth2: {"grant", "token_url", "client_id", "client_secret", "scope", "username", "password"},
+}
+
+func (a *Auth) field(name string) *string {
+	switch name {
+	case "username":
+		return &a.Username
+	case "password":
+		return &a.Password
+	case "token":
+		return &a.Token
+	case "key":
+		return &a.Key
+	case "value":
+		return &a.Value
+	case "in":
+		return &a.In
+	case "grant":
+		return &a.Grant
+	case "token_url":
+		return &a.TokenURL
+	case "client_id":
+		return &a.ClientID
+	case "client_secret":
+		return &a.ClientSecret
+	case "scope":
+		return &a.Scope
+	}
+	return nil
+}
+
+// AuthPlaceholder возвращает подсказку формата параметров способа авторизации
+func AuthPlaceholder(t AuthType) string {
+	fields := authFields[t]
+	if len(fields) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(fields))
+	for i, name := range fields {
+		pairs[i] = name + "=..."
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// FormatAuthParams записывает параметры авторизации в строку "name=value; name2=value2".
+// Значения с точкой с запятой, кавычками или пробелами по краям записываются в двойных кавычках.
+func FormatAuthParams(a Auth) string {
+	var pairs []string
+	for _, name := range authFields[a.Type] {
+		if value := *a.field(name); value != "" {
+			pairs = append(pairs, name+"="+quoteValue(value))
+		}
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// ParseAuthParams разбирает строку "name=value; name2=value2" в авторизацию указанного типа.
+// Параметры, не относящиеся к выбранному способу, игнорируются.
+func ParseAuthParams(t AuthType, params string) Auth {
+	auth := Auth{Type: t}
+	for _, pair := range splitAuthParams(params) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		for _, allowed := range authFields[t] {
+			if name == allowed {
+				*auth.field(name) = unquoteValue(strings.TrimSpace(parts[1]))
+			}
+		}
+	}
+	return auth
+}
+
+// splitAuthParams разделяет параметры по точке с запятой. Точка с запятой
+// в значении, которое начинается с двойной кавычки, параметры не разделяет.
+func splitAuthParams(params string) []string {
+	var parts []string
+	start, inValue, quoted := 0, false, false
+	for i := 0; i < len(params); i++ {
+		switch c := params[i]; {
+		case quoted:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quoted = false
+			}
+		case c == ';':
+			parts = append(parts, params[start:i])
+			start, inValue = i+1, false
+		case c == '=' && !inValue:
+			inValue = true
+			value := strings.TrimLeft(params[i+1:], " \t")
+			if strings.HasPrefix(value, "\"") {
+				quoted = true
+				i = len(params) - len(value)
+			}
+		}
+	}
+	return append(parts, params[start:])
+}
+
+// Expand возвращает авторизацию с подставленными значениями переменных
+func (a Auth) Expand(vars map[string]string) Auth {
+	for _, value := range []*string{
+		&a.Username, &a.Password, &a.Token, &a.Key, &a.Value, &a.In,
+		&a.Grant, &a.TokenURL, &a.ClientID, &a.ClientSecret, &a.Scope,
+	} {
+		*value = ExpandVariables(*value, vars)
+	}
+	return a
+}
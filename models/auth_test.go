@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestAuthParamsRoundTrip(t *testing.T) {
+	for _, a := range []Auth{
+		{Type: AuthBasic, Username: "admin", Password: "p;ss"},
+		{Type: AuthBasic, Username: "bob", Password: `say "hi"; bye`},
+		{Type: AuthDigest, Username: " spaced ", Password: `back\slash;`},
+		{Type: AuthBearer, Token: "abc==;def"},
+		{Type: AuthAPIKey, Key: "X-Key", Value: "{{apiKey}};x=1", In: APIKeyInQuery},
+		{
+			Type: AuthOAuth2, Grant: GrantPassword, TokenURL: "https://auth.example.com/token?a=1;b=2",
+			ClientID: "app", ClientSecret: `s;e"c`, Scope: "read write", Username: "bob", Password: "=;=",
+		},
+	} {
+		params := FormatAuthParams(a)
+		if got := ParseAuthParams(a.Type, params); got != a {
+			t.Errorf("%q: разобрано %+v, ожидалось %+v", params, got, a)
+		}
+	}
+}
+
+func TestParseAuthParams(t *testing.T) {
+	for _, tc := range []struct {
+		t      AuthType
+		params string
+		want   Auth
+	}{
+		// Значения без кавычек записывались и до экранирования
+		{AuthBasic, "username=admin; password=secret", Auth{Type: AuthBasic, Username: "admin", Password: "secret"}},
+		{AuthBearer, "token = abc==", Auth{Type: AuthBearer, Token: "abc=="}},
+		{AuthBasic, `username=a"b; password=c`, Auth{Type: AuthBasic, Username: `a"b`, Password: "c"}},
+		{AuthBasic, `username="a;b" ; password = "c\"d"`, Auth{Type: AuthBasic, Username: "a;b", Password: `c"d`}},
+		// Параметры другого способа и пары без значения пропускаются
+		{AuthBearer, "username=admin; broken; TOKEN=t", Auth{Type: AuthBearer, Token: "t"}},
+		{AuthBasic, `password="unterminated; x`, Auth{Type: AuthBasic, Password: `"unterminated; x`}},
+	} {
+		if got := ParseAuthParams(tc.t, tc.params); got != tc.want {
+			t.Errorf("%q: разобрано %+v, ожидалось %+v", tc.params, got, tc.want)
+		}
+	}
+}
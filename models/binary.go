@@ -0,0 +1,205 @@
+package models
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxBodySize - размер тела ответа, который хранится в памяти, если
+	// в настройках не задан другой. Остаток тела переносится во временный файл.
+	DefaultMaxBodySize = 10 * 1024 * 1024
+	// maxPrettyBodySize ограничивает размер тела, которое форматируется и подсвечивается
+	maxPrettyBodySize = 1024 * 1024
+	// maxHexDumpSize ограничивает часть двоичного тела, показываемую дампом
+	maxHexDumpSize = 64 * 1024
+	// binarySniffSize - начало тела, по которому определяются двоичные данные
+	binarySniffSize = 8 * 1024
+)
+
+// ParseSize разбирает размер в байтах: 1048576, 512KB, 10MB, 1GB
+func ParseSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix     string
+		multiplier int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, multiplier = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix)), unit.multiplier
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("неверный размер %q, ожидается например 512KB или 10MB", value)
+	}
+	return n * multiplier, nil
+}
+
+// binaryMediaTypes - типы содержимого, которые всегда показываются дампом
+var binaryMediaTypes = map[string]bool{
+	"application/pdf": true, "application/zip": true, "application/gzip": true, "application/x-gzip": true,
+	"application/x-tar": true, "application/x-7z-compressed": true, "application/x-rar-compressed": true,
+	"application/x-bzip2": true, "application/zstd": true, "application/wasm": true,
+	"application/protobuf": true, "application/x-protobuf": true, "application/vnd.google.protobuf": true,
+	"application/msgpack": true, "application/x-msgpack": true, "application/cbor": true,
+}
+
+// IsBinaryContent сообщает, являются ли данные двоичными, по Content-Type
+// и началу тела: нулевые байты, управляющие символы и неверные
+// последовательности UTF-8 в тексте встречаются редко
+func IsBinaryContent(contentType string, sample []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		return false
+	case binaryMediaTypes[mediaType]:
+		return true
+	}
+	if kind, _, _ := strings.Cut(mediaType, "/"); kind == "image" || kind == "audio" || kind == "video" || kind == "font" {
+		return true
+	}
+
+	sample = sample[:min(len(sample), binarySniffSize)]
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return true
+	}
+	suspicious := 0
+	for i := 0; i < len(sample); {
+		r, size := utf8.DecodeRune(sample[i:])
+		switch {
+		case r == utf8.RuneError && size == 1 && len(sample)-i >= utf8.UTFMax:
+			// Неполная последовательность в конце образца не учитывается
+			suspicious++
+		case r < 0x20 && !strings.ContainsRune("\n\r\t\f\x1b", r):
+			suspicious++
+		}
+		i += size
+	}
+	return suspicious*10 > len(sample)
+}
+
+// FormatHexDump возвращает шестнадцатеричный дамп начала данных
+func FormatHexDump(data string) string {
+	dump := hex.Dump([]byte(data[:min(len(data), maxHexDumpSize)]))
+	if len(data) > maxHexDumpSize {
+		dump += fmt.Sprintf("… показаны первые %s из %s", FormatSize(maxHexDumpSize), FormatSize(int64(len(data))))
+	}
+	return strings.TrimSuffix(dump, "\n")
+}
+
+// SanitizeText подготавливает текст тела к выводу в терминал: неверные
+// последовательности UTF-8 заменяются на U+FFFD, а управляющие символы,
+// кроме перевода строки и табуляции, - на их видимые обозначения (␛, ␀)
+func SanitizeText(s string) string {
+	clean := true
+	for _, r := range s {
+		if r == utf8.RuneError || r < 0x20 && r != '\n' && r != '\t' || r >= 0x7f && r < 0xa0 {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return s
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == '\n' || r == '\t':
+			sb.WriteRune(r)
+		case r < 0x20:
+			sb.WriteRune(0x2400 + r)
+		case r == 0x7f:
+			sb.WriteRune('␡')
+		case r >= 0x80 && r < 0xa0:
+			sb.WriteRune(utf8.RuneError)
+		default:
+			// Неверные байты range возвращает как U+FFFD
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// fileExtensions - расширения для частых типов, у которых в системе их несколько
+var fileExtensions = map[string]string{
+	"application/json": ".json", "application/xml": ".xml", "text/xml": ".xml", "text/html": ".html",
+	"text/plain": ".txt", "text/csv": ".csv", "application/yaml": ".yaml", "image/jpeg": ".jpg",
+	"image/png": ".png", "image/gif": ".gif", "image/svg+xml": ".svg", "application/pdf": ".pdf",
+	"application/zip": ".zip", "application/gzip": ".gz", "application/octet-stream": ".bin",
+}
+
+// ResponseFileName предлагает имя файла для тела ответа: из Content-Disposition,
+// из последнего сегмента адреса или по типу содержимого
+func ResponseFileName(data ResponseData) string {
+	if _, params, err := mime.ParseMediaType(HeaderValue(data.Headers, "Content-Disposition")); err == nil {
+		if name := path.Base(params["filename"]); params["filename"] != "" && name != "/" && name != ".." {
+			return name
+		}
+	}
+	if u, err := url.Parse(data.URL); err == nil {
+		if name := path.Base(u.Path); strings.Contains(name, ".") && name != "." && name != ".." {
+			return name
+		}
+	}
+	ext := ".txt"
+	if data.Binary {
+		ext = ".bin"
+	}
+	mediaType, _, _ := mime.ParseMediaType(HeaderValue(data.Headers, "Content-Type"))
+	if preferred, ok := fileExtensions[mediaType]; ok {
+		ext = preferred
+	} else if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		ext = exts[0]
+	}
+	return "response" + ext
+}
+
+// CopyResponseBody записывает полное тело ответа. Тело, не поместившееся
+// в память, копируется из временного файла.
+func CopyResponseBody(w io.Writer, data ResponseData) (int64, error) {
+	if data.BodyFile == "" {
+		return io.Copy(w, strings.NewReader(data.Body))
+	}
+	file, err := os.Open(data.BodyFile)
+	if err != nil {
+		return 0, fmt.Errorf("временный файл тела недоступен: %w", err)
+	}
+	defer file.Close()
+	return io.Copy(w, file)
+}
+
+// WriteResponseBody сохраняет тело ответа в новый файл и возвращает количество записанных байт
+func WriteResponseBody(data ResponseData, filename string) (int64, error) {
+	out, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if os.IsExist(err) {
+			return 0, fmt.Errorf("файл %s уже существует", filename)
+		}
+		return 0, err
+	}
+	written, err := CopyResponseBody(out, data)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return written, err
+}
+
+// RemoveBodyFile удаляет временный файл тела ответа, если он есть
+func RemoveBodyFile(data ResponseData) {
+	if data.BodyFile != "" {
+		os.Remove(data.BodyFile)
+	}
+}
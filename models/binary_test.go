@@ -0,0 +1,148 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsBinaryContent(t *testing.T) {
+	text := strings.Repeat("строка текста\n", 10)
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		sample      string
+		want        bool
+	}{
+		{"JSON", "application/json", `{"a": 1}`, false},
+		{"текст UTF-8", "text/plain", text, false},
+		{"пустое тело", "", "", false},
+		{"без типа", "", "plain text\r\n\tand tabs\f", false},
+		{"ANSI-последовательности", "text/plain", "\x1b[31mred\x1b[0m", false},
+		// Тип содержимого определяет результат независимо от тела
+		{"изображение", "image/png", "text", true},
+		{"шрифт", "font/woff2", "", true},
+		{"PDF", "application/pdf; name=a.pdf", "%PDF-1.7", true},
+		{"protobuf", "application/x-protobuf", "", true},
+		{"+json", "application/problem+json", "\x00", false},
+		{"+xml", "image/svg+xml", "<svg/>", false},
+		// Без известного типа решает содержимое
+		{"нулевой байт", "application/octet-stream", "abc\x00def", true},
+		{"нулевой байт в тексте", "text/plain", "abc\x00def", true},
+		{"управляющие символы", "", strings.Repeat("\x01\x02ab", 10), true},
+		{"неверный UTF-8", "", strings.Repeat("\xff\xfeab", 10), true},
+		{"редкие неверные байты", "", text + "\xff", false},
+		// Последовательность UTF-8, обрезанная на границе образца, не считается ошибкой
+		{"обрезанный символ", "", strings.Repeat("я", binarySniffSize/2-1) + "a\xd0", false},
+		{"обрезанный короткий образец", "", "a\xd0", false},
+	} {
+		if got := IsBinaryContent(tc.contentType, []byte(tc.sample)); got != tc.want {
+			t.Errorf("%s: двоичное %v, ожидалось %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	for value, want := range map[string]int64{
+		"1048576": 1048576,
+		"512KB":   512 << 10,
+		"512 kb":  512 << 10,
+		"10MB":    10 << 20,
+		"10m":     10 << 20,
+		"1GB":     1 << 30,
+		"100B":    100,
+	} {
+		if got, err := ParseSize(value); err != nil || got != want {
+			t.Errorf("%s = %d, %v; ожидалось %d", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "0", "-1MB", "MB", "1.5MB", "10TB"} {
+		if _, err := ParseSize(value); err == nil {
+			t.Errorf("%q: ожидалась ошибка", value)
+		}
+	}
+}
+
+func TestFormatHexDump(t *testing.T) {
+	if got, want := FormatHexDump("PK\x03\x04"), "00000000  50 4b 03 04                                       |PK..|"; got != want {
+		t.Errorf("дамп = %q, ожидалось %q", got, want)
+	}
+	dump := FormatHexDump(strings.Repeat("\x00", maxHexDumpSize+1))
+	if !strings.HasSuffix(dump, "… показаны первые 64.0 КБ (65536 Б) из 64.0 КБ (65537 Б)") {
+		t.Errorf("дамп длинных данных заканчивается на %q", dump[len(dump)-80:])
+	}
+}
+
+func TestSanitizeText(t *testing.T) {
+	for input, want := range map[string]string{
+		"обычный\tтекст\n":  "обычный\tтекст\n",
+		"a\r\nb":            "a\nb",
+		"\x1b[31mred\x00":   "␛[31mred␀",
+		"del\x7f":           "del␡",
+		"bad\xffutf8":       "bad�utf8",
+		"c1\u0085control":   "c1�control",
+		"lone\rreturn\x07!": "lone␍return␇!",
+	} {
+		if got := SanitizeText(input); got != want {
+			t.Errorf("%q = %q, ожидалось %q", input, got, want)
+		}
+	}
+}
+
+func TestResponseFileName(t *testing.T) {
+	for _, tc := range []struct {
+		data ResponseData
+		want string
+	}{
+		{ResponseData{Headers: []Header{{Key: "Content-Disposition", Value: `attachment; filename="report.csv"`}}}, "report.csv"},
+		// Путь в имени файла отбрасывается
+		{ResponseData{Headers: []Header{{Key: "Content-Disposition", Value: `attachment; filename="../../etc/passwd"`}}}, "passwd"},
+		{ResponseData{URL: "https://example.com/files/archive.tar.gz?x=1"}, "archive.tar.gz"},
+		{ResponseData{URL: "https://example.com/api/users", Headers: []Header{{Key: "Content-Type", Value: "application/json; charset=utf-8"}}}, "response.json"},
+		{ResponseData{URL: "https://example.com/", Binary: true}, "response.bin"},
+		{ResponseData{URL: "https://example.com/"}, "response.txt"},
+	} {
+		if got := ResponseFileName(tc.data); got != tc.want {
+			t.Errorf("%+v: имя файла %q, ожидалось %q", tc.data, got, tc.want)
+		}
+	}
+}
+
+func TestWriteResponseBody(t *testing.T) {
+	dir := t.TempDir()
+	spilled := filepath.Join(dir, "spilled")
+	if err := os.WriteFile(spilled, []byte("full body"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]ResponseData{
+		"memory": {Body: "in memory"},
+		"file":   {Body: "full", BodyFile: spilled},
+	} {
+		target := filepath.Join(dir, name+".out")
+		written, err := WriteResponseBody(data, target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved, _ := os.ReadFile(target)
+		want := data.Body
+		if data.BodyFile != "" {
+			want = "full body"
+		}
+		if string(saved) != want || written != int64(len(want)) {
+			t.Errorf("%s: записано %d байт %q, ожидалось %q", name, written, saved, want)
+		}
+		// Существующий файл не перезаписывается
+		if _, err := WriteResponseBody(data, target); err == nil || !strings.Contains(err.Error(), "уже существует") {
+			t.Errorf("%s: повторная запись: %v", name, err)
+		}
+	}
+
+	RemoveBodyFile(ResponseData{BodyFile: spilled})
+	if _, err := os.Stat(spilled); !os.IsNotExist(err) {
+		t.Errorf("временный файл не удален: %v", err)
+	}
+	if _, err := CopyResponseBody(&strings.Builder{}, ResponseData{BodyFile: spilled}); err == nil {
+		t.Error("ожидалась ошибка недоступного временного файла")
+	}
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"strings"
+)
+
+// BodyType определяет, как формируется тело запроса
+type BodyType string
+
+const (
+	BodyRaw       BodyType = "" // текст отправляется как есть
+	BodyJSON      BodyType = "json"
+	BodyForm      BodyType = "form" // application/x-www-form-urlencoded
+	BodyMultipart BodyType = "multipart"
+	BodyBinary    BodyType = "binary" // содержимое файла
+	BodyNone      BodyType = "none"
+)
+
+// BodyTypes перечисляет типы тела в порядке переключения в интерфейсе
+var BodyTypes = []BodyType{BodyRaw, BodyJSON, BodyForm, BodyMultipart, BodyBinary, BodyNone}
+
+// Name возвращает название типа тела для интерфейса
+func (t BodyType) Name() string {
+	switch t {
+	case BodyJSON:
+		return "JSON"
+	case BodyForm:
+		return "Form"
+	case BodyMultipart:
+		return "Multipart"
+	case BodyBinary:
+		return "Файл"
+	case BodyNone:
+		return "Нет"
+	}
+	return "Raw"
+}
+
+// Next возвращает следующий (delta > 0) или предыдущий тип тела
+func (t BodyType) Next(delta int) BodyType {
+	index := 0
+	for i, bt := range BodyTypes {
+		if bt == t {
+			index = i
+		}
+	}
+	count := len(BodyTypes)
+	return BodyTypes[((index+delta)%count+count)%count]
+}
+
+// HasForm сообщает, задается ли тело полями формы
+func (t BodyType) HasForm() bool {
+	return t == BodyForm || t == BodyMultipart
+}
+
+// BodyPlaceholder возвращает подсказку формата тела
+func BodyPlaceholder(t BodyType) string {
+	switch t {
+	case BodyForm:
+		return "name=value (по одному полю на строке)"
+	case BodyMultipart:
+		return "name=value\nfile=@/path/to/file.png;type=image/png"
+	case BodyBinary:
+		return "/path/to/file"
+	case BodyNone:
+		return "Запрос без тела"
+	}
+	return "{\"key\": \"value\"}"
+}
+
+// FormField представляет поле формы. В multipart поле может ссылаться на локальный файл.
+type FormField struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`                 // значение или путь к файлу
+	File        bool   `json:"file,omitempty"`        // Value - путь к файлу (только multipart)
+	ContentType string `json:"contentType,omitempty"` // тип содержимого файла
+}
+
+// FormatFormFields записывает поля формы по одному на строке: "name=value",
+// файлы - в форме curl "name=@path;type=content/type"
+func FormatFormFields(fields []FormField) string {
+	lines := make([]string, len(fields))
+	for i, f := range fields {
+		value := f.Value
+		if f.File {
+			value = "@" + value
+			if f.ContentType != "" {
+				value += ";type=" + f.ContentType
+			}
+		}
+		lines[i] = f.Key + "=" + value
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseFormFields разбирает поля формы, записанные FormatFormFields. Значения
+// с префиксом @ считаются путями к файлам, если files == true (multipart).
+func ParseFormFields(input string, files bool) []FormField {
+	var fields []FormField
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(line, "=")
+		field := FormField{Key: strings.TrimSpace(key), Value: value}
+		if files && strings.HasPrefix(value, "@") {
+			field.File = true
+			field.Value = strings.TrimPrefix(value, "@")
+			if path, contentType, ok := strings.Cut(field.Value, ";type="); ok {
+				field.Value, field.ContentType = path, strings.TrimSpace(contentType)
+			}
+			field.Value = strings.TrimSpace(field.Value)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// ExpandFormFields возвращает копию полей с подставленными значениями переменных
+func ExpandFormFields(fields []FormField, vars map[string]string) []FormField {
+	expanded := make([]FormField, len(fields))
+	for i, f := range fields {
+		f.Key = ExpandVariables(f.Key, vars)
+		f.Value = ExpandVariables(f.Value, vars)
+		expanded[i] = f
+	}
+	return expanded
+}
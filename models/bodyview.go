@@ -0,0 +1,505 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	searchMatchStyle   = lipgloss.NewStyle().Background(lipgloss.Color("58")).Foreground(lipgloss.Color("229"))
+	currentMatchStyle  = lipgloss.NewStyle().Background(lipgloss.Color("205")).Foreground(lipgloss.Color("0")).Bold(true)
+	treeCursorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+	bodyViewErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	bodyViewNoteStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	assertPassStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true)
+	assertFailStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+)
+
+// ResponsePrompt определяет, что вводится в поле вкладки "Ответ"
+type ResponsePrompt int
+
+const (
+	ResponsePromptNone   ResponsePrompt = iota
+	ResponsePromptSearch                // поиск по содержимому подвкладки
+	ResponsePromptFilter                // выражение JSONPath или jq для тела
+	ResponsePromptSave                  // путь файла для сохранения тела
+)
+
+// searchMatch описывает найденное вхождение: строку и байтовые границы в ней
+type searchMatch struct {
+	line, start, end int
+}
+
+// bodyViewState хранит состояние просмотра ответа вкладки запроса: поиск,
+// фильтр тела и дерево JSON со свернутыми узлами
+type bodyViewState struct {
+	search     string
+	matches    []searchMatch
+	matchIndex int
+
+	filter   string       // выражение JSONPath ($...) или jq (.…)
+	values   []*JSONValue // разобранное и отфильтрованное тело (nil, если не требуется или не JSON)
+	valueErr string       // причина, по которой фильтр или дерево не применены
+
+	tree      bool
+	folded    map[string]bool // свернутые узлы по ключу узла
+	cursor    int             // строка дерева под курсором
+	treeLines []jsonTreeLine  // строки дерева на момент последнего показа
+
+	kind    ContentKind // формат тела, определенный по Content-Type или содержимому
+	raw     bool        // тело показывается как получено, без форматирования и подсветки
+	rawText string      // тело как получено, подготовленное к выводу в терминал
+	note    string      // предупреждение о том, что тело показано не полностью или не как текст
+}
+
+// setBody определяет формат тела ответа и возвращает текст для показа.
+// Большие тела не форматируются, двоичные показываются дампом.
+func (v *bodyViewState) setBody(data ResponseData) string {
+	v.kind = DetectContentKind(HeaderValue(data.Headers, "Content-Type"), data.Body)
+	v.rawText, v.note = "", ""
+	if data.BodyFile != "" {
+		v.note = fmt.Sprintf("Тело больше лимита max_body: показаны первые %s из %s. s - сохранить полностью",
+			FormatSize(int64(len(data.Body))), FormatSize(data.DecodedSize))
+	}
+	v.refresh(data.Body)
+	if data.Binary {
+		v.kind = ContentBinary
+		if v.note == "" {
+			v.note = "Двоичные данные показаны шестнадцатеричным дампом. s - сохранить в файл"
+		}
+		return FormatHexDump(data.Body)
+	}
+	v.rawText = SanitizeText(data.Body)
+	if len(data.Body) > maxPrettyBodySize {
+		return v.rawText
+	}
+	return SanitizeText(PrettyBody(v.kind, data.Body))
+}
+
+// notices возвращает строки предупреждений над телом и пустую строку после них
+func (v *bodyViewState) notices() []string {
+	var lines []string
+	for _, notice := range []string{v.note, v.valueErr} {
+		if notice != "" {
+			lines = append(lines, "⚠ "+notice)
+		}
+	}
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// refresh разбирает тело ответа, если включен фильтр или дерево.
+// Свернутые узлы сохраняются между ответами: структура повторного ответа обычно та же.
+func (v *bodyViewState) refresh(body string) {
+	v.values, v.valueErr = nil, ""
+	v.cursor = 0
+	if v.filter == "" && !v.tree {
+		return
+	}
+	root, err := ParseJSON(body)
+	if err != nil {
+		v.valueErr = "тело ответа не является JSON"
+		return
+	}
+	if v.values, err = QueryJSON(root, v.filter); err != nil {
+		v.valueErr = "ошибка фильтра: " + err.Error()
+	}
+}
+
+// lines возвращает строки тела для показа и для поиска
+func (v *bodyViewState) lines(formatted string) []string {
+	lines := v.notices()
+	switch {
+	case v.values != nil && v.tree:
+		v.treeLines = buildJSONTree(v.values, v.folded)
+		v.cursor = min(v.cursor, max(len(v.treeLines)-1, 0))
+		for _, line := range v.treeLines {
+			lines = append(lines, line.text)
+		}
+		return lines
+	case v.values != nil && v.filter != "":
+		return append(lines, strings.Split(FormatJSONValues(v.values), "\n")...)
+	}
+	return append(lines, strings.Split(formatted, "\n")...)
+}
+
+// findMatches ищет запрос в строках. Поиск не учитывает регистр, если запрос
+// набран строчными буквами.
+func findMatches(lines []string, query string) []searchMatch {
+	if query == "" {
+		return nil
+	}
+	caseSensitive := strings.IndexFunc(query, unicode.IsUpper) >= 0
+	if !caseSensitive {
+		query = strings.ToLower(query)
+	}
+	var matches []searchMatch
+	for i, line := range lines {
+		if !caseSensitive {
+			lower := strings.ToLower(line)
+			if len(lower) != len(line) {
+				// Регистр изменил длину строки: границы вхождений не совпадут с исходной
+				continue
+			}
+			line = lower
+		}
+		for offset := 0; ; {
+			index := strings.Index(line[offset:], query)
+			if index < 0 {
+				break
+			}
+			start := offset + index
+			matches = append(matches, searchMatch{line: i, start: start, end: start + len(query)})
+			offset = start + len(query)
+		}
+	}
+	return matches
+}
+
+// highlightLines раскрашивает строки подсветкой синтаксиса и выделяет найденные
+// вхождения, текущее - отдельным цветом. Первые skip строк не раскрашиваются.
+func highlightLines(lines []string, kind ContentKind, skip int, matches []searchMatch, current int) []string {
+	ranges := make(map[int][]styledRange)
+	for i, m := range matches {
+		style := &searchMatchStyle
+		if i == current {
+			style = &currentMatchStyle
+		}
+		ranges[m.line] = append(ranges[m.line], styledRange{start: m.start, end: m.end, style: style})
+	}
+	h := &syntaxHighlighter{kind: kind}
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		spans := []styledSpan{{text: line}}
+		if i >= skip {
+			spans = h.spans(line)
+		}
+		result[i] = renderSpans(spans, ranges[i])
+	}
+	return result
+}
+
+// --- Вкладка "Ответ": поиск, фильтр и дерево ---
+
+// renderResponseContent показывает содержимое активной подвкладки, сохраняя прокрутку
+func (m *AppModel) renderResponseContent() {
+	lines := strings.Split(m.responseText(), "\n")
+	m.bodyView.matches = findMatches(lines, m.bodyView.search)
+	m.bodyView.matchIndex = min(m.bodyView.matchIndex, max(len(m.bodyView.matches)-1, 0))
+	kind, skip := ContentText, 0
+	if m.isBodyShown() {
+		skip = len(m.bodyView.notices())
+		if !m.isRawShown() && (len(m.responseData.Body) <= maxPrettyBodySize || m.bodyView.kind == ContentBinary) {
+			kind = m.GetResponseBodyKind()
+		}
+	}
+	lines = highlightLines(lines, kind, skip, m.bodyView.matches, m.bodyView.matchIndex)
+
+	// Предупреждения над телом: последнее из них - ошибка фильтра или дерева
+	for i := range max(skip-1, 0) {
+		style := bodyViewNoteStyle
+		if i == skip-2 && m.bodyView.valueErr != "" {
+			style = bodyViewErrorStyle
+		}
+		lines[i] = style.Render(lines[i])
+	}
+	if m.responseView == ResponseViewTests {
+		colorResultMarks(lines)
+	}
+	if m.isTreeShown() {
+		for i := range lines {
+			gutter := "  "
+			if i == m.bodyView.cursor+skip {
+				gutter = treeCursorStyle.Render("› ")
+			}
+			lines[i] = gutter + lines[i]
+		}
+	}
+	m.responseVP.SetContent(strings.Join(lines, "\n"))
+}
+
+// colorResultMarks выделяет цветом отметки ✓ и ✗ в начале строк результатов
+func colorResultMarks(lines []string) {
+	for i, line := range lines {
+		text := strings.TrimLeft(line, " ")
+		indent := line[:len(line)-len(text)]
+		if rest, ok := strings.CutPrefix(text, "✓"); ok {
+			lines[i] = indent + assertPassStyle.Render("✓") + rest
+		} else if rest, ok := strings.CutPrefix(text, "✗"); ok {
+			lines[i] = indent + assertFailStyle.Render("✗") + rest
+		}
+	}
+}
+
+// responseText возвращает текст активной подвкладки ответа
+func (m *AppModel) responseText() string {
+	switch {
+	case m.errorMsg != "":
+		return m.errorMsg
+	case m.status == "":
+		return ""
+	case m.responseView == ResponseViewHeaders:
+		return FormatHeaders(m.responseData.Headers)
+	case m.responseView == ResponseViewCookies:
+		return FormatCookies(m.responseData.Cookies)
+	case m.responseView == ResponseViewTests:
+		text := "Проверки не заданы. Добавьте их клавишей T на вкладке \"Запрос\"."
+		if len(m.responseData.Assertions) > 0 {
+			text = FormatAssertionResults(m.responseData.Assertions)
+		}
+		if len(m.responseData.Extracted) > 0 {
+			text = strings.TrimSuffix(text, "\n") + "\n\n" + FormatExtractionResults(m.responseData.Extracted)
+		}
+		return text
+	case m.responseView == ResponseViewTimings:
+		// Оставляем место для названия этапа и длительности
+		return FormatWaterfall(m.responseData.Timings, m.responseVP.Width-25)
+	case m.responseView == ResponseViewInfo:
+		return FormatResponseInfo(m.responseData)
+	case m.isRawShown():
+		return strings.Join(append(m.bodyView.notices(), m.bodyView.rawText), "\n")
+	}
+	return strings.Join(m.bodyView.lines(m.response), "\n")
+}
+
+// isBodyShown сообщает, показывается ли на вкладке "Ответ" тело ответа
+func (m *AppModel) isBodyShown() bool {
+	return m.responseView == ResponseViewBody && m.errorMsg == "" && m.status != ""
+}
+
+// isRawShown сообщает, показывается ли тело ответа как получено. Двоичное
+// тело всегда показывается дампом.
+func (m *AppModel) isRawShown() bool {
+	return m.bodyView.raw && m.bodyView.kind != ContentBinary
+}
+
+// isTreeShown сообщает, показывается ли тело ответа деревом
+func (m *AppModel) isTreeShown() bool {
+	return m.isBodyShown() && m.bodyView.tree && m.bodyView.values != nil && !m.isRawShown()
+}
+
+// scrollToLine прокручивает область ответа, чтобы строка была видна
+func (m *AppModel) scrollToLine(line int) {
+	if line < m.responseVP.YOffset || line >= m.responseVP.YOffset+m.responseVP.Height {
+		m.responseVP.SetYOffset(max(line-m.responseVP.Height/3, 0))
+	}
+}
+
+func (m *AppModel) GetResponseInput() *textinput.Model {
+	return &m.responseInput
+}
+
+func (m *AppModel) GetResponsePrompt() ResponsePrompt {
+	return m.responsePrompt
+}
+
+func (m *AppModel) SetResponsePrompt(prompt ResponsePrompt) {
+	m.responsePrompt = prompt
+}
+
+// SetSearch ищет строку в содержимом подвкладки ответа и переходит к первому
+// вхождению начиная с видимой части
+func (m *AppModel) SetSearch(query string) {
+	m.bodyView.search = query
+	m.bodyView.matchIndex = 0
+	m.renderResponseContent()
+	for i, match := range m.bodyView.matches {
+		if match.line >= m.responseVP.YOffset {
+			m.bodyView.matchIndex = i
+			break
+		}
+	}
+	m.showMatch()
+}
+
+// GetSearch возвращает строку поиска
+func (m *AppModel) GetSearch() string {
+	return m.bodyView.search
+}
+
+// NextMatch переходит к следующему (delta > 0) или предыдущему вхождению
+func (m *AppModel) NextMatch(delta int) bool {
+	count := len(m.bodyView.matches)
+	if count == 0 {
+		return false
+	}
+	m.bodyView.matchIndex = ((m.bodyView.matchIndex+delta)%count + count) % count
+	m.showMatch()
+	return true
+}
+
+// showMatch выделяет текущее вхождение и прокручивает к нему. В дереве курсор
+// переходит на строку вхождения.
+func (m *AppModel) showMatch() {
+	if len(m.bodyView.matches) == 0 {
+		m.renderResponseContent()
+		return
+	}
+	line := m.bodyView.matches[m.bodyView.matchIndex].line
+	if m.isTreeShown() {
+		m.bodyView.cursor = max(line-len(m.bodyView.notices()), 0)
+	}
+	m.renderResponseContent()
+	m.scrollToLine(line)
+}
+
+// SearchStatus возвращает номер текущего вхождения и количество вхождений
+func (m *AppModel) SearchStatus() (current, total int) {
+	if len(m.bodyView.matches) == 0 {
+		return 0, 0
+	}
+	return m.bodyView.matchIndex + 1, len(m.bodyView.matches)
+}
+
+// SetBodyFilter применяет к телу ответа выражение JSONPath или jq. Ошибка
+// в выражении возвращается, а фильтр не меняется.
+func (m *AppModel) SetBodyFilter(expr string) error {
+	expr = strings.TrimSpace(expr)
+	if expr != "" {
+		if _, err := QueryJSON(&JSONValue{}, expr); err != nil {
+			return err
+		}
+	}
+	m.bodyView.filter = expr
+	m.bodyView.raw = false
+	m.bodyView.refresh(m.responseData.Body)
+	m.updateResponseContent()
+	return nil
+}
+
+// GetBodyFilter возвращает выражение фильтра тела ответа
+func (m *AppModel) GetBodyFilter() string {
+	return m.bodyView.filter
+}
+
+// ToggleTreeView переключает показ тела ответа деревом JSON. Если тело
+// не удалось разобрать, дерево не включается и возвращается причина.
+func (m *AppModel) ToggleTreeView() error {
+	m.bodyView.tree = !m.bodyView.tree || m.bodyView.raw
+	m.bodyView.raw = false
+	m.bodyView.refresh(m.responseData.Body)
+	var err error
+	if m.bodyView.tree && m.bodyView.values == nil && m.status != "" {
+		err = errors.New(m.bodyView.valueErr)
+		m.bodyView.tree = false
+		m.bodyView.refresh(m.responseData.Body)
+	}
+	m.updateResponseContent()
+	return err
+}
+
+// IsTreeView сообщает, включен ли показ тела ответа деревом
+func (m *AppModel) IsTreeView() bool {
+	return m.isTreeShown()
+}
+
+// MoveTreeCursor перемещает курсор дерева на delta строк
+func (m *AppModel) MoveTreeCursor(delta int) {
+	if !m.isTreeShown() || len(m.bodyView.treeLines) == 0 {
+		return
+	}
+	m.bodyView.cursor = min(max(m.bodyView.cursor+delta, 0), len(m.bodyView.treeLines)-1)
+	m.renderResponseContent()
+	m.scrollToLine(m.bodyView.cursor + len(m.bodyView.notices()))
+}
+
+// ToggleFold сворачивает или разворачивает узел под курсором дерева
+func (m *AppModel) ToggleFold() {
+	if !m.isTreeShown() || m.bodyView.cursor >= len(m.bodyView.treeLines) {
+		return
+	}
+	line := m.bodyView.treeLines[m.bodyView.cursor]
+	if line.foldKey == "" {
+		return
+	}
+	if m.bodyView.folded == nil {
+		m.bodyView.folded = map[string]bool{}
+	}
+	if line.isFolded {
+		delete(m.bodyView.folded, line.foldKey)
+	} else {
+		m.bodyView.folded[line.foldKey] = true
+		// Курсор на закрывающей скобке переходит на свернутый узел
+		for i := m.bodyView.cursor; i >= 0; i-- {
+			if m.bodyView.treeLines[i].foldKey == line.foldKey {
+				m.bodyView.cursor = i
+			}
+		}
+	}
+	m.renderResponseContent()
+	m.scrollToLine(m.bodyView.cursor + len(m.bodyView.notices()))
+}
+
+// FoldAll сворачивает все вложенные узлы дерева (fold = true) или разворачивает все узлы
+func (m *AppModel) FoldAll(fold bool) {
+	if !m.isTreeShown() {
+		return
+	}
+	m.bodyView.folded = map[string]bool{}
+	if fold {
+		for _, line := range buildJSONTree(m.bodyView.values, nil) {
+			if line.foldKey != "" && line.depth > 0 {
+				m.bodyView.folded[line.foldKey] = true
+			}
+		}
+	}
+	m.bodyView.cursor = 0
+	m.updateResponseContent()
+}
+
+// GetTreePath возвращает путь jq узла под курсором дерева
+func (m *AppModel) GetTreePath() string {
+	if !m.isTreeShown() || m.bodyView.cursor >= len(m.bodyView.treeLines) {
+		return ""
+	}
+	return m.bodyView.treeLines[m.bodyView.cursor].path
+}
+
+// ToggleRawView переключает показ тела ответа как получено и в отформатированном
+// виде с подсветкой
+func (m *AppModel) ToggleRawView() {
+	m.bodyView.raw = !m.bodyView.raw
+	m.updateResponseContent()
+}
+
+// IsRawView сообщает, показывается ли тело ответа без форматирования
+func (m *AppModel) IsRawView() bool {
+	return m.isRawShown()
+}
+
+// GetResponseBodyKind возвращает формат показываемого тела ответа. Результат
+// фильтра и дерево всегда показываются как JSON.
+func (m *AppModel) GetResponseBodyKind() ContentKind {
+	if m.bodyView.values != nil {
+		return ContentJSON
+	}
+	return m.bodyView.kind
+}
+
+// SuggestBodyFileName предлагает имя файла для сохранения тела ответа
+func (m *AppModel) SuggestBodyFileName() string {
+	return ResponseFileName(m.responseData)
+}
+
+// SaveResponseBody сохраняет тело ответа активной вкладки в новый файл
+// и возвращает количество записанных байт
+func (m *AppModel) SaveResponseBody(filename string) (int64, error) {
+	if m.status == "" || m.errorMsg != "" {
+		return 0, errors.New("нет ответа")
+	}
+	return WriteResponseBody(m.responseData, filename)
+}
+
+// RemoveBodyFiles удаляет временные файлы тел ответов всех вкладок
+func (m *AppModel) RemoveBodyFiles() {
+	for _, s := range m.sessions {
+		RemoveBodyFile(s.responseData)
+	}
+}
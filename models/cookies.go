@@ -0,0 +1,356 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// cookieTimeFormat формат срока действия cookie, как в заголовке Set-Cookie
+const cookieTimeFormat = "Mon, 02 Jan 2006 15:04:05 GMT"
+
+// StoredCookie представляет cookie в хранилище окружения
+type StoredCookie struct {
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain"`
+	Path     string    `json:"path"`
+	Expires  time.Time `json:"expires,omitzero"` // нулевое значение для cookie сеанса
+	Secure   bool      `json:"secure,omitempty"`
+	HTTPOnly bool      `json:"httpOnly,omitempty"`
+	HostOnly bool      `json:"hostOnly,omitempty"` // отправляется только на Domain, без поддоменов
+	SameSite string    `json:"sameSite,omitempty"`
+}
+
+// Implement list.Item interface for StoredCookie
+func (c StoredCookie) Title() string { return c.Name + "=" + c.Value }
+func (c StoredCookie) Description() string {
+	parts := []string{c.Domain + c.Path}
+	if c.Expires.IsZero() {
+		parts = append(parts, "сеанс")
+	} else {
+		parts = append(parts, "до "+c.Expires.Local().Format("2006-01-02 15:04"))
+	}
+	if c.Secure {
+		parts = append(parts, "Secure")
+	}
+	if c.HTTPOnly {
+		parts = append(parts, "HttpOnly")
+	}
+	return strings.Join(parts, " | ")
+}
+func (c StoredCookie) FilterValue() string { return c.Domain + " " + c.Name }
+
+// Expired сообщает, истек ли срок действия cookie
+func (c StoredCookie) Expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+// sameCookie сообщает, описывают ли значения одну cookie (RFC 6265, раздел 5.3)
+func (c StoredCookie) sameCookie(other StoredCookie) bool {
+	return c.Name == other.Name && strings.EqualFold(c.Domain, other.Domain) && c.Path == other.Path
+}
+
+// FormatCookieLine записывает cookie в форме заголовка Set-Cookie для редактирования
+func FormatCookieLine(c StoredCookie) string {
+	parts := []string{c.Name + "=" + c.Value, "Domain=" + c.Domain, "Path=" + c.Path}
+	if !c.Expires.IsZero() {
+		parts = append(parts, "Expires="+c.Expires.UTC().Format(cookieTimeFormat))
+	}
+	if c.SameSite != "" {
+		parts = append(parts, "SameSite="+c.SameSite)
+	}
+	if c.Secure {
+		parts = append(parts, "Secure")
+	}
+	if c.HTTPOnly {
+		parts = append(parts, "HttpOnly")
+	}
+	if c.HostOnly {
+		parts = append(parts, "HostOnly")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ParseCookieLine разбирает cookie в форме заголовка Set-Cookie:
+// "name=value; Domain=example.com; Path=/; Expires=...; Max-Age=3600; Secure; HttpOnly"
+func ParseCookieLine(line string) (StoredCookie, error) {
+	parts := strings.Split(line, ";")
+	name, value, ok := strings.Cut(parts[0], "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return StoredCookie{}, fmt.Errorf("ожидается name=value")
+	}
+	c := StoredCookie{Name: name, Value: strings.TrimSpace(value), Path: "/"}
+
+	for _, attr := range parts[1:] {
+		key, value, _ := strings.Cut(attr, "=")
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "":
+		case "domain":
+			c.Domain = strings.ToLower(strings.TrimPrefix(value, "."))
+		case "path":
+			c.Path = value
+		case "expires":
+			expires, err := time.Parse(cookieTimeFormat, value)
+			if err != nil {
+				if expires, err = time.ParseInLocation("2006-01-02 15:04", value, time.Local); err != nil {
+					return StoredCookie{}, fmt.Errorf("неверный срок действия %q", value)
+				}
+			}
+			c.Expires = expires
+		case "max-age":
+			var seconds int
+			if _, err := fmt.Sscan(value, &seconds); err != nil {
+				return StoredCookie{}, fmt.Errorf("неверный Max-Age %q", value)
+			}
+			c.Expires = time.Now().Add(time.Duration(seconds) * time.Second)
+		case "samesite":
+			c.SameSite = value
+		case "secure":
+			c.Secure = true
+		case "httponly":
+			c.HTTPOnly = true
+		case "hostonly":
+			c.HostOnly = true
+		default:
+			return StoredCookie{}, fmt.Errorf("неизвестный атрибут %q", key)
+		}
+	}
+	if c.Domain == "" {
+		return StoredCookie{}, fmt.Errorf("не указан Domain")
+	}
+	if !strings.HasPrefix(c.Path, "/") {
+		return StoredCookie{}, fmt.Errorf("Path должен начинаться с /")
+	}
+	return c, nil
+}
+
+// CookieStore хранит cookies окружений (ключ "" - запросы без окружения).
+// Cookies сохраняются ответами, которые обрабатываются параллельно,
+// поэтому доступ к хранилищу защищен мьютексом.
+type CookieStore struct {
+	mu      sync.Mutex
+	jars    map[string][]StoredCookie
+	loadErr error // ошибка чтения cookies.json: хранилище не сохраняется
+}
+
+// CookieJar предоставляет доступ к cookies одного окружения
+type CookieJar struct {
+	store *CookieStore
+	env   string
+}
+
+func getCookiesPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "cookies.json"), nil
+}
+
+// LoadCookieStore загружает cookies всех окружений. При ошибке чтения вместе с ней
+// возвращается пустое хранилище, чтобы запросы могли выполняться. Такое хранилище
+// не сохраняется, чтобы не перезаписать файл, который не удалось прочитать.
+func LoadCookieStore() (*CookieStore, error) {
+	store := &CookieStore{jars: map[string][]StoredCookie{}}
+	err := store.load()
+	if err != nil {
+		store.jars = map[string][]StoredCookie{}
+		store.loadErr = fmt.Errorf("cookies не загружены: %w", err)
+	}
+	return store, err
+}
+
+func (s *CookieStore) load() error {
+	path, err := getCookiesPath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, &s.jars); err != nil {
+		return err
+	}
+	if s.jars == nil {
+		s.jars = map[string][]StoredCookie{}
+	}
+	return nil
+}
+
+// save записывает хранилище в файл, удаляя cookies с истекшим сроком. Вызывается под мьютексом.
+func (s *CookieStore) save() error {
+	if s.loadErr != nil {
+		return s.loadErr
+	}
+	now := time.Now()
+	for env, cookies := range s.jars {
+		cookies = filterCookies(cookies, func(c StoredCookie) bool { return !c.Expired(now) })
+		if len(cookies) == 0 {
+			delete(s.jars, env)
+		} else {
+			s.jars[env] = cookies
+		}
+	}
+	path, err := getCookiesPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.jars, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Jar возвращает cookies указанного окружения
+func (s *CookieStore) Jar(env string) *CookieJar {
+	return &CookieJar{store: s, env: env}
+}
+
+// Env возвращает имя окружения, к которому относятся cookies
+func (j *CookieJar) Env() string {
+	return j.env
+}
+
+// All возвращает действующие cookies, отсортированные по домену, пути и имени
+func (j *CookieJar) All() []StoredCookie {
+	j.store.mu.Lock()
+	defer j.store.mu.Unlock()
+	now := time.Now()
+	cookies := filterCookies(j.store.jars[j.env], func(c StoredCookie) bool { return !c.Expired(now) })
+	sort.SliceStable(cookies, func(a, b int) bool {
+		if cookies[a].Domain != cookies[b].Domain {
+			return cookies[a].Domain < cookies[b].Domain
+		}
+		if cookies[a].Path != cookies[b].Path {
+			return cookies[a].Path < cookies[b].Path
+		}
+		return cookies[a].Name < cookies[b].Name
+	})
+	return cookies
+}
+
+// Set добавляет cookies, заменяя совпадающие по имени, домену и пути,
+// и сохраняет хранилище. Cookie с истекшим сроком удаляет совпадающую.
+func (j *CookieJar) Set(cookies ...StoredCookie) error {
+	j.store.mu.Lock()
+	defer j.store.mu.Unlock()
+	for _, c := range cookies {
+		jar := filterCookies(j.store.jars[j.env], func(existing StoredCookie) bool { return !existing.sameCookie(c) })
+		if !c.Expired(time.Now()) {
+			jar = append(jar, c)
+		}
+		j.store.jars[j.env] = jar
+	}
+	return j.store.save()
+}
+
+// Replace заменяет cookie old (например, после редактирования) на c
+func (j *CookieJar) Replace(old, c StoredCookie) error {
+	j.store.mu.Lock()
+	j.store.jars[j.env] = filterCookies(j.store.jars[j.env], func(existing StoredCookie) bool { return !existing.sameCookie(old) })
+	j.store.mu.Unlock()
+	return j.Set(c)
+}
+
+// Delete удаляет cookie и сохраняет хранилище
+func (j *CookieJar) Delete(c StoredCookie) error {
+	return j.deleteWhere(func(existing StoredCookie) bool { return existing.sameCookie(c) })
+}
+
+// DeleteDomain удаляет все cookies домена и возвращает их количество
+func (j *CookieJar) DeleteDomain(domain string) (int, error) {
+	before := len(j.All())
+	err := j.deleteWhere(func(c StoredCookie) bool { return strings.EqualFold(c.Domain, domain) })
+	return before - len(j.All()), err
+}
+
+func (j *CookieJar) deleteWhere(match func(StoredCookie) bool) error {
+	j.store.mu.Lock()
+	defer j.store.mu.Unlock()
+	j.store.jars[j.env] = filterCookies(j.store.jars[j.env], func(c StoredCookie) bool { return !match(c) })
+	return j.store.save()
+}
+
+// filterCookies возвращает новый срез с cookies, для которых keep возвращает true
+func filterCookies(cookies []StoredCookie, keep func(StoredCookie) bool) []StoredCookie {
+	var result []StoredCookie
+	for _, c := range cookies {
+		if keep(c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// --- Вкладка "Cookies" ---
+
+// CookieJar возвращает cookies активного окружения
+func (m *AppModel) CookieJar() *CookieJar {
+	return m.cookies.Jar(m.environments.Active)
+}
+
+// refreshCookieList показывает в списке cookies активного окружения
+func (m *AppModel) refreshCookieList() {
+	cookies := m.CookieJar().All()
+	items := make([]list.Item, len(cookies))
+	for i, c := range cookies {
+		items[i] = c
+	}
+	m.cookieList.SetItems(items)
+	m.cookieList.Title = "Cookies"
+	if env := m.environments.Active; env != "" {
+		m.cookieList.Title += " окружения " + env
+	}
+}
+
+func (m *AppModel) GetCookieList() *list.Model {
+	return &m.cookieList
+}
+
+// GetSelectedCookie возвращает выбранную на вкладке "Cookies" cookie
+func (m *AppModel) GetSelectedCookie() (StoredCookie, bool) {
+	c, ok := m.cookieList.SelectedItem().(StoredCookie)
+	return c, ok
+}
+
+// SaveCookie сохраняет новую или измененную cookie (old - исходная cookie при редактировании)
+func (m *AppModel) SaveCookie(old *StoredCookie, c StoredCookie) error {
+	jar := m.CookieJar()
+	var err error
+	if old != nil {
+		err = jar.Replace(*old, c)
+	} else {
+		err = jar.Set(c)
+	}
+	m.refreshCookieList()
+	return err
+}
+
+// DeleteSelectedCookie удаляет выбранную cookie, а при allDomain - все cookies ее домена.
+// Возвращает количество удаленных cookies.
+func (m *AppModel) DeleteSelectedCookie(allDomain bool) (int, error) {
+	c, ok := m.GetSelectedCookie()
+	if !ok {
+		return 0, nil
+	}
+	defer m.refreshCookieList()
+	if allDomain {
+		return m.CookieJar().DeleteDomain(c.Domain)
+	}
+	return 1, m.CookieJar().Delete(c)
+}
@@ -0,0 +1,216 @@
+package models
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Variable представляет переменную окружения
+type Variable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Environment представляет именованный набор переменных
+type Environment struct {
+	Name      string     `json:"name"`
+	Variables []Variable `json:"variables"`
+}
+
+// EnvironmentSet определяет структуру для сохранения окружений в JSON
+type EnvironmentSet struct {
+	Active       string        `json:"active"`
+	Environments []Environment `json:"environments"`
+}
+
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// ExpandVariables подставляет значения переменных вместо плейсхолдеров {{name}}.
+// Неизвестные переменные остаются без изменений.
+func ExpandVariables(input string, vars map[string]string) string {
+	if len(vars) == 0 || input == "" {
+		return input
+	}
+	return variablePattern.ReplaceAllStringFunc(input, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// ExpandURLVariables подставляет значения переменных в URL. Значения в пути и строке
+// запроса кодируются, чтобы пробелы и символы ?, &, # не меняли структуру URL.
+// Переменные до начала пути (схема, хост, базовый адрес вида {{host}}) подставляются как есть.
+func ExpandURLVariables(rawURL string, vars map[string]string) string {
+	if len(vars) == 0 || rawURL == "" {
+		return rawURL
+	}
+	query, fragment := strings.Index(rawURL, "?"), strings.Index(rawURL, "#")
+	if fragment >= 0 && query > fragment {
+		query = -1
+	}
+	pathStart := 0
+	if idx := strings.Index(rawURL, "://"); idx >= 0 && (query < 0 || idx < query) {
+		pathStart = idx + len("://")
+	}
+	if idx := strings.Index(rawURL[pathStart:], "/"); idx >= 0 {
+		pathStart += idx
+	} else {
+		pathStart = len(rawURL)
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, loc := range variablePattern.FindAllStringSubmatchIndex(rawURL, -1) {
+		sb.WriteString(rawURL[last:loc[0]])
+		last = loc[1]
+		value, ok := vars[rawURL[loc[2]:loc[3]]]
+		switch {
+		case !ok:
+			sb.WriteString(rawURL[loc[0]:loc[1]])
+		case fragment >= 0 && loc[0] > fragment:
+			sb.WriteString(value)
+		case query >= 0 && loc[0] > query:
+			sb.WriteString(url.QueryEscape(value))
+		case loc[0] >= pathStart:
+			// Значение может содержать несколько сегментов пути
+			sb.WriteString(strings.ReplaceAll(url.PathEscape(value), "%2F", "/"))
+		default:
+			sb.WriteString(value)
+		}
+	}
+	sb.WriteString(rawURL[last:])
+	return sb.String()
+}
+
+// MergeVariables объединяет переменные коллекции с переменными окружения.
+// Переменные окружения имеют приоритет и могут использоваться в значениях переменных коллекции.
+func MergeVariables(collection []Variable, env map[string]string) map[string]string {
+	vars := make(map[string]string, len(collection)+len(env))
+	for _, v := range collection {
+		vars[v.Key] = v.Value
+	}
+	for k, v := range env {
+		vars[k] = v
+	}
+	for _, v := range collection {
+		if _, overridden := env[v.Key]; !overridden {
+			vars[v.Key] = ExpandVariables(v.Value, vars)
+		}
+	}
+	return vars
+}
+
+// Find возвращает окружение по имени
+func (s *EnvironmentSet) Find(name string) *Environment {
+	for i := range s.Environments {
+		if s.Environments[i].Name == name {
+			return &s.Environments[i]
+		}
+	}
+	return nil
+}
+
+// MergeVariables добавляет переменные в окружение (создавая его при необходимости),
+// перезаписывая значения существующих ключей
+func (s *EnvironmentSet) MergeVariables(name string, vars []Variable) {
+	env := s.Find(name)
+	if env == nil {
+		s.Environments = append(s.Environments, Environment{Name: name})
+		env = &s.Environments[len(s.Environments)-1]
+	}
+	for _, v := range vars {
+		replaced := false
+		for i := range env.Variables {
+			if env.Variables[i].Key == v.Key {
+				env.Variables[i].Value = v.Value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			env.Variables = append(env.Variables, v)
+		}
+	}
+}
+
+// ActiveVariables возвращает переменные активного окружения
+func (s *EnvironmentSet) ActiveVariables() map[string]string {
+	vars := map[string]string{}
+	if env := s.Find(s.Active); env != nil {
+		for _, v := range env.Variables {
+			vars[v.Key] = v.Value
+		}
+	}
+	return vars
+}
+
+// Next переключает активное окружение на следующее (после последнего - без окружения)
+func (s *EnvironmentSet) Next() {
+	if len(s.Environments) == 0 {
+		s.Active = ""
+		return
+	}
+	if s.Active == "" {
+		s.Active = s.Environments[0].Name
+		return
+	}
+	for i, env := range s.Environments {
+		if env.Name == s.Active {
+			if i+1 < len(s.Environments) {
+				s.Active = s.Environments[i+1].Name
+			} else {
+				s.Active = ""
+			}
+			return
+		}
+	}
+	s.Active = ""
+}
+
+// --- Логика Сохранения/Загрузки ---
+
+func getEnvironmentsPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "environments.json"), nil
+}
+
+// LoadEnvironments загружает окружения из файла рядом с requests.json
+func LoadEnvironments() (EnvironmentSet, error) {
+	var set EnvironmentSet
+	path, err := getEnvironmentsPath()
+	if err != nil {
+		return set, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return set, nil
+		}
+		return set, err
+	}
+	err = json.Unmarshal(data, &set)
+	return set, err
+}
+
+// SaveEnvironments сохраняет окружения в файл
+func SaveEnvironments(set EnvironmentSet) error {
+	path, err := getEnvironmentsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
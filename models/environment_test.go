@@ -0,0 +1,66 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandURLVariables(t *testing.T) {
+	vars := map[string]string{
+		"host":    "api.example.com:8080",
+		"base":    "https://api.example.com/v1",
+		"scheme":  "https",
+		"id":      "42",
+		"name":    "a b/c?d#e",
+		"query":   "x&y=z #1",
+		"encoded": "a%20b",
+		"segment": "users/me",
+		"empty":   "",
+	}
+	for _, tc := range []struct {
+		url, want string
+	}{
+		// До начала пути значения подставляются как есть
+		{"https://{{host}}/users/{{id}}", "https://api.example.com:8080/users/42"},
+		{"{{base}}/users/{{id}}", "https://api.example.com/v1/users/42"},
+		{"{{scheme}}://{{host}}", "https://api.example.com:8080"},
+		{"{{host}}?q={{query}}", "api.example.com:8080?q=x%26y%3Dz+%231"},
+		// В пути значение кодируется, слеш разделяет сегменты
+		{"https://x/{{name}}", "https://x/a%20b/c%3Fd%23e"},
+		{"https://x/{{segment}}/{{id}}", "https://x/users/me/42"},
+		// В строке запроса значение кодируется целиком
+		{"https://x/search?q={{name}}&id={{id}}", "https://x/search?q=a+b%2Fc%3Fd%23e&id=42"},
+		{"https://x/?{{query}}", "https://x/?x%26y%3Dz+%231"},
+		// Во фрагменте значение подставляется как есть, ? во фрагменте не начинает строку запроса
+		{"https://x/page#{{name}}", "https://x/page#a b/c?d#e"},
+		{"https://x/page#a?b={{id}}", "https://x/page#a?b=42"},
+		// Закодированный текст адреса не меняется, закодированное значение кодируется еще раз
+		{"https://x/a%20b/{{id}}?q=%26{{id}}", "https://x/a%20b/42?q=%2642"},
+		{"https://x/{{encoded}}?v={{encoded}}", "https://x/a%2520b?v=a%2520b"},
+		// Неизвестные переменные и пустые значения
+		{"https://x/{{missing}}?q={{ missing }}", "https://x/{{missing}}?q={{ missing }}"},
+		{"https://x/{{ id }}/{{empty}}", "https://x/42/"},
+		{"", ""},
+	} {
+		if got := ExpandURLVariables(tc.url, vars); got != tc.want {
+			t.Errorf("%s = %s, ожидалось %s", tc.url, got, tc.want)
+		}
+	}
+
+	if got := ExpandURLVariables("https://x/{{id}}", nil); got != "https://x/{{id}}" {
+		t.Errorf("без переменных = %s", got)
+	}
+}
+
+func TestMergeVariables(t *testing.T) {
+	collection := []Variable{
+		{Key: "host", Value: "localhost"},
+		{Key: "base", Value: "https://{{host}}/api"},
+		{Key: "token", Value: "collection"},
+	}
+	got := MergeVariables(collection, map[string]string{"host": "prod.example.com", "token": "env"})
+	want := map[string]string{"host": "prod.example.com", "base": "https://prod.example.com/api", "token": "env"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("переменные = %v, ожидалось %v", got, want)
+	}
+}
@@ -0,0 +1,243 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ExtractSource определяет, откуда берется значение переменной
+type ExtractSource string
+
+const (
+	ExtractJSON   ExtractSource = "json"   // значение по выражению JSONPath или jq
+	ExtractHeader ExtractSource = "header" // значение заголовка ответа
+	ExtractCookie ExtractSource = "cookie" // значение cookie, установленной ответом
+	ExtractBody   ExtractSource = "body"   // первая группа регулярного выражения или все тело
+	ExtractStatus ExtractSource = "status" // код ответа
+)
+
+// Extraction описывает правило, по которому значение из ответа записывается
+// в переменную. В строковой записи: token = json $.access_token,
+// location = header Location, code = body "code=(\d+)", result = status.
+type Extraction struct {
+	Variable   string        `json:"variable"`
+	Source     ExtractSource `json:"source"`
+	Expression string        `json:"expression,omitempty"` // выражение, имя заголовка или cookie, регулярное выражение
+}
+
+// ExtractionResult содержит значение, извлеченное из ответа
+type ExtractionResult struct {
+	Variable string `json:"variable"`
+	Value    string `json:"value,omitempty"`
+	Error    string `json:"error,omitempty"` // значение не найдено, переменная не изменена
+}
+
+// ExtractionPlaceholder содержит пример записи правил для поля ввода
+const ExtractionPlaceholder = "token = json $.access_token; id = header Location; code = body \"code=(\\d+)\""
+
+// variableName соответствует допустимому имени переменной {{name}}
+var variableName = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// ParseExtraction разбирает правило из строки "переменная = источник [выражение]"
+func ParseExtraction(line string) (Extraction, error) {
+	line = strings.TrimSpace(line)
+	name, rest, ok := strings.Cut(line, "=")
+	e := Extraction{Variable: strings.TrimSpace(name)}
+	if !ok || e.Variable == "" {
+		return e, fmt.Errorf("неверное правило %q, ожидается переменная = источник [выражение]", line)
+	}
+	if !variableName.MatchString(e.Variable) {
+		return e, fmt.Errorf("%s: недопустимое имя переменной %q", line, e.Variable)
+	}
+
+	source, expression := cutWord(rest)
+	e.Source = ExtractSource(strings.ToLower(source))
+	e.Expression = expression
+	switch e.Source {
+	case ExtractJSON:
+		if e.Expression == "" {
+			return e, fmt.Errorf("%s: не указано выражение JSONPath или jq", line)
+		}
+		if !strings.Contains(e.Expression, "{{") {
+			if _, err := QueryJSON(&JSONValue{Kind: JSONNull}, e.Expression); err != nil {
+				return e, fmt.Errorf("%s: %v", line, err)
+			}
+		}
+	case ExtractHeader, ExtractCookie:
+		if e.Expression == "" {
+			return e, fmt.Errorf("%s: не указано имя", line)
+		}
+	case ExtractBody:
+		e.Expression = unquoteValue(e.Expression)
+		if _, err := regexp.Compile(e.Expression); err != nil {
+			return e, fmt.Errorf("%s: неверное регулярное выражение: %v", line, err)
+		}
+	case ExtractStatus:
+		if e.Expression != "" {
+			return e, fmt.Errorf("%s: у источника status нет выражения", line)
+		}
+	default:
+		return e, fmt.Errorf("%s: неизвестный источник %q, ожидается json, header, cookie, body или status", line, source)
+	}
+	return e, nil
+}
+
+// String возвращает запись правила, из которой его можно снова разобрать
+func (e Extraction) String() string {
+	s := e.Variable + " = " + string(e.Source)
+	switch {
+	case e.Source == ExtractBody && e.Expression != "":
+		s += " " + quoteValue(e.Expression)
+	case e.Expression != "":
+		s += " " + e.Expression
+	}
+	return s
+}
+
+// Expand возвращает правило с подставленными значениями переменных
+func (e Extraction) Expand(vars map[string]string) Extraction {
+	e.Expression = ExpandVariables(e.Expression, vars)
+	return e
+}
+
+// FormatExtractions записывает правила в строку "правило; правило2"
+func FormatExtractions(extractions []Extraction) string {
+	parts := make([]string, len(extractions))
+	for i, e := range extractions {
+		parts[i] = e.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ParseExtractions разбирает правила, разделенные точкой с запятой
+func ParseExtractions(input string) ([]Extraction, error) {
+	var extractions []Extraction
+	for _, part := range splitAssertions(input) {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		e, err := ParseExtraction(part)
+		if err != nil {
+			return nil, err
+		}
+		extractions = append(extractions, e)
+	}
+	return extractions, nil
+}
+
+// ExtractVariables извлекает значения переменных из ответа
+func ExtractVariables(extractions []Extraction, data ResponseData) []ExtractionResult {
+	if len(extractions) == 0 {
+		return nil
+	}
+	var root *JSONValue
+	var parseErr error
+	results := make([]ExtractionResult, len(extractions))
+	for i, e := range extractions {
+		result := ExtractionResult{Variable: e.Variable}
+		var err error
+		switch e.Source {
+		case ExtractJSON:
+			if root == nil && parseErr == nil {
+				if root, parseErr = ParseJSON(data.Body); parseErr != nil {
+					parseErr = fmt.Errorf("тело ответа не является JSON: %v", parseErr)
+				}
+			}
+			if err = parseErr; err == nil {
+				result.Value, err = extractJSON(root, e.Expression)
+			}
+		case ExtractHeader:
+			if result.Value = HeaderValue(data.Headers, e.Expression); result.Value == "" {
+				err = fmt.Errorf("заголовка %s нет", e.Expression)
+			}
+		case ExtractCookie:
+			err = fmt.Errorf("ответ не устанавливает cookie %s", e.Expression)
+			for _, c := range data.Cookies {
+				if c.Name == e.Expression {
+					result.Value, err = c.Value, nil
+				}
+			}
+		case ExtractBody:
+			result.Value, err = extractText(data.Body, e.Expression)
+		case ExtractStatus:
+			result.Value = strconv.Itoa(data.StatusCode)
+		default:
+			err = fmt.Errorf("неизвестный источник %q", e.Source)
+		}
+		if err != nil {
+			result.Value, result.Error = "", err.Error()
+		}
+		results[i] = result
+	}
+	return results
+}
+
+// ExtractedVariables возвращает успешно извлеченные значения
+func ExtractedVariables(results []ExtractionResult) []Variable {
+	var vars []Variable
+	for _, r := range results {
+		if r.Error == "" {
+			vars = append(vars, Variable{Key: r.Variable, Value: r.Value})
+		}
+	}
+	return vars
+}
+
+// FormatExtractionResults форматирует извлеченные значения по одному на строке
+func FormatExtractionResults(results []ExtractionResult) string {
+	var sb strings.Builder
+	sb.WriteString("Переменные из ответа:\n")
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Fprintf(&sb, "✗ %s: %s\n", r.Variable, r.Error)
+			continue
+		}
+		value := r.Value
+		if runes := []rune(value); len(runes) > 120 {
+			value = string(runes[:119]) + "…"
+		}
+		fmt.Fprintf(&sb, "✓ %s = %s\n", r.Variable, value)
+	}
+	return sb.String()
+}
+
+// extractJSON возвращает первое найденное значение: строку без кавычек,
+// остальные значения - записью JSON
+func extractJSON(root *JSONValue, expr string) (string, error) {
+	found, err := QueryJSON(root, expr)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(strings.TrimSpace(expr), "$") {
+		found = found[0].Items
+	}
+	if len(found) == 0 || found[0].Kind == JSONNull {
+		return "", fmt.Errorf("значение не найдено")
+	}
+	if found[0].Kind == JSONString {
+		return found[0].Scalar, nil
+	}
+	return found[0].Compact(), nil
+}
+
+// extractText возвращает первую группу регулярного выражения, а если групп
+// нет - все совпадение. Пустое выражение возвращает все тело.
+func extractText(body, expr string) (string, error) {
+	if expr == "" {
+		return body, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return "", fmt.Errorf("неверное регулярное выражение: %v", err)
+	}
+	match := re.FindStringSubmatch(body)
+	switch {
+	case match == nil:
+		return "", fmt.Errorf("совпадений нет")
+	case len(match) > 1:
+		return match[1], nil
+	}
+	return match[0], nil
+}
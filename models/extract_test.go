@@ -0,0 +1,122 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseExtraction(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		want Extraction
+	}{
+		{"token = json $.access_token", Extraction{Variable: "token", Source: ExtractJSON, Expression: "$.access_token"}},
+		{"ids=json .items[] | select(.active) | .id", Extraction{Variable: "ids", Source: ExtractJSON, Expression: ".items[] | select(.active) | .id"}},
+		{"loc = HEADER Location", Extraction{Variable: "loc", Source: ExtractHeader, Expression: "Location"}},
+		{"sid = cookie session_id", Extraction{Variable: "sid", Source: ExtractCookie, Expression: "session_id"}},
+		{`code = body "code=(\d+); "`, Extraction{Variable: "code", Source: ExtractBody, Expression: `code=(\d+); `}},
+		{"all = body", Extraction{Variable: "all", Source: ExtractBody}},
+		{"user.id-2 = status", Extraction{Variable: "user.id-2", Source: ExtractStatus}},
+		{"v = json $.{{field}}", Extraction{Variable: "v", Source: ExtractJSON, Expression: "$.{{field}}"}},
+	} {
+		got, err := ParseExtraction(tc.line)
+		if err != nil {
+			t.Errorf("%s: ошибка %v", tc.line, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: разобрано %+v, ожидалось %+v", tc.line, got, tc.want)
+		}
+		if again, err := ParseExtraction(got.String()); err != nil || again != got {
+			t.Errorf("%s: повторный разбор %q = %+v, %v", tc.line, got.String(), again, err)
+		}
+	}
+}
+
+func TestParseExtractionErrors(t *testing.T) {
+	for line, want := range map[string]string{
+		"token json $.a":      "ожидается переменная = источник",
+		"= json $.a":          "ожидается переменная = источник",
+		"my var = status":     `недопустимое имя переменной "my var"`,
+		"{{x}} = status":      "недопустимое имя переменной",
+		"t = json":            "не указано выражение",
+		"t = json $.a[":       "ожидается индекс",
+		"t = header":          "не указано имя",
+		"t = cookie":          "не указано имя",
+		"t = body (":          "неверное регулярное выражение",
+		"t = status 200":      "у источника status нет выражения",
+		"t = query page":      `неизвестный источник "query"`,
+		"t = json .a | nope":  "неизвестное выражение",
+		"t = json $.a[?(@.a)": `ожидается "]"`,
+	} {
+		_, err := ParseExtraction(line)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: ошибка %v, ожидалось %q", line, err, want)
+		}
+	}
+}
+
+func TestExtractVariables(t *testing.T) {
+	data := ResponseData{
+		StatusCode: 201,
+		Headers:    []Header{{Key: "Location", Value: "/items/42"}},
+		Cookies:    []Cookie{{Name: "session", Value: "abc"}, {Name: "session", Value: "def"}},
+		Body:       `{"token": "t-1", "id": 42, "items": [{"id": 1}, {"id": 2}], "user": {"name": "Ann"}, "none": null, "text": "code=17 code=18"}`,
+	}
+	extractions, err := ParseExtractions(`token = json $.access_token; id = json $.id; token2 = json .token; ` +
+		`last = json $.items[-1].id; third = json $.items[2].id; user = json $.user; none = json $.none; ` +
+		`loc = header location; missing = header X-Id; sid = cookie session; other = cookie other; ` +
+		`code = body "code=(\d+)"; word = body co.e; nope = body "x=(\d+)"; status = status`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := ExtractVariables(extractions, data)
+	want := []ExtractionResult{
+		{Variable: "token", Error: "значение не найдено"},
+		{Variable: "id", Value: "42"},
+		{Variable: "token2", Value: "t-1"},
+		{Variable: "last", Value: "2"},
+		{Variable: "third", Error: "значение не найдено"},
+		{Variable: "user", Value: `{"name":"Ann"}`},
+		{Variable: "none", Error: "значение не найдено"},
+		{Variable: "loc", Value: "/items/42"},
+		{Variable: "missing", Error: "заголовка X-Id нет"},
+		// Из нескольких cookies с одним именем берется последняя
+		{Variable: "sid", Value: "def"},
+		{Variable: "other", Error: "ответ не устанавливает cookie other"},
+		{Variable: "code", Value: "17"},
+		{Variable: "word", Value: "code"},
+		{Variable: "nope", Error: "совпадений нет"},
+		{Variable: "status", Value: "201"},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("результаты:\n%+v\nожидалось:\n%+v", results, want)
+	}
+
+	wantVars := []Variable{
+		{Key: "id", Value: "42"}, {Key: "token2", Value: "t-1"}, {Key: "last", Value: "2"},
+		{Key: "user", Value: `{"name":"Ann"}`}, {Key: "loc", Value: "/items/42"}, {Key: "sid", Value: "def"},
+		{Key: "code", Value: "17"}, {Key: "word", Value: "code"}, {Key: "status", Value: "201"},
+	}
+	if vars := ExtractedVariables(results); !reflect.DeepEqual(vars, wantVars) {
+		t.Errorf("переменные = %+v, ожидалось %+v", vars, wantVars)
+	}
+}
+
+func TestExtractVariablesNotJSON(t *testing.T) {
+	extractions := []Extraction{
+		{Variable: "a", Source: ExtractJSON, Expression: "$.a"},
+		{Variable: "b", Source: ExtractJSON, Expression: ".b"},
+		{Variable: "all", Source: ExtractBody},
+	}
+	results := ExtractVariables(extractions, ResponseData{Body: "plain text"})
+	for _, r := range results[:2] {
+		if !strings.HasPrefix(r.Error, "тело ответа не является JSON") || r.Value != "" {
+			t.Errorf("%s: результат %+v, ожидалась ошибка разбора JSON", r.Variable, r)
+		}
+	}
+	if results[2].Value != "plain text" || results[2].Error != "" {
+		t.Errorf("all: результат %+v", results[2])
+	}
+}
@@ -0,0 +1,284 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Folder группирует сохраненные запросы и вложенные папки.
+// Базовый URL и заголовки папки наследуются всеми запросами внутри нее.
+type Folder struct {
+	Name     string         `json:"name,omitempty"`
+	BaseURL  string         `json:"baseUrl,omitempty"`
+	Headers  []Header       `json:"headers,omitempty"`
+	Folders  []*Folder      `json:"folders,omitempty"`
+	Requests []SavedRequest `json:"requests,omitempty"`
+}
+
+// Count возвращает количество запросов в папке с учетом вложенных папок
+func (f *Folder) Count() int {
+	count := len(f.Requests)
+	for _, child := range f.Folders {
+		count += child.Count()
+	}
+	return count
+}
+
+// pathTo возвращает цепочку папок от f до target включительно (nil, если target не найдена)
+func (f *Folder) pathTo(target *Folder) []*Folder {
+	if f == target {
+		return []*Folder{f}
+	}
+	for _, child := range f.Folders {
+		if path := child.pathTo(target); path != nil {
+			return append([]*Folder{f}, path...)
+		}
+	}
+	return nil
+}
+
+// findPath возвращает вложенную папку по цепочке имен (nil, если папка не найдена)
+func (f *Folder) findPath(names []string) *Folder {
+	if len(names) == 0 {
+		return nil
+	}
+	for _, child := range f.Folders {
+		if child.Name == names[0] {
+			if len(names) == 1 {
+				return child
+			}
+			return child.findPath(names[1:])
+		}
+	}
+	return nil
+}
+
+// removeFolder удаляет вложенную папку target из дерева
+func (f *Folder) removeFolder(target *Folder) bool {
+	for i, child := range f.Folders {
+		if child == target {
+			f.Folders = append(f.Folders[:i], f.Folders[i+1:]...)
+			return true
+		}
+		if child.removeFolder(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestDefaults содержит унаследованные от папок значения запроса
+type RequestDefaults struct {
+	BaseURL string
+	Headers []Header
+}
+
+// Inherit дополняет значения по умолчанию значениями вложенной папки
+func (d RequestDefaults) Inherit(f *Folder) RequestDefaults {
+	result := RequestDefaults{BaseURL: d.BaseURL, Headers: mergeHeaders(d.Headers, f.Headers)}
+	if f.BaseURL != "" {
+		if d.BaseURL == "" || isAbsoluteURL(f.BaseURL) {
+			result.BaseURL = f.BaseURL
+		} else {
+			result.BaseURL = joinURL(d.BaseURL, f.BaseURL)
+		}
+	}
+	return result
+}
+
+// Apply возвращает запрос с подставленными значениями по умолчанию: относительный URL
+// дополняется базовым, а заголовки папок добавляются, если запрос их не переопределяет
+func (d RequestDefaults) Apply(sr SavedRequest) SavedRequest {
+	if d.BaseURL != "" && !isAbsoluteURL(sr.URL) {
+		sr.URL = joinURL(d.BaseURL, sr.URL)
+	}
+	sr.Headers = mergeHeaders(d.Headers, sr.Headers)
+	return sr
+}
+
+// mergeHeaders объединяет заголовки, значения из override заменяют одноименные из base
+func mergeHeaders(base, override []Header) []Header {
+	merged := make([]Header, 0, len(base)+len(override))
+	for _, h := range base {
+		overridden := false
+		for _, o := range override {
+			if strings.EqualFold(h.Key, o.Key) {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged = append(merged, h)
+		}
+	}
+	return append(merged, override...)
+}
+
+// isAbsoluteURL сообщает, задан ли URL полностью (со схемой или переменной в начале)
+func isAbsoluteURL(rawURL string) bool {
+	return strings.Contains(rawURL, "://") || strings.HasPrefix(rawURL, "{{")
+}
+
+func joinURL(base, path string) string {
+	if path == "" {
+		return base
+	}
+	if strings.HasPrefix(path, "?") {
+		return base + path
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+// --- Коллекция ---
+
+// DefaultsFor возвращает значения, унаследованные запросами папки folder
+func (c *Collection) DefaultsFor(folder *Folder) RequestDefaults {
+	var defaults RequestDefaults
+	for _, f := range c.Folder.pathTo(folder) {
+		defaults = defaults.Inherit(f)
+	}
+	return defaults
+}
+
+// Resolved возвращает все запросы коллекции в порядке дерева с примененным наследованием.
+// Имена запросов во вложенных папках содержат путь: "Папка/Подпапка/Запрос".
+func (c *Collection) Resolved() []SavedRequest {
+	var requests []SavedRequest
+	c.walkResolved(func(path string, sr SavedRequest) {
+		sr.Name = path
+		requests = append(requests, sr)
+	})
+	return requests
+}
+
+// FolderRequests возвращает запросы папки "Папка/Подпапка" с вложенными папками
+// в порядке дерева (пустой путь - вся коллекция)
+func (c *Collection) FolderRequests(path string) ([]SavedRequest, error) {
+	path = strings.Trim(path, "/")
+	if path != "" && c.Folder.findPath(strings.Split(path, "/")) == nil {
+		return nil, fmt.Errorf("папка %q не найдена", path)
+	}
+	var requests []SavedRequest
+	for _, sr := range c.Resolved() {
+		if path == "" || strings.HasPrefix(sr.Name, path+"/") {
+			requests = append(requests, sr)
+		}
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("нет запросов для запуска")
+	}
+	return requests, nil
+}
+
+// FindRequest ищет запрос по пути "Папка/Запрос" или по имени, если оно уникально.
+// Найденный запрос возвращается с путем в имени и примененным наследованием.
+func (c *Collection) FindRequest(name string) (SavedRequest, error) {
+	var exact, matches []SavedRequest
+	c.walkResolved(func(path string, sr SavedRequest) {
+		if path == name {
+			sr.Name = path
+			exact = append(exact, sr)
+		} else if sr.Name == name {
+			sr.Name = path
+			matches = append(matches, sr)
+		}
+	})
+	if len(exact) > 0 {
+		return exact[0], nil
+	}
+	switch len(matches) {
+	case 0:
+		return SavedRequest{}, fmt.Errorf("запрос %q не найден", name)
+	case 1:
+		return matches[0], nil
+	}
+	return SavedRequest{}, fmt.Errorf("найдено несколько запросов с именем %q, укажите путь к папке", name)
+}
+
+// walkResolved обходит запросы коллекции, передавая путь запроса и запрос с примененным наследованием
+func (c *Collection) walkResolved(fn func(path string, sr SavedRequest)) {
+	var walk func(f *Folder, prefix string, defaults RequestDefaults)
+	walk = func(f *Folder, prefix string, defaults RequestDefaults) {
+		defaults = defaults.Inherit(f)
+		for _, child := range f.Folders {
+			walk(child, prefix+child.Name+"/", defaults)
+		}
+		for _, sr := range f.Requests {
+			fn(prefix+sr.Name, defaults.Apply(sr))
+		}
+	}
+	walk(&c.Folder, "", RequestDefaults{})
+}
+
+// --- Дерево вкладки "Сохраненные" ---
+
+// TreeItem представляет папку или запрос в дереве вкладки "Сохраненные"
+type TreeItem struct {
+	Parent   *Folder // папка, в которой находится элемент
+	Folder   *Folder // сама папка (nil для запроса)
+	Index    int     // индекс запроса в Parent.Requests
+	Depth    int
+	Expanded bool
+	Path     string
+}
+
+// IsFolder сообщает, является ли элемент папкой
+func (t TreeItem) IsFolder() bool {
+	return t.Folder != nil
+}
+
+// Request возвращает запрос элемента в том виде, в котором он сохранен
+func (t TreeItem) Request() SavedRequest {
+	return t.Parent.Requests[t.Index]
+}
+
+func (t TreeItem) Title() string {
+	indent := strings.Repeat("  ", t.Depth)
+	if t.IsFolder() {
+		marker := "▸"
+		if t.Expanded {
+			marker = "▾"
+		}
+		return fmt.Sprintf("%s%s %s/", indent, marker, t.Folder.Name)
+	}
+	return indent + "  " + t.Request().Name
+}
+
+func (t TreeItem) Description() string {
+	indent := strings.Repeat("  ", t.Depth)
+	if t.IsFolder() {
+		description := fmt.Sprintf("%s  запросов: %d", indent, t.Folder.Count())
+		if t.Folder.BaseURL != "" {
+			description += " | " + t.Folder.BaseURL
+		}
+		if len(t.Folder.Headers) > 0 {
+			description += fmt.Sprintf(" | заголовков: %d", len(t.Folder.Headers))
+		}
+		return description
+	}
+	return indent + "  " + t.Request().Description()
+}
+
+func (t TreeItem) FilterValue() string { return t.Path }
+
+// buildTree возвращает элементы дерева; содержимое свернутых папок пропускается,
+// если showAll не задан
+func buildTree(root *Folder, expanded map[*Folder]bool, showAll bool) []TreeItem {
+	var items []TreeItem
+	var walk func(f *Folder, depth int, prefix string)
+	walk = func(f *Folder, depth int, prefix string) {
+		for _, child := range f.Folders {
+			path := prefix + child.Name
+			open := showAll || expanded[child]
+			items = append(items, TreeItem{Parent: f, Folder: child, Depth: depth, Expanded: open, Path: path})
+			if open {
+				walk(child, depth+1, path+"/")
+			}
+		}
+		for i, sr := range f.Requests {
+			items = append(items, TreeItem{Parent: f, Index: i, Depth: depth, Path: prefix + sr.Name})
+		}
+	}
+	walk(root, 0, "")
+	return items
+}
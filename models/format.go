@@ -0,0 +1,326 @@
+package models
+
+import (
+	"encoding/json"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ContentKind определяет формат содержимого тела для форматирования и подсветки
+type ContentKind int
+
+const (
+	ContentText ContentKind = iota
+	ContentJSON
+	ContentXML
+	ContentHTML
+	ContentYAML
+	ContentForm   // application/x-www-form-urlencoded
+	ContentBinary // двоичные данные, показываются шестнадцатеричным дампом
+)
+
+var contentKindNames = [...]string{"Текст", "JSON", "XML", "HTML", "YAML", "Form", "Двоичные данные"}
+
+// Name возвращает название формата для интерфейса
+func (k ContentKind) Name() string {
+	return contentKindNames[k]
+}
+
+var (
+	// yamlLine соответствует строкам YAML вида "key: value" и элементам списка
+	yamlLine = regexp.MustCompile(`^\s*(- |-$|[\w"'.\-/]+:(\s|$)|#)`)
+	// formBody соответствует телу вида a=1&b=2
+	formBody = regexp.MustCompile(`^[^=&\s]+=[^&\s]*(&[^=&\s]+=[^&\s]*)*$`)
+)
+
+// HeaderValue возвращает значение первого заголовка с указанным именем
+func HeaderValue(headers []Header, key string) string {
+	for _, h := range headers {
+		if strings.EqualFold(h.Key, key) {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// DetectContentKind определяет формат тела по Content-Type. Если тип не задан
+// или не говорит о формате (text/plain, application/octet-stream), формат
+// определяется по содержимому.
+func DetectContentKind(contentType, body string) ContentKind {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, _, _ = strings.Cut(strings.ToLower(contentType), ";")
+		mediaType = strings.TrimSpace(mediaType)
+	}
+	switch {
+	case mediaType == "application/json", mediaType == "text/json", strings.HasSuffix(mediaType, "+json"):
+		return ContentJSON
+	case mediaType == "text/html", mediaType == "application/xhtml+xml":
+		return ContentHTML
+	case strings.HasSuffix(mediaType, "/xml"), strings.HasSuffix(mediaType, "+xml"):
+		return ContentXML
+	case strings.Contains(mediaType, "yaml"):
+		return ContentYAML
+	case mediaType == "application/x-www-form-urlencoded":
+		return ContentForm
+	}
+	return SniffContentKind(body)
+}
+
+// SniffContentKind определяет формат тела по содержимому
+func SniffContentKind(body string) ContentKind {
+	trimmed := strings.TrimSpace(body)
+	if trimmed == "" {
+		return ContentText
+	}
+	switch trimmed[0] {
+	case '{', '[':
+		if json.Valid([]byte(trimmed)) {
+			return ContentJSON
+		}
+	case '<':
+		head := strings.ToLower(trimmed[:min(len(trimmed), 512)])
+		if strings.HasPrefix(head, "<!doctype html") || strings.Contains(head, "<html") {
+			return ContentHTML
+		}
+		if strings.HasSuffix(trimmed, ">") {
+			return ContentXML
+		}
+	}
+	if !strings.ContainsAny(trimmed, "\n") && formBody.MatchString(trimmed) {
+		return ContentForm
+	}
+	if strings.HasPrefix(trimmed, "---\n") || strings.HasPrefix(trimmed, "%YAML") {
+		return ContentYAML
+	}
+	// Несколько строк, каждая из которых похожа на YAML
+	lines := strings.Split(trimmed, "\n")
+	if len(lines) < 2 {
+		return ContentText
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" && !yamlLine.MatchString(line) && !strings.HasPrefix(line, " ") {
+			return ContentText
+		}
+	}
+	return ContentYAML
+}
+
+// PrettyBody форматирует тело для чтения. Если тело не удалось разобрать,
+// оно возвращается без изменений.
+func PrettyBody(kind ContentKind, body string) string {
+	switch kind {
+	case ContentJSON:
+		return FormatJSON(body)
+	case ContentXML:
+		return formatMarkup(body, false)
+	case ContentHTML:
+		return formatMarkup(body, true)
+	case ContentForm:
+		return formatFormBody(body)
+	case ContentBinary:
+		return FormatHexDump(body)
+	}
+	return body
+}
+
+// formatFormBody записывает поля application/x-www-form-urlencoded по одному
+// на строке с раскодированными значениями
+func formatFormBody(body string) string {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return ""
+	}
+	var lines []string
+	for _, pair := range strings.Split(body, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if v, err := url.QueryUnescape(value); err == nil {
+			value = v
+		}
+		lines = append(lines, key+"="+value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markupTokenKind определяет вид фрагмента разметки
+type markupTokenKind int
+
+const (
+	markupText  markupTokenKind = iota
+	markupOpen                  // открывающий тег
+	markupClose                 // закрывающий тег
+	markupLeaf                  // тег без содержимого, комментарий, объявление
+	markupRaw                   // содержимое script и style в HTML
+)
+
+type markupToken struct {
+	kind markupTokenKind
+	text string
+	name string // имя тега
+}
+
+// htmlVoidElements - элементы HTML без закрывающего тега
+var htmlVoidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// htmlSiblingElements - элементы HTML, которые неявно закрываются следующим
+// таким же элементом (<li>a<li>b)
+var htmlSiblingElements = map[string]bool{
+	"li": true, "p": true, "option": true, "tr": true, "td": true, "th": true, "dt": true, "dd": true,
+}
+
+// tokenizeMarkup разбивает XML или HTML на теги и текст
+func tokenizeMarkup(body string, html bool) []markupToken {
+	var tokens []markupToken
+	for i := 0; i < len(body); {
+		rest := body[i:]
+		if rest[0] != '<' {
+			end := strings.IndexByte(rest, '<')
+			if end < 0 {
+				end = len(rest)
+			}
+			tokens = append(tokens, markupToken{kind: markupText, text: rest[:end]})
+			i += end
+			continue
+		}
+
+		closer := ">"
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			closer = "-->"
+		case strings.HasPrefix(rest, "<![CDATA["):
+			closer = "]]>"
+		case strings.HasPrefix(rest, "<?"):
+			closer = "?>"
+		}
+		var end int
+		if closer == ">" && !strings.HasPrefix(rest, "<!") {
+			end = markupTagEnd(rest)
+		} else if end = strings.Index(rest, closer); end >= 0 {
+			end += len(closer)
+		}
+		if end < 0 {
+			// Незакрытый тег: остаток показывается как текст
+			tokens = append(tokens, markupToken{kind: markupText, text: rest})
+			break
+		}
+		tag := rest[:end]
+		i += end
+
+		token := markupToken{kind: markupLeaf, text: tag}
+		if closer == ">" && !strings.HasPrefix(tag, "<!") {
+			token.name = markupTagName(tag, html)
+			switch {
+			case strings.HasPrefix(tag, "</"):
+				token.kind = markupClose
+			case !strings.HasSuffix(tag, "/>") && !(html && htmlVoidElements[token.name]):
+				token.kind = markupOpen
+			}
+		}
+		tokens = append(tokens, token)
+
+		// Содержимое script и style не разбирается как разметка
+		if html && token.kind == markupOpen && (token.name == "script" || token.name == "style") {
+			end := strings.Index(strings.ToLower(body[i:]), "</"+token.name)
+			if end < 0 {
+				end = len(body) - i
+			}
+			tokens = append(tokens, markupToken{kind: markupRaw, text: body[i : i+end]})
+			i += end
+		}
+	}
+	return tokens
+}
+
+// markupTagEnd возвращает позицию после закрывающей скобки тега с учетом
+// кавычек в атрибутах или -1, если тег не закрыт
+func markupTagEnd(tag string) int {
+	var quote byte
+	for i := 1; i < len(tag); i++ {
+		switch c := tag[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// markupTagName возвращает имя тега. Имена тегов HTML приводятся к нижнему регистру.
+func markupTagName(tag string, html bool) string {
+	name := strings.TrimLeft(tag, "</")
+	if end := strings.IndexAny(name, " \t\r\n/>"); end >= 0 {
+		name = name[:end]
+	}
+	if html {
+		name = strings.ToLower(name)
+	}
+	return name
+}
+
+// formatMarkup расставляет отступы в XML или HTML: каждый тег на своей строке,
+// элементы только с текстом - одной строкой
+func formatMarkup(body string, html bool) string {
+	tokens := tokenizeMarkup(body, html)
+	var sb strings.Builder
+	var stack []string
+	write := func(text string) {
+		indent := strings.Repeat("  ", len(stack))
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				sb.WriteString(indent + line + "\n")
+			}
+		}
+	}
+	isText := func(i int) bool {
+		return i < len(tokens) && tokens[i].kind == markupText && !strings.Contains(strings.TrimSpace(tokens[i].text), "\n")
+	}
+	closes := func(i int, name string) bool {
+		return i < len(tokens) && tokens[i].kind == markupClose && tokens[i].name == name
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch token.kind {
+		case markupOpen:
+			if html && htmlSiblingElements[token.name] && len(stack) > 0 && stack[len(stack)-1] == token.name {
+				stack = stack[:len(stack)-1]
+			}
+			switch {
+			case closes(i+1, token.name):
+				write(token.text + tokens[i+1].text)
+				i++
+			case isText(i+1) && closes(i+2, token.name):
+				write(token.text + strings.TrimSpace(tokens[i+1].text) + tokens[i+2].text)
+				i += 2
+			default:
+				write(token.text)
+				stack = append(stack, token.name)
+			}
+		case markupClose:
+			// Закрывающий тег снимает со стека и незакрытые вложенные элементы (<li>, <p> в HTML)
+			for depth := len(stack) - 1; depth >= 0; depth-- {
+				if stack[depth] == token.name {
+					stack = stack[:depth]
+					break
+				}
+			}
+			write(token.text)
+		default:
+			write(token.text)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,376 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	syntaxKeyStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("75"))
+	syntaxStringStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	syntaxNumberStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("215"))
+	syntaxLiteralStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("176"))
+	syntaxPunctStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	syntaxTagStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("81"))
+	syntaxAttrStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("180"))
+	syntaxCommentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("242")).Italic(true)
+)
+
+var (
+	yamlKey    = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s#'"\-][^#]*?|-[^\s#][^#]*?):(\s|$)`)
+	yamlNumber = regexp.MustCompile(`^[-+]?(\d[\d_]*(\.\d*)?([eE][-+]?\d+)?|\.\d+|0x[0-9a-fA-F]+|\.inf|\.nan)$`)
+)
+
+// styledSpan - участок строки с одним стилем (nil - без оформления)
+type styledSpan struct {
+	text  string
+	style *lipgloss.Style
+}
+
+// spanList собирает участки строки, объединяя соседние участки с одним стилем
+type spanList []styledSpan
+
+func (l *spanList) add(text string, style *lipgloss.Style) {
+	if text == "" {
+		return
+	}
+	if n := len(*l); n > 0 && (*l)[n-1].style == style {
+		(*l)[n-1].text += text
+		return
+	}
+	*l = append(*l, styledSpan{text: text, style: style})
+}
+
+// syntaxHighlighter раскрашивает текст построчно. Хранит состояние между
+// строками: многострочные комментарии и теги разметки.
+type syntaxHighlighter struct {
+	kind      ContentKind
+	inComment bool
+	inTag     bool
+	tagName   string // имя тега, атрибуты которого раскрашиваются
+	rawTag    string // script или style в HTML, содержимое которых не раскрашивается
+}
+
+// HighlightBody раскрашивает текст тела в соответствии с форматом
+func HighlightBody(kind ContentKind, text string) string {
+	if kind == ContentText {
+		return text
+	}
+	h := &syntaxHighlighter{kind: kind}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = renderSpans(h.spans(line), nil)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// spans разбивает строку на участки с оформлением
+func (h *syntaxHighlighter) spans(line string) []styledSpan {
+	var spans spanList
+	switch h.kind {
+	case ContentJSON:
+		jsonSpans(line, &spans)
+	case ContentXML, ContentHTML:
+		h.markupSpans(line, &spans)
+	case ContentYAML:
+		yamlSpans(line, &spans)
+	case ContentForm:
+		formSpans(line, &spans)
+	case ContentBinary:
+		hexDumpSpans(line, &spans)
+	default:
+		spans.add(line, nil)
+	}
+	return spans
+}
+
+// jsonSpans раскрашивает строку JSON: ключи, строки, числа и литералы.
+// Подходит и для строк дерева JSON.
+func jsonSpans(line string, spans *spanList) {
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == '"':
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(line))
+			style := &syntaxStringStyle
+			if strings.HasPrefix(strings.TrimLeft(line[end:], " "), ":") {
+				style = &syntaxKeyStyle
+			}
+			spans.add(line[i:end], style)
+			i = end
+		case c == '-' || c >= '0' && c <= '9':
+			end := i + 1
+			for end < len(line) && strings.IndexByte("0123456789.eE+-", line[end]) >= 0 {
+				end++
+			}
+			spans.add(line[i:end], &syntaxNumberStyle)
+			i = end
+		case strings.IndexByte("{}[],:", c) >= 0:
+			spans.add(line[i:i+1], &syntaxPunctStyle)
+			i++
+		default:
+			end := i + 1
+			for end < len(line) && strings.IndexByte("\"-0123456789{}[],:", line[end]) < 0 {
+				end++
+			}
+			word := line[i:end]
+			if trimmed := strings.TrimSpace(word); trimmed == "true" || trimmed == "false" || trimmed == "null" {
+				spans.add(word, &syntaxLiteralStyle)
+			} else {
+				spans.add(word, nil)
+			}
+			i = end
+		}
+	}
+}
+
+// markupSpans раскрашивает строку XML или HTML: теги, атрибуты и комментарии
+func (h *syntaxHighlighter) markupSpans(line string, spans *spanList) {
+	for i := 0; i < len(line); {
+		rest := line[i:]
+		switch {
+		case h.rawTag != "":
+			end := strings.Index(strings.ToLower(rest), "</"+h.rawTag)
+			if end < 0 {
+				spans.add(rest, nil)
+				return
+			}
+			spans.add(rest[:end], nil)
+			h.rawTag = ""
+			i += end
+		case h.inComment:
+			end := strings.Index(rest, "-->")
+			if end < 0 {
+				spans.add(rest, &syntaxCommentStyle)
+				return
+			}
+			spans.add(rest[:end+3], &syntaxCommentStyle)
+			h.inComment = false
+			i += end + 3
+		case h.inTag:
+			i += h.tagSpans(rest, spans)
+		case strings.HasPrefix(rest, "<!--"):
+			h.inComment = true
+		case strings.HasPrefix(rest, "<![CDATA["):
+			end := strings.Index(rest, "]]>")
+			if end < 0 {
+				end = len(rest) - 3
+			}
+			spans.add(rest[:end+3], &syntaxStringStyle)
+			i += end + 3
+		case rest[0] == '<':
+			start := 1
+			if len(rest) > 1 && strings.IndexByte("/?!", rest[1]) >= 0 {
+				start = 2
+			}
+			end := start
+			for end < len(rest) && strings.IndexByte(" \t/>", rest[end]) < 0 {
+				end++
+			}
+			spans.add(rest[:start], &syntaxPunctStyle)
+			spans.add(rest[start:end], &syntaxTagStyle)
+			h.inTag = true
+			h.tagName = ""
+			if start == 1 {
+				h.tagName = strings.ToLower(rest[start:end])
+			}
+			i += end
+		default:
+			end := strings.IndexByte(rest, '<')
+			if end < 0 {
+				end = len(rest)
+			}
+			spans.add(rest[:end], nil)
+			i += end
+		}
+	}
+}
+
+// tagSpans раскрашивает атрибуты тега до закрывающей скобки и возвращает
+// количество обработанных байт
+func (h *syntaxHighlighter) tagSpans(rest string, spans *spanList) int {
+	for i := 0; i < len(rest); {
+		c := rest[i]
+		switch {
+		case strings.HasPrefix(rest[i:], "/>"), strings.HasPrefix(rest[i:], "?>"):
+			spans.add(rest[i:i+2], &syntaxPunctStyle)
+			h.inTag = false
+			return i + 2
+		case c == '>':
+			spans.add(">", &syntaxPunctStyle)
+			h.inTag = false
+			if h.kind == ContentHTML && (h.tagName == "script" || h.tagName == "style") {
+				h.rawTag = h.tagName
+			}
+			return i + 1
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(rest[i+1:], c)
+			if end < 0 {
+				spans.add(rest[i:], &syntaxStringStyle)
+				return len(rest)
+			}
+			spans.add(rest[i:i+end+2], &syntaxStringStyle)
+			i += end + 2
+		case c == '=':
+			spans.add("=", &syntaxPunctStyle)
+			i++
+		case c == ' ' || c == '\t':
+			spans.add(rest[i:i+1], nil)
+			i++
+		default:
+			end := i + 1
+			for end < len(rest) && strings.IndexByte(" \t=>/?\"'", rest[end]) < 0 {
+				end++
+			}
+			spans.add(rest[i:end], &syntaxAttrStyle)
+			i = end
+		}
+	}
+	return len(rest)
+}
+
+// yamlSpans раскрашивает строку YAML: ключи, значения, элементы списков и комментарии
+func yamlSpans(line string, spans *spanList) {
+	rest := strings.TrimLeft(line, " ")
+	spans.add(line[:len(line)-len(rest)], nil)
+	if rest == "---" || rest == "..." {
+		spans.add(rest, &syntaxPunctStyle)
+		return
+	}
+	for rest == "-" || strings.HasPrefix(rest, "- ") {
+		spans.add("-", &syntaxPunctStyle)
+		rest = rest[1:]
+		trimmed := strings.TrimLeft(rest, " ")
+		spans.add(rest[:len(rest)-len(trimmed)], nil)
+		rest = trimmed
+	}
+	if m := yamlKey.FindStringSubmatchIndex(rest); m != nil {
+		spans.add(rest[:m[3]], &syntaxKeyStyle)
+		spans.add(":", &syntaxPunctStyle)
+		rest = rest[m[3]+1:]
+	}
+	yamlValueSpans(rest, spans)
+}
+
+// yamlValueSpans раскрашивает значение YAML с комментарием в конце строки
+func yamlValueSpans(value string, spans *spanList) {
+	comment := ""
+	if strings.HasPrefix(value, "#") {
+		value, comment = "", value
+	} else if i := strings.Index(value, " #"); i >= 0 && !strings.ContainsAny(value[:i], `"'`) {
+		value, comment = value[:i], value[i:]
+	}
+	trimmed := strings.TrimSpace(value)
+	lead := value[:strings.Index(value, trimmed)]
+	spans.add(lead, nil)
+	switch {
+	case trimmed == "":
+	case trimmed == "|" || trimmed == ">" || strings.HasPrefix(trimmed, "|-") || strings.HasPrefix(trimmed, ">-"):
+		spans.add(trimmed, &syntaxPunctStyle)
+	case trimmed == "true" || trimmed == "false" || trimmed == "null" || trimmed == "~":
+		spans.add(trimmed, &syntaxLiteralStyle)
+	case yamlNumber.MatchString(trimmed):
+		spans.add(trimmed, &syntaxNumberStyle)
+	case trimmed[0] == '&' || trimmed[0] == '*' || trimmed[0] == '!':
+		spans.add(trimmed, &syntaxAttrStyle)
+	default:
+		spans.add(trimmed, &syntaxStringStyle)
+	}
+	spans.add(value[len(lead)+len(trimmed):], nil)
+	spans.add(comment, &syntaxCommentStyle)
+}
+
+// formSpans раскрашивает поле формы "name=value" или "name=@path;type=..."
+func formSpans(line string, spans *spanList) {
+	key, value, ok := strings.Cut(line, "=")
+	spans.add(key, &syntaxKeyStyle)
+	if !ok {
+		return
+	}
+	spans.add("=", &syntaxPunctStyle)
+	if strings.HasPrefix(value, "@") {
+		spans.add(value, &syntaxAttrStyle)
+		return
+	}
+	spans.add(value, &syntaxStringStyle)
+}
+
+// hexDumpSpans раскрашивает строку шестнадцатеричного дампа: смещение,
+// байты и их представление символами
+func hexDumpSpans(line string, spans *spanList) {
+	offset, rest, ok := strings.Cut(line, "  ")
+	if !ok || len(offset) != 8 {
+		spans.add(line, &syntaxCommentStyle)
+		return
+	}
+	spans.add(offset, &syntaxPunctStyle)
+	spans.add("  ", nil)
+	if i := strings.IndexByte(rest, '|'); i >= 0 {
+		spans.add(rest[:i], nil)
+		spans.add(rest[i:], &syntaxStringStyle)
+		return
+	}
+	spans.add(rest, nil)
+}
+
+// styledRange - байтовый диапазон строки со стилем, заменяющим подсветку синтаксиса
+type styledRange struct {
+	start, end int
+	style      *lipgloss.Style
+}
+
+// renderSpans оформляет участки строки. Диапазоны ranges (вхождения поиска)
+// перекрывают подсветку синтаксиса.
+func renderSpans(spans []styledSpan, ranges []styledRange) string {
+	var sb strings.Builder
+	pos := 0
+	for _, span := range spans {
+		for text := span.text; text != ""; {
+			n, style := len(text), span.style
+			for _, r := range ranges {
+				switch {
+				case pos >= r.start && pos < r.end:
+					n, style = min(n, r.end-pos), r.style
+				case r.start > pos:
+					n = min(n, r.start-pos)
+				}
+			}
+			if style != nil {
+				open, close := styleCodes(style)
+				sb.WriteString(open + text[:n] + close)
+			} else {
+				sb.WriteString(text[:n])
+			}
+			text = text[n:]
+			pos += n
+		}
+	}
+	return sb.String()
+}
+
+// styleCodesCache хранит управляющие последовательности стилей. Render для
+// каждого участка слишком медленный на телах в мегабайты.
+var styleCodesCache sync.Map
+
+// styleCodes возвращает последовательности, которые стиль ставит до и после текста
+func styleCodes(style *lipgloss.Style) (open, close string) {
+	if codes, ok := styleCodesCache.Load(style); ok {
+		c := codes.([2]string)
+		return c[0], c[1]
+	}
+	rendered := style.Render("X")
+	i := strings.Index(rendered, "X")
+	open, close = rendered[:i], rendered[i+1:]
+	styleCodesCache.Store(style, [2]string{open, close})
+	return open, close
+}
@@ -0,0 +1,222 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+const (
+	// maxHistoryEntries ограничивает количество хранимых записей истории
+	maxHistoryEntries = 200
+	// maxHistoryResponseSize ограничивает размер сохраняемого тела ответа
+	maxHistoryResponseSize = 64 * 1024
+)
+
+// RequestSnapshot содержит копию отправленного запроса
+type RequestSnapshot struct {
+	Method  string   `json:"method"`
+	URL     string   `json:"url"`
+	Headers []Header `json:"headers"`
+	Params  []Param  `json:"params"`
+	Body    string   `json:"body"`
+	// BodyType и Form позволяют восстановить тело формы из истории
+	BodyType BodyType    `json:"bodyType,omitempty"`
+	Form     []FormField `json:"form,omitempty"`
+}
+
+// HistoryEntry определяет структуру записи истории запросов
+type HistoryEntry struct {
+	Timestamp  time.Time       `json:"timestamp"`
+	Request    RequestSnapshot `json:"request"`
+	Status     string          `json:"status"`
+	StatusCode int             `json:"statusCode"`
+	Time       string          `json:"time"`
+	Proto      string          `json:"proto,omitempty"`
+	Timings    *Timings        `json:"timings,omitempty"`
+	Response   string          `json:"response"`
+	// ResponseHeaders содержит заголовки ответа
+	ResponseHeaders []Header          `json:"responseHeaders,omitempty"`
+	Truncated       bool              `json:"truncated,omitempty"`
+	Error           string            `json:"error,omitempty"`
+	Assertions      []AssertionResult `json:"assertions,omitempty"` // результаты проверок ответа
+}
+
+// Implement list.Item interface for HistoryEntry
+func (e HistoryEntry) Title() string {
+	return fmt.Sprintf("[%s] %s", e.Request.Method, e.Request.URL)
+}
+func (e HistoryEntry) Description() string {
+	result := e.Status
+	if e.Error != "" {
+		result = "Ошибка: " + e.Error
+	}
+	if len(e.Assertions) > 0 {
+		result += fmt.Sprintf(" | проверки %d/%d", CountPassed(e.Assertions), len(e.Assertions))
+	}
+	return fmt.Sprintf("%s | %s | %s", e.Timestamp.Format("2006-01-02 15:04:05"), result, e.Time)
+}
+func (e HistoryEntry) FilterValue() string {
+	return fmt.Sprintf("%s %s %d", e.Request.Method, e.Request.URL, e.StatusCode)
+}
+
+// ToSavedRequest преобразует запись истории в сохраненный запрос
+func (e HistoryEntry) ToSavedRequest(name string) SavedRequest {
+	return SavedRequest{
+		Name:     name,
+		Method:   ParseMethod(e.Request.Method),
+		URL:      e.Request.URL,
+		Body:     e.Request.Body,
+		BodyType: e.Request.BodyType,
+		Form:     e.Request.Form,
+		Headers:  e.Request.Headers,
+		Params:   e.Request.Params,
+	}
+}
+
+// NewHistoryEntry создает запись истории из данных ответа
+func NewHistoryEntry(data ResponseData) HistoryEntry {
+	timings := data.Timings
+	if data.Binary {
+		// Двоичное тело не сохраняется в истории
+		data.Body = ""
+	}
+	return HistoryEntry{
+		Truncated:       data.Binary || data.BodyFile != "",
+		Timestamp:       data.Timestamp,
+		Request:         data.Request,
+		Status:          data.Status,
+		StatusCode:      data.StatusCode,
+		Time:            data.Time,
+		Proto:           data.Proto,
+		Timings:         &timings,
+		Response:        data.Body,
+		ResponseHeaders: data.Headers,
+		Assertions:      data.Assertions,
+	}
+}
+
+// truncate обрезает тело ответа до размера, хранимого в истории,
+// не разрывая многобайтовые символы UTF-8
+func (e HistoryEntry) truncate() HistoryEntry {
+	if len(e.Response) > maxHistoryResponseSize {
+		end := maxHistoryResponseSize
+		for end > maxHistoryResponseSize-utf8.UTFMax && !utf8.RuneStart(e.Response[end]) {
+			end--
+		}
+		e.Response = e.Response[:end]
+		e.Truncated = true
+	}
+	return e
+}
+
+// GetHistory возвращает записи истории (новые записи первыми)
+func (m *AppModel) GetHistory() []HistoryEntry {
+	entries := make([]HistoryEntry, len(m.history))
+	for i, item := range m.history {
+		entries[i] = item.(HistoryEntry)
+	}
+	return entries
+}
+
+// --- Логика Сохранения/Загрузки ---
+
+func getHistoryPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "history.json"), nil
+}
+
+// LoadHistory загружает историю запросов (новые записи первыми)
+func LoadHistory() ([]HistoryEntry, error) {
+	path, err := getHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entries []HistoryEntry
+	err = json.Unmarshal(data, &entries)
+	return entries, err
+}
+
+// SaveHistory сохраняет историю запросов
+func SaveHistory(entries []HistoryEntry) error {
+	path, err := getHistoryPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (m *AppModel) loadHistory() error {
+	entries, err := LoadHistory()
+	if err != nil {
+		// Не перезаписываем файл, который не удалось прочитать
+		m.historyErr = fmt.Errorf("история не загружена: %w", err)
+		m.addNotice("Ошибка: " + m.historyErr.Error())
+		return err
+	}
+	m.history = make([]list.Item, len(entries))
+	for i, e := range entries {
+		m.history[i] = e
+	}
+	m.historyList.SetItems(m.history)
+	return nil
+}
+
+func (m *AppModel) saveHistory() error {
+	if m.historyErr != nil {
+		return m.historyErr
+	}
+	return SaveHistory(m.GetHistory())
+}
+
+// addHistoryEntry добавляет запись в начало истории с учетом ограничения размера
+func (m *AppModel) addHistoryEntry(entry HistoryEntry) {
+	m.history = append([]list.Item{entry.truncate()}, m.history...)
+	if len(m.history) > maxHistoryEntries {
+		m.history = m.history[:maxHistoryEntries]
+	}
+	m.historyList.SetItems(m.history)
+	m.saveHistory()
+}
+
+func (m *AppModel) selectedHistoryEntry() (HistoryEntry, bool) {
+	entry, ok := m.historyList.SelectedItem().(HistoryEntry)
+	return entry, ok
+}
+
+// LoadRequestFromHistory загружает выбранную запись истории на вкладку "Запрос"
+func (m *AppModel) LoadRequestFromHistory() {
+	if entry, ok := m.selectedHistoryEntry(); ok {
+		m.loadRequest(entry.ToSavedRequest(""), nil, false)
+	}
+}
+
+// AddSavedRequestFromHistory сохраняет выбранную запись истории как именованный запрос
+func (m *AppModel) AddSavedRequestFromHistory(name string) {
+	if entry, ok := m.selectedHistoryEntry(); ok {
+		m.addSavedRequest(&m.collection.Folder, entry.ToSavedRequest(name))
+	}
+}
+
+func (m *AppModel) GetHistoryList() *list.Model {
+	return &m.historyList
+}
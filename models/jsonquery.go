@@ -0,0 +1,673 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// jsonStep преобразует значение в набор значений (шаг пути или звено конвейера jq)
+type jsonStep func(v *JSONValue) []*JSONValue
+
+// jsonCond проверяет значение в фильтре [?(...)] или select(...)
+type jsonCond func(v *JSONValue) bool
+
+// jsonOperand возвращает операнд условия для значения (nil, если путь не найден)
+type jsonOperand func(v *JSONValue) *JSONValue
+
+// QueryJSON применяет к документу выражение JSONPath ($.items[0].name, $..id,
+// $.items[?(@.price < 10)]) или подмножества jq (.items[] | select(.active) | .name, keys, length).
+// Результат JSONPath - массив найденных значений, результат jq - последовательность значений.
+func QueryJSON(root *JSONValue, expr string) ([]*JSONValue, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return []*JSONValue{root}, nil
+	}
+
+	p := &queryParser{src: expr}
+	var steps []jsonStep
+	var err error
+	jsonPath := strings.HasPrefix(expr, "$")
+	if jsonPath {
+		p.pos = 1
+		steps, err = p.parsePath()
+	} else {
+		p.jq = true
+		steps, err = p.parsePipeline()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpaces(); !p.eof() {
+		return nil, p.errorf("неожиданный символ %q", p.src[p.pos:p.pos+1])
+	}
+
+	results := []*JSONValue{root}
+	for _, step := range steps {
+		var next []*JSONValue
+		for _, v := range results {
+			next = append(next, step(v)...)
+		}
+		results = next
+	}
+	if jsonPath {
+		return []*JSONValue{{Kind: JSONArray, Items: results}}, nil
+	}
+	return results, nil
+}
+
+// FormatJSONValues записывает значения с отступами, каждое с новой строки, как jq
+func FormatJSONValues(values []*JSONValue) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = v.Format()
+	}
+	return strings.Join(parts, "\n")
+}
+
+// --- Разбор выражений ---
+
+type queryParser struct {
+	src string
+	pos int
+	jq  bool // синтаксис jq: конвейеры, select(...), точка как текущее значение
+}
+
+func (p *queryParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *queryParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *queryParser) skipSpaces() {
+	for !p.eof() && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *queryParser) consume(s string) bool {
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// consumeWord принимает слово, за которым не следует символ идентификатора
+func (p *queryParser) consumeWord(word string) bool {
+	if !strings.HasPrefix(p.src[p.pos:], word) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(p.src[p.pos+len(word):]); p.isIdentRune(r) {
+		return false
+	}
+	p.pos += len(word)
+	return true
+}
+
+func (p *queryParser) expect(s string) error {
+	p.skipSpaces()
+	if !p.consume(s) {
+		return p.errorf("ожидается %q", s)
+	}
+	return nil
+}
+
+func (p *queryParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("позиция %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *queryParser) isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || (!p.jq && (r == '-' || r == '$'))
+}
+
+// parsePipeline разбирает конвейер jq: звенья, разделенные |
+func (p *queryParser) parsePipeline() ([]jsonStep, error) {
+	var steps []jsonStep
+	for {
+		p.skipSpaces()
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, term...)
+		p.skipSpaces()
+		if !p.consume("|") {
+			return steps, nil
+		}
+	}
+}
+
+// parseTerm разбирает звено конвейера jq: путь или функцию
+func (p *queryParser) parseTerm() ([]jsonStep, error) {
+	switch {
+	case p.peek() == '.':
+		return p.parsePath()
+	case p.consumeWord("keys"):
+		return []jsonStep{jqKeys}, nil
+	case p.consumeWord("length"):
+		return []jsonStep{jqLength}, nil
+	case p.consumeWord("first"):
+		return []jsonStep{indexStep([]int{0})}, nil
+	case p.consumeWord("last"):
+		return []jsonStep{indexStep([]int{-1})}, nil
+	case p.consumeWord("select"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		cond, err := p.parseCond()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return []jsonStep{func(v *JSONValue) []*JSONValue {
+			if cond(v) {
+				return []*JSONValue{v}
+			}
+			return nil
+		}}, nil
+	}
+	if p.eof() {
+		return nil, p.errorf("ожидается выражение")
+	}
+	return nil, p.errorf("неизвестное выражение %q", p.src[p.pos:])
+}
+
+// parsePath разбирает шаги пути: .name, ."name", .*, ..name, [0], [-1], [1:3], ['a','b'], [*], [], [?(cond)]
+func (p *queryParser) parsePath() ([]jsonStep, error) {
+	var steps []jsonStep
+	for !p.eof() {
+		switch {
+		case p.consume(".."):
+			steps = append(steps, descendantsStep)
+			// $..name и $..* применяют следующий шаг ко всем вложенным значениям
+			if r, _ := utf8.DecodeRuneInString(p.src[p.pos:]); p.isIdentRune(r) || r == '*' || r == '"' {
+				step, err := p.parseMember()
+				if err != nil {
+					return nil, err
+				}
+				steps = append(steps, step)
+			}
+		case p.consume("."):
+			// Точка без имени (jq ".", ".[0]") обозначает текущее значение
+			if r, _ := utf8.DecodeRuneInString(p.src[p.pos:]); p.isIdentRune(r) || r == '*' || r == '"' {
+				step, err := p.parseMember()
+				if err != nil {
+					return nil, err
+				}
+				steps = append(steps, step)
+			}
+		case p.peek() == '[':
+			step, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		case p.peek() == '?' && p.jq:
+			// Необязательный доступ jq (.a?): ошибки доступа и так не возникают
+			p.pos++
+		default:
+			return steps, nil
+		}
+	}
+	return steps, nil
+}
+
+// parseMember разбирает имя после точки: идентификатор, строку в кавычках или *
+func (p *queryParser) parseMember() (jsonStep, error) {
+	if p.consume("*") {
+		return childrenStep, nil
+	}
+	if p.peek() == '"' {
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return fieldStep([]string{key}), nil
+	}
+	start := p.pos
+	for !p.eof() {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !p.isIdentRune(r) {
+			break
+		}
+		p.pos += size
+	}
+	return fieldStep([]string{p.src[start:p.pos]}), nil
+}
+
+// parseBracket разбирает шаг в квадратных скобках
+func (p *queryParser) parseBracket() (jsonStep, error) {
+	p.pos++ // [
+	p.skipSpaces()
+	switch {
+	case p.consume("]"):
+		return childrenStep, nil
+	case p.consume("*"):
+		return childrenStep, p.expect("]")
+	case p.consume("?("):
+		cond, err := p.parseCond()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return filterStep(cond), p.expect("]")
+	case p.peek() == '"' || p.peek() == '\'':
+		var keys []string
+		for {
+			p.skipSpaces()
+			key, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+			p.skipSpaces()
+			if !p.consume(",") {
+				break
+			}
+		}
+		return fieldStep(keys), p.expect("]")
+	}
+
+	start, hasStart := p.parseInt()
+	p.skipSpaces()
+	if p.consume(":") {
+		p.skipSpaces()
+		end, hasEnd := p.parseInt()
+		var from, to *int
+		if hasStart {
+			from = &start
+		}
+		if hasEnd {
+			to = &end
+		}
+		return sliceStep(from, to, p.jq), p.expect("]")
+	}
+	if !hasStart {
+		return nil, p.errorf("ожидается индекс, ключ, * или ?(условие)")
+	}
+	indices := []int{start}
+	for p.skipSpaces(); p.consume(","); p.skipSpaces() {
+		p.skipSpaces()
+		index, ok := p.parseInt()
+		if !ok {
+			return nil, p.errorf("ожидается индекс")
+		}
+		indices = append(indices, index)
+	}
+	return indexStep(indices), p.expect("]")
+}
+
+func (p *queryParser) parseInt() (int, bool) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for !p.eof() && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	n, err := strconv.Atoi(p.src[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, false
+	}
+	return n, true
+}
+
+// parseString разбирает строку в двойных (с экранированием JSON) или одинарных кавычках
+func (p *queryParser) parseString() (string, error) {
+	quote := p.peek()
+	if quote != '"' && quote != '\'' {
+		return "", p.errorf("ожидается строка в кавычках")
+	}
+	start := p.pos
+	var sb strings.Builder
+	for p.pos++; !p.eof(); p.pos++ {
+		c := p.src[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.src):
+			p.pos++
+			if quote == '"' {
+				sb.WriteByte('\\')
+			}
+			sb.WriteByte(p.src[p.pos])
+		case c == quote:
+			p.pos++
+			if quote == '\'' {
+				return sb.String(), nil
+			}
+			var s string
+			if err := json.Unmarshal([]byte(`"`+sb.String()+`"`), &s); err != nil {
+				return "", fmt.Errorf("позиция %d: неверная строка", start+1)
+			}
+			return s, nil
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("позиция %d: незакрытая кавычка", start+1)
+}
+
+// parseCond разбирает условие: сравнения, объединенные ||/or и &&/and
+func (p *queryParser) parseCond() (jsonCond, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if !p.consume("||") && !p.consumeWord("or") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v *JSONValue) bool { return l(v) || right(v) }
+	}
+}
+
+func (p *queryParser) parseAnd() (jsonCond, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if !p.consume("&&") && !p.consumeWord("and") {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v *JSONValue) bool { return l(v) && right(v) }
+	}
+}
+
+func (p *queryParser) parseComparison() (jsonCond, error) {
+	p.skipSpaces()
+	if strings.HasPrefix(p.src[p.pos:], "!") && !strings.HasPrefix(p.src[p.pos:], "!=") {
+		p.pos++
+		cond, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		return func(v *JSONValue) bool { return !cond(v) }, nil
+	}
+	if p.consume("(") {
+		cond, err := p.parseCond()
+		if err != nil {
+			return nil, err
+		}
+		return cond, p.expect(")")
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	op := ""
+	for _, candidate := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		// Условие без сравнения проверяет наличие и истинность значения
+		return func(v *JSONValue) bool { return left(v).Truthy() }, nil
+	}
+	p.skipSpaces()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return func(v *JSONValue) bool { return compareJSON(left(v), op, right(v)) }, nil
+}
+
+// parseOperand разбирает путь относительно текущего значения (@.a в JSONPath, .a в jq) или литерал
+func (p *queryParser) parseOperand() (jsonOperand, error) {
+	switch c := p.peek(); {
+	case c == '@' && !p.jq, c == '.' && p.jq:
+		if c == '@' {
+			p.pos++
+		}
+		steps, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		return func(v *JSONValue) *JSONValue {
+			values := []*JSONValue{v}
+			for _, step := range steps {
+				var next []*JSONValue
+				for _, value := range values {
+					next = append(next, step(value)...)
+				}
+				values = next
+			}
+			if len(values) == 0 {
+				return nil
+			}
+			return values[0]
+		}, nil
+	case c == '"' || c == '\'':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		literal := &JSONValue{Kind: JSONString, Scalar: s}
+		return func(*JSONValue) *JSONValue { return literal }, nil
+	case c == '-' || c >= '0' && c <= '9':
+		start := p.pos
+		for p.pos++; !p.eof() && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0; p.pos++ {
+		}
+		number := p.src[start:p.pos]
+		if _, err := strconv.ParseFloat(number, 64); err != nil {
+			return nil, fmt.Errorf("позиция %d: неверное число %q", start+1, number)
+		}
+		literal := &JSONValue{Kind: JSONNumber, Scalar: number}
+		return func(*JSONValue) *JSONValue { return literal }, nil
+	}
+	for _, word := range []string{"true", "false", "null"} {
+		if p.consumeWord(word) {
+			literal := &JSONValue{Kind: JSONBool, Scalar: word}
+			if word == "null" {
+				literal = &JSONValue{Kind: JSONNull}
+			}
+			return func(*JSONValue) *JSONValue { return literal }, nil
+		}
+	}
+	return nil, p.errorf("ожидается путь, строка, число, true, false или null")
+}
+
+// --- Шаги ---
+
+func fieldStep(keys []string) jsonStep {
+	return func(v *JSONValue) []*JSONValue {
+		var result []*JSONValue
+		for _, key := range keys {
+			if field, ok := v.Field(key); ok {
+				result = append(result, field)
+			}
+		}
+		return result
+	}
+}
+
+// indexStep выбирает элементы массива; отрицательный индекс отсчитывается от конца
+func indexStep(indices []int) jsonStep {
+	return func(v *JSONValue) []*JSONValue {
+		if v.Kind != JSONArray {
+			return nil
+		}
+		var result []*JSONValue
+		for _, i := range indices {
+			if i < 0 {
+				i += v.Len()
+			}
+			if i >= 0 && i < v.Len() {
+				result = append(result, v.Items[i])
+			}
+		}
+		return result
+	}
+}
+
+// sliceStep выбирает элементы массива с from по to (не включая), как срезы Python.
+// В jq срез возвращает массив, в JSONPath - сами элементы.
+func sliceStep(from, to *int, asArray bool) jsonStep {
+	return func(v *JSONValue) []*JSONValue {
+		if v.Kind != JSONArray {
+			return nil
+		}
+		bound := func(i *int, fallback int) int {
+			if i == nil {
+				return fallback
+			}
+			n := *i
+			if n < 0 {
+				n += v.Len()
+			}
+			return min(max(n, 0), v.Len())
+		}
+		start, end := bound(from, 0), bound(to, v.Len())
+		items := v.Items[start:max(start, end)]
+		if asArray {
+			return []*JSONValue{{Kind: JSONArray, Items: items}}
+		}
+		return items
+	}
+}
+
+func childrenStep(v *JSONValue) []*JSONValue {
+	if !v.IsContainer() {
+		return nil
+	}
+	return v.Items
+}
+
+// descendantsStep возвращает значение и все вложенные в него значения
+func descendantsStep(v *JSONValue) []*JSONValue {
+	result := []*JSONValue{v}
+	for _, item := range childrenStep(v) {
+		result = append(result, descendantsStep(item)...)
+	}
+	return result
+}
+
+func filterStep(cond jsonCond) jsonStep {
+	return func(v *JSONValue) []*JSONValue {
+		var result []*JSONValue
+		for _, item := range childrenStep(v) {
+			if cond(item) {
+				result = append(result, item)
+			}
+		}
+		return result
+	}
+}
+
+// jqKeys возвращает отсортированные ключи объекта или индексы массива
+func jqKeys(v *JSONValue) []*JSONValue {
+	result := &JSONValue{Kind: JSONArray}
+	switch v.Kind {
+	case JSONObject:
+		keys := append([]string(nil), v.Keys...)
+		sort.Strings(keys)
+		for _, key := range keys {
+			result.Items = append(result.Items, &JSONValue{Kind: JSONString, Scalar: key})
+		}
+	case JSONArray:
+		for i := range v.Items {
+			result.Items = append(result.Items, &JSONValue{Kind: JSONNumber, Scalar: strconv.Itoa(i)})
+		}
+	default:
+		return nil
+	}
+	return []*JSONValue{result}
+}
+
+// jqLength возвращает длину массива, объекта или строки, модуль числа и 0 для null
+func jqLength(v *JSONValue) []*JSONValue {
+	var n string
+	switch v.Kind {
+	case JSONObject, JSONArray:
+		n = strconv.Itoa(v.Len())
+	case JSONString:
+		n = strconv.Itoa(utf8.RuneCountInString(v.Scalar))
+	case JSONNumber:
+		n = strings.TrimPrefix(v.Scalar, "-")
+	case JSONNull:
+		n = "0"
+	default:
+		return nil
+	}
+	return []*JSONValue{{Kind: JSONNumber, Scalar: n}}
+}
+
+// compareJSON сравнивает значения: числа - численно, строки - лексикографически,
+// остальные значения поддерживают только == и !=. Отсутствующее значение равно null.
+func compareJSON(a *JSONValue, op string, b *JSONValue) bool {
+	if a == nil {
+		a = &JSONValue{Kind: JSONNull}
+	}
+	if b == nil {
+		b = &JSONValue{Kind: JSONNull}
+	}
+	cmp, ordered := 0, false
+	if x, ok := a.Number(); ok {
+		if y, ok := b.Number(); ok {
+			cmp, ordered = compareFloats(x, y), true
+		}
+	}
+	if a.Kind == JSONString && b.Kind == JSONString {
+		cmp, ordered = strings.Compare(a.Scalar, b.Scalar), true
+	}
+	switch op {
+	case "==":
+		return ordered && cmp == 0 || !ordered && a.Kind == b.Kind && a.Format() == b.Format()
+	case "!=":
+		return !compareJSON(a, "==", b)
+	}
+	if !ordered {
+		return false
+	}
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func compareFloats(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
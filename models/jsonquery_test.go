@@ -0,0 +1,152 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+const queryDocument = `{
+	"store": {
+		"name": "Shop",
+		"items": [
+			{"id": 1, "name": "pen", "price": 5, "tags": ["office"], "active": true},
+			{"id": 2, "name": "book", "price": 12.5, "tags": [], "active": false},
+			{"id": 3, "name": "lamp", "price": 30, "active": true, "sale": null}
+		],
+		"owner": {"first-name": "Ann", "$ref": "u1"}
+	},
+	"count": -3,
+	"empty": {}
+}`
+
+func mustParseJSON(t *testing.T, input string) *JSONValue {
+	t.Helper()
+	root, err := ParseJSON(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// compactValues записывает найденные значения в одну строку через пробел
+func compactValues(values []*JSONValue) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = v.Compact()
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestQueryJSONPath(t *testing.T) {
+	root := mustParseJSON(t, queryDocument)
+	for _, tc := range []struct {
+		expr, want string
+	}{
+		{"$", `[{"store":{"name":"Shop","items":[{"id":1,"name":"pen","price":5,"tags":["office"],"active":true},{"id":2,"name":"book","price":12.5,"tags":[],"active":false},{"id":3,"name":"lamp","price":30,"active":true,"sale":null}],"owner":{"first-name":"Ann","$ref":"u1"}},"count":-3,"empty":{}}]`},
+		{"$.store.name", `["Shop"]`},
+		{"$.store.items[0].name", `["pen"]`},
+		{"$.store.items[-1].id", `[3]`},
+		{"$.store.items[0,2].id", `[1,3]`},
+		{"$.store.items[1:].id", `[2,3]`},
+		{"$.store.items[:-1].id", `[1,2]`},
+		{"$.store.items[*].tags[0]", `["office"]`},
+		{"$.store.items[].name", `["pen","book","lamp"]`},
+		{"$..id", `[1,2,3]`},
+		{"$.store.owner.first-name", `["Ann"]`},
+		{"$.store.owner.$ref", `["u1"]`},
+		{`$.store.owner["first-name"]`, `["Ann"]`},
+		{`$.store['name','missing']`, `["Shop"]`},
+		{`$.store.owner."first-name"`, `["Ann"]`},
+		{"$.empty.*", `[]`},
+		// Фильтры: сравнения, логические операторы, проверка наличия
+		{"$.store.items[?(@.price < 10)].name", `["pen"]`},
+		{"$.store.items[?(@.price >= 12.5 && @.active)].name", `["lamp"]`},
+		{"$.store.items[?(@.id == 1 || @.name == 'book')].id", `[1,2]`},
+		{"$.store.items[?(!@.active)].name", `["book"]`},
+		{"$.store.items[?(@.tags)].id", `[1,2]`},
+		{"$.store.items[?(@.sale == null)].id", `[1,2,3]`},
+		{`$.store.items[?(@.name != "pen")].id`, `[2,3]`},
+		// Несовместимые типы сравниваются только на равенство
+		{`$.store.items[?(@.price > "a")].id`, `[]`},
+		{`$.store.items[?(@.price == "5")].id`, `[]`},
+		{`$.store.items[?(@.name > "c")].name`, `["pen","lamp"]`},
+		// Отсутствующие пути и индексы не являются ошибкой
+		{"$.missing.path", `[]`},
+		{"$.store.items[10]", `[]`},
+		{"$.store.name[0]", `[]`},
+		{"$.count.field", `[]`},
+	} {
+		got, err := QueryJSON(root, tc.expr)
+		if err != nil {
+			t.Errorf("%s: ошибка %v", tc.expr, err)
+			continue
+		}
+		if len(got) != 1 || got[0].Compact() != tc.want {
+			t.Errorf("%s = %s, ожидалось %s", tc.expr, compactValues(got), tc.want)
+		}
+	}
+}
+
+func TestQueryJQ(t *testing.T) {
+	root := mustParseJSON(t, queryDocument)
+	for _, tc := range []struct {
+		expr, want string
+	}{
+		{"", compactValues([]*JSONValue{root})},
+		{".store.name", `"Shop"`},
+		{".store.items[] | .name", `"pen" "book" "lamp"`},
+		{".store.items[] | select(.active) | .id", `1 3`},
+		{".store.items[] | select(.price > 10 and .active == false) | .name", `"book"`},
+		{".store.items[] | select(.id == 1 or .id == 3) | .id", `1 3`},
+		{".store.items | length", `3`},
+		{".store.items | first | .name", `"pen"`},
+		{".store.items | last | .name", `"lamp"`},
+		{".store.items[1:] | length", `2`},
+		{".store.items[-1] | keys", `["active","id","name","price","sale"]`},
+		{".store.items[0].tags | keys", `[0]`},
+		{".store.name | length", `4`},
+		{".count | length", `3`},
+		{".store.items[2].sale | length", `0`},
+		{`.store.owner."first-name"`, `"Ann"`},
+		{".missing?", ``},
+		{".missing | length", ``},
+		{".", compactValues([]*JSONValue{root})},
+		// keys для скаляра не возвращает значений
+		{".store.name | keys", ``},
+	} {
+		got, err := QueryJSON(root, tc.expr)
+		if err != nil {
+			t.Errorf("%s: ошибка %v", tc.expr, err)
+			continue
+		}
+		if compactValues(got) != tc.want {
+			t.Errorf("%s = %s, ожидалось %s", tc.expr, compactValues(got), tc.want)
+		}
+	}
+}
+
+func TestQueryJSONErrors(t *testing.T) {
+	root := mustParseJSON(t, queryDocument)
+	for expr, want := range map[string]string{
+		"$.items[":                 "позиция 9: ожидается индекс",
+		"$.items[a]":               "позиция 9: ожидается индекс",
+		"$.items[0":                `позиция 10: ожидается "]"`,
+		"$.items[?(@.a == )]":      "ожидается путь, строка, число, true, false или null",
+		"$.items[?(@.a == 1e)]":    `неверное число "1e"`,
+		`$.items["a]`:              "незакрытая кавычка",
+		"$.a b":                    `позиция 5: неожиданный символ "b"`,
+		".items | unknown":         `неизвестное выражение "unknown"`,
+		".items |":                 "ожидается выражение",
+		".items[] | select(.a":     `ожидается ")"`,
+		".items[] | select(.a) x":  `неожиданный символ "x"`,
+		`$.items[?(@.a == "\q")]`:  "неверная строка",
+		".items | keysx":           `неизвестное выражение "keysx"`,
+		"$.items[?(@.a == 1":       `ожидается ")"`,
+		"$.items[?(@.a == 1 && )]": "ожидается путь",
+	} {
+		_, err := QueryJSON(root, expr)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: ошибка %v, ожидалось %q", expr, err, want)
+		}
+	}
+}
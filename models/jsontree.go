@@ -0,0 +1,85 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// jqIdentifier соответствует ключам, которые в пути jq записываются через точку
+var jqIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// jsonTreeLine представляет строку дерева JSON
+type jsonTreeLine struct {
+	text     string
+	path     string // путь узла в синтаксисе jq, пригодный для фильтра
+	foldKey  string // ключ узла в наборе свернутых узлов ("" для скалярных значений)
+	depth    int
+	isFolded bool
+}
+
+// buildJSONTree строит строки дерева значений. Узлы из folded показываются
+// одной строкой с количеством элементов.
+func buildJSONTree(roots []*JSONValue, folded map[string]bool) []jsonTreeLine {
+	var lines []jsonTreeLine
+	var walk func(v *JSONValue, label, path, foldKey string, depth int, comma bool)
+	walk = func(v *JSONValue, label, path, foldKey string, depth int, comma bool) {
+		indent := strings.Repeat("  ", depth)
+		suffix := ""
+		if comma {
+			suffix = ","
+		}
+		if !v.IsContainer() || v.Len() == 0 {
+			lines = append(lines, jsonTreeLine{text: indent + "  " + label + v.ScalarText() + suffix, path: path, depth: depth})
+			return
+		}
+
+		open, close := "[", "]"
+		if v.Kind == JSONObject {
+			open, close = "{", "}"
+		}
+		if folded[foldKey] {
+			lines = append(lines, jsonTreeLine{
+				text:     fmt.Sprintf("%s▸ %s%s…%s%s  (%d)", indent, label, open, close, suffix, v.Len()),
+				path:     path,
+				foldKey:  foldKey,
+				depth:    depth,
+				isFolded: true,
+			})
+			return
+		}
+		lines = append(lines, jsonTreeLine{text: indent + "▾ " + label + open, path: path, foldKey: foldKey, depth: depth})
+		for i, item := range v.Items {
+			childLabel, childPath := "", jqIndexPath(path, i)
+			if v.Kind == JSONObject {
+				childLabel, childPath = quoteJSON(v.Keys[i])+": ", jqKeyPath(path, v.Keys[i])
+			}
+			walk(item, childLabel, childPath, foldKey+childPath[len(path):], depth+1, i < v.Len()-1)
+		}
+		// Закрывающая скобка относится к тому же узлу: на ней узел тоже можно свернуть
+		lines = append(lines, jsonTreeLine{text: indent + "  " + close + suffix, path: path, foldKey: foldKey, depth: depth})
+	}
+	for i, root := range roots {
+		walk(root, "", ".", fmt.Sprintf("%d:.", i), 0, false)
+	}
+	return lines
+}
+
+// jqKeyPath возвращает путь jq к ключу объекта: .a.b или .a["b-c"]
+func jqKeyPath(parent, key string) string {
+	if parent == "." {
+		parent = ""
+	}
+	if jqIdentifier.MatchString(key) {
+		return parent + "." + key
+	}
+	if parent == "" {
+		parent = "."
+	}
+	return parent + "[" + quoteJSON(key) + "]"
+}
+
+// jqIndexPath возвращает путь jq к элементу массива: .items[0] или .[0]
+func jqIndexPath(parent string, index int) string {
+	return fmt.Sprintf("%s[%d]", parent, index)
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// JSONKind определяет тип значения JSON
+type JSONKind int
+
+const (
+	JSONNull JSONKind = iota
+	JSONBool
+	JSONNumber
+	JSONString
+	JSONArray
+	JSONObject
+)
+
+// JSONValue представляет разобранное значение JSON. В отличие от map[string]any
+// сохраняет порядок ключей объекта и исходную запись чисел.
+type JSONValue struct {
+	Kind   JSONKind
+	Scalar string       // строка, запись числа или true/false
+	Keys   []string     // ключи объекта в порядке документа
+	Items  []*JSONValue // элементы массива или значения объекта (в порядке Keys)
+}
+
+// ParseJSON разбирает документ JSON, сохраняя порядок ключей
+func ParseJSON(input string) (*JSONValue, error) {
+	dec := json.NewDecoder(strings.NewReader(input))
+	dec.UseNumber()
+	v, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("лишние данные после значения JSON")
+	}
+	return v, nil
+}
+
+func decodeJSONValue(dec *json.Decoder) (*JSONValue, error) {
+	token, err := dec.Token()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("пустой документ JSON")
+		}
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		v := &JSONValue{Kind: JSONArray}
+		if t == '{' {
+			v.Kind = JSONObject
+		}
+		for dec.More() {
+			if v.Kind == JSONObject {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				v.Keys = append(v.Keys, key.(string))
+			}
+			item, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			v.Items = append(v.Items, item)
+		}
+		// Закрывающая скобка
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case string:
+		return &JSONValue{Kind: JSONString, Scalar: t}, nil
+	case json.Number:
+		return &JSONValue{Kind: JSONNumber, Scalar: t.String()}, nil
+	case bool:
+		return &JSONValue{Kind: JSONBool, Scalar: strconv.FormatBool(t)}, nil
+	}
+	return &JSONValue{Kind: JSONNull}, nil
+}
+
+// Field возвращает значение ключа объекта
+func (v *JSONValue) Field(key string) (*JSONValue, bool) {
+	if v.Kind != JSONObject {
+		return nil, false
+	}
+	for i, k := range v.Keys {
+		if k == key {
+			return v.Items[i], true
+		}
+	}
+	return nil, false
+}
+
+// IsContainer сообщает, является ли значение объектом или массивом
+func (v *JSONValue) IsContainer() bool {
+	return v.Kind == JSONObject || v.Kind == JSONArray
+}
+
+// Len возвращает количество элементов массива или ключей объекта
+func (v *JSONValue) Len() int {
+	return len(v.Items)
+}
+
+// Number возвращает числовое значение
+func (v *JSONValue) Number() (float64, bool) {
+	if v.Kind != JSONNumber {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(v.Scalar, 64)
+	return f, err == nil
+}
+
+// Truthy сообщает, считается ли значение истинным (все, кроме null и false)
+func (v *JSONValue) Truthy() bool {
+	return v != nil && v.Kind != JSONNull && !(v.Kind == JSONBool && v.Scalar == "false")
+}
+
+// ScalarText возвращает запись скалярного значения в JSON
+func (v *JSONValue) ScalarText() string {
+	switch v.Kind {
+	case JSONNull:
+		return "null"
+	case JSONString:
+		return quoteJSON(v.Scalar)
+	case JSONObject:
+		return "{}"
+	case JSONArray:
+		return "[]"
+	}
+	return v.Scalar
+}
+
+// Format возвращает значение в виде JSON с отступами в два пробела
+func (v *JSONValue) Format() string {
+	var sb strings.Builder
+	v.format(&sb, "")
+	return sb.String()
+}
+
+// Compact возвращает значение в виде JSON одной строкой
+func (v *JSONValue) Compact() string {
+	if !v.IsContainer() || v.Len() == 0 {
+		return v.ScalarText()
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(v.Format())); err != nil {
+		return v.Format()
+	}
+	return buf.String()
+}
+
+func (v *JSONValue) format(sb *strings.Builder, indent string) {
+	if !v.IsContainer() || v.Len() == 0 {
+		sb.WriteString(v.ScalarText())
+		return
+	}
+	open, close := "[", "]"
+	if v.Kind == JSONObject {
+		open, close = "{", "}"
+	}
+	sb.WriteString(open + "\n")
+	for i, item := range v.Items {
+		sb.WriteString(indent + "  ")
+		if v.Kind == JSONObject {
+			sb.WriteString(quoteJSON(v.Keys[i]) + ": ")
+		}
+		item.format(sb, indent+"  ")
+		if i < len(v.Items)-1 {
+			sb.WriteString(",")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(indent + close)
+}
+
+// quoteJSON записывает строку в кавычках JSON без экранирования HTML символов
+func quoteJSON(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return fmt.Sprintf("%q", s)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,503 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+// LoadOptions задает параметры нагрузочного теста одного запроса
+type LoadOptions struct {
+	Concurrency int           // количество одновременно выполняемых запросов
+	Requests    int           // общее количество запросов (0 - без ограничения, до окончания Duration)
+	Duration    time.Duration // длительность теста (0 - до выполнения Requests запросов)
+	RPS         float64       // целевое количество запросов в секунду (0 - без ограничения)
+	FailOn      string        // диапазоны кодов ответа, считающиеся ошибкой ("" - не проверять)
+}
+
+// DefaultLoadOptions возвращает параметры нагрузочного теста по умолчанию
+func DefaultLoadOptions() LoadOptions {
+	return LoadOptions{Concurrency: 10, Requests: 100, FailOn: "400-599"}
+}
+
+// FormatLoadOptions записывает параметры теста в строку "name=value; name2=value2"
+func FormatLoadOptions(o LoadOptions) string {
+	failOn := o.FailOn
+	if failOn == "" {
+		failOn = "none"
+	}
+	return strings.Join([]string{
+		fmt.Sprintf("concurrency=%d", o.Concurrency),
+		fmt.Sprintf("requests=%d", o.Requests),
+		"duration=" + o.Duration.String(),
+		"rps=" + strconv.FormatFloat(o.RPS, 'f', -1, 64),
+		"fail_on=" + failOn,
+	}, "; ")
+}
+
+// ParseLoadOptions разбирает параметры теста из строки "name=value; name2=value2".
+// Незаданные параметры получают значения по умолчанию.
+func ParseLoadOptions(input string) (LoadOptions, error) {
+	o := DefaultLoadOptions()
+	for _, pair := range strings.Split(input, ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return o, fmt.Errorf("неверный параметр %q, ожидается name=value", strings.TrimSpace(pair))
+		}
+		name, value := strings.ToLower(strings.TrimSpace(parts[0])), strings.TrimSpace(parts[1])
+		var err error
+		switch name {
+		case "concurrency":
+			if o.Concurrency, err = strconv.Atoi(value); err != nil || o.Concurrency < 1 {
+				err = fmt.Errorf("concurrency: ожидается положительное число, получено %q", value)
+			}
+		case "requests":
+			if o.Requests, err = strconv.Atoi(value); err != nil || o.Requests < 0 {
+				err = fmt.Errorf("requests: ожидается неотрицательное число, получено %q", value)
+			}
+		case "duration":
+			if o.Duration, err = time.ParseDuration(value); err != nil || o.Duration < 0 {
+				err = fmt.Errorf("duration: ожидается длительность, например 30s или 1m, получено %q", value)
+			}
+		case "rps":
+			if o.RPS, err = strconv.ParseFloat(value, 64); err != nil || o.RPS < 0 || math.IsInf(o.RPS, 0) {
+				err = fmt.Errorf("rps: ожидается неотрицательное число, получено %q", value)
+			}
+		case "fail_on":
+			o.FailOn = value
+			if value == "none" || value == "" {
+				o.FailOn = ""
+			} else if _, err = ParseStatusRanges(value); err != nil {
+				err = fmt.Errorf("fail_on: %v", err)
+			}
+		default:
+			err = fmt.Errorf("неизвестный параметр %q", name)
+		}
+		if err != nil {
+			return o, err
+		}
+	}
+	return o, o.Validate()
+}
+
+// Validate проверяет, что тест когда-нибудь завершится
+func (o LoadOptions) Validate() error {
+	if o.Requests == 0 && o.Duration == 0 {
+		return fmt.Errorf("укажите количество запросов requests или длительность duration")
+	}
+	return nil
+}
+
+// FailRanges возвращает диапазоны кодов ответа, считающиеся ошибкой
+func (o LoadOptions) FailRanges() []StatusRange {
+	return failRanges(o.FailOn)
+}
+
+// LoadSample содержит результат одного запроса нагрузочного теста
+type LoadSample struct {
+	Latency time.Duration
+	Status  string // статус ответа, например "200 OK"
+	Code    int
+	Size    int64  // размер тела, полученного по сети
+	Error   string // ошибка выполнения запроса
+}
+
+// LoadBucket представляет интервал гистограммы задержек
+type LoadBucket struct {
+	From  time.Duration
+	To    time.Duration
+	Count int
+}
+
+// LoadCount содержит количество ответов с одинаковым статусом или ошибок с одинаковой причиной
+type LoadCount struct {
+	Name   string
+	Code   int // код ответа (0 для ошибок выполнения)
+	Count  int
+	Failed bool // ответ с этим кодом считается ошибкой
+}
+
+// LoadStats содержит ход и итоги нагрузочного теста
+type LoadStats struct {
+	LoadID    uint64 // Идентификатор теста, по которому ход сопоставляется с ним
+	Name      string // Путь запроса в коллекции
+	Method    string
+	URL       string
+	Options   LoadOptions
+	Started   time.Time
+	Elapsed   time.Duration
+	Completed int             // Запросы, получившие ответ или завершившиеся ошибкой
+	Failed    int             // Ошибки выполнения и ответы с кодом из fail_on
+	Bytes     int64           // Размер полученных тел ответов
+	Latencies []time.Duration // Время ответов (в снимке - по возрастанию)
+	Statuses  map[string]int  // Количество ответов по статусу
+	Errors    map[string]int  // Количество ошибок выполнения по причине
+	Canceled  bool            // Тест остановлен пользователем
+	Finished  bool
+}
+
+// NewLoadStats создает пустую статистику теста запроса
+func NewLoadStats(name, method, url string, options LoadOptions) LoadStats {
+	return LoadStats{
+		Name:     name,
+		Method:   method,
+		URL:      url,
+		Options:  options,
+		Started:  time.Now(),
+		Statuses: map[string]int{},
+		Errors:   map[string]int{},
+	}
+}
+
+// Add учитывает результат запроса
+func (s *LoadStats) Add(sample LoadSample, failRanges []StatusRange) {
+	s.Completed++
+	if sample.Error != "" {
+		s.Failed++
+		s.Errors[loadErrorReason(sample.Error)]++
+		return
+	}
+	if StatusInRanges(failRanges, sample.Code) {
+		s.Failed++
+	}
+	s.Bytes += sample.Size
+	s.Latencies = append(s.Latencies, sample.Latency)
+	s.Statuses[sample.Status]++
+}
+
+// Snapshot возвращает копию статистики с упорядоченным временем ответов,
+// которую можно передать интерфейсу, пока тест продолжает выполняться
+func (s *LoadStats) Snapshot() LoadStats {
+	snapshot := *s
+	snapshot.Elapsed = time.Since(s.Started)
+	snapshot.Latencies = slices.Clone(s.Latencies)
+	slices.Sort(snapshot.Latencies)
+	snapshot.Statuses = make(map[string]int, len(s.Statuses))
+	for status, count := range s.Statuses {
+		snapshot.Statuses[status] = count
+	}
+	snapshot.Errors = make(map[string]int, len(s.Errors))
+	for reason, count := range s.Errors {
+		snapshot.Errors[reason] = count
+	}
+	return snapshot
+}
+
+// loadErrorReason возвращает причину ошибки без адреса запроса и портов соединения,
+// чтобы одинаковые ошибки разных запросов учитывались вместе
+func loadErrorReason(message string) string {
+	if i := strings.LastIndex(message, ": "); i >= 0 {
+		return message[i+2:]
+	}
+	return message
+}
+
+// Percentile возвращает время ответа, которое не превышают p процентов запросов
+func (s LoadStats) Percentile(p float64) time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(s.Latencies))))
+	return s.Latencies[min(max(rank-1, 0), len(s.Latencies)-1)]
+}
+
+// MinLatency возвращает наименьшее время ответа
+func (s LoadStats) MinLatency() time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+	return s.Latencies[0]
+}
+
+// MaxLatency возвращает наибольшее время ответа
+func (s LoadStats) MaxLatency() time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+	return s.Latencies[len(s.Latencies)-1]
+}
+
+// MeanLatency возвращает среднее время ответа
+func (s LoadStats) MeanLatency() time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, l := range s.Latencies {
+		total += l
+	}
+	return total / time.Duration(len(s.Latencies))
+}
+
+// Throughput возвращает количество выполненных запросов в секунду
+func (s LoadStats) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Completed) / s.Elapsed.Seconds()
+}
+
+// BytesPerSecond возвращает скорость получения тел ответов
+func (s LoadStats) BytesPerSecond() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / s.Elapsed.Seconds()
+}
+
+// Histogram делит время ответов от наименьшего до наибольшего на count интервалов,
+// растущих в геометрической прогрессии, чтобы редкие долгие ответы не сжимали
+// основную часть распределения в один интервал
+func (s LoadStats) Histogram(count int) []LoadBucket {
+	if len(s.Latencies) == 0 || count < 1 {
+		return nil
+	}
+	low, high := max(s.MinLatency(), time.Microsecond), s.MaxLatency()
+	if low >= high {
+		return []LoadBucket{{From: s.MinLatency(), To: high, Count: len(s.Latencies)}}
+	}
+	ratio := math.Log(float64(high) / float64(low))
+	buckets := make([]LoadBucket, count)
+	for i := range buckets {
+		buckets[i].From = time.Duration(float64(low) * math.Exp(ratio*float64(i)/float64(count)))
+		buckets[i].To = time.Duration(float64(low) * math.Exp(ratio*float64(i+1)/float64(count)))
+	}
+	buckets[0].From, buckets[count-1].To = s.MinLatency(), high
+	for _, l := range s.Latencies {
+		i := 0
+		if l > low {
+			i = int(float64(count) * math.Log(float64(l)/float64(low)) / ratio)
+		}
+		buckets[min(i, count-1)].Count++
+	}
+	return buckets
+}
+
+// StatusCounts возвращает количество ответов по статусам в порядке кодов
+func (s LoadStats) StatusCounts() []LoadCount {
+	failRanges := s.Options.FailRanges()
+	counts := make([]LoadCount, 0, len(s.Statuses))
+	for status, count := range s.Statuses {
+		code, _ := strconv.Atoi(strings.Fields(status + " 0")[0])
+		counts = append(counts, LoadCount{Name: status, Code: code, Count: count, Failed: StatusInRanges(failRanges, code)})
+	}
+	slices.SortFunc(counts, func(a, b LoadCount) int {
+		if a.Code != b.Code {
+			return a.Code - b.Code
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return counts
+}
+
+// ErrorCounts возвращает количество ошибок выполнения по причинам, начиная с частых
+func (s LoadStats) ErrorCounts() []LoadCount {
+	counts := make([]LoadCount, 0, len(s.Errors))
+	for reason, count := range s.Errors {
+		counts = append(counts, LoadCount{Name: reason, Count: count, Failed: true})
+	}
+	slices.SortFunc(counts, func(a, b LoadCount) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return counts
+}
+
+// progress возвращает долю выполненной части теста по количеству запросов или времени
+func (s LoadStats) progress() float64 {
+	done := 0.0
+	if s.Options.Requests > 0 {
+		done = float64(s.Completed) / float64(s.Options.Requests)
+	}
+	if s.Options.Duration > 0 {
+		done = max(done, float64(s.Elapsed)/float64(s.Options.Duration))
+	}
+	if s.Finished && !s.Canceled {
+		done = 1
+	}
+	return min(done, 1)
+}
+
+// FormatLoadStats возвращает ход или итоги теста: полосу выполнения, пропускную
+// способность, перцентили, гистограмму задержек, коды ответа и ошибки выполнения
+func FormatLoadStats(s LoadStats, width int) string {
+	var sb strings.Builder
+	limits := []string{fmt.Sprintf("параллельно: %d", s.Options.Concurrency)}
+	if s.Options.Requests > 0 {
+		limits = append(limits, fmt.Sprintf("запросов: %d", s.Options.Requests))
+	}
+	if s.Options.Duration > 0 {
+		limits = append(limits, "длительность: "+s.Options.Duration.String())
+	}
+	if s.Options.RPS > 0 {
+		limits = append(limits, "rps: "+strconv.FormatFloat(s.Options.RPS, 'f', -1, 64))
+	}
+	fmt.Fprintf(&sb, "Нагрузка: %s %s (%s)\n", s.Method, s.Name, strings.Join(limits, ", "))
+
+	barWidth := max(10, min(width-30, 50))
+	done := int(float64(barWidth) * s.progress())
+	fmt.Fprintf(&sb, "%s%s %d  ✗ %d  %s\n\n", strings.Repeat("█", done), strings.Repeat("░", barWidth-done),
+		s.Completed, s.Failed, s.Elapsed.Round(time.Millisecond))
+
+	fmt.Fprintf(&sb, "Пропускная способность: %.1f запр/с, %s/с\n", s.Throughput(), formatRate(s.BytesPerSecond()))
+	if len(s.Latencies) > 0 {
+		fmt.Fprintf(&sb, "Время ответа: мин %s  p50 %s  p90 %s  p99 %s  макс %s  среднее %s\n",
+			formatDuration(s.MinLatency()), formatDuration(s.Percentile(50)), formatDuration(s.Percentile(90)),
+			formatDuration(s.Percentile(99)), formatDuration(s.MaxLatency()), formatDuration(s.MeanLatency()))
+
+		sb.WriteString("\nГистограмма времени ответа:\n")
+		buckets := s.Histogram(10)
+		largest := 0
+		for _, b := range buckets {
+			largest = max(largest, b.Count)
+		}
+		histWidth := max(10, min(width-40, 50))
+		for _, b := range buckets {
+			length := histWidth * b.Count / largest
+			if b.Count > 0 && length == 0 {
+				length = 1
+			}
+			fmt.Fprintf(&sb, "%10s - %-10s │%s %d\n", formatDuration(b.From), formatDuration(b.To), strings.Repeat("█", length), b.Count)
+		}
+	}
+
+	if statuses := s.StatusCounts(); len(statuses) > 0 {
+		sb.WriteString("\nКоды ответа:\n")
+		for _, c := range statuses {
+			fmt.Fprintf(&sb, "%s %s: %d\n", resultMark(!c.Failed), c.Name, c.Count)
+		}
+	}
+	if errors := s.ErrorCounts(); len(errors) > 0 {
+		sb.WriteString("\nОшибки выполнения:\n")
+		for _, c := range errors {
+			fmt.Fprintf(&sb, "✗ %s: %d\n", c.Name, c.Count)
+		}
+	}
+
+	switch {
+	case !s.Finished:
+		sb.WriteString("\n⏳ Выполняется... (ctrl+x: остановить)")
+	case s.Canceled:
+		fmt.Fprintf(&sb, "\nТест остановлен: выполнено запросов %d, неуспешно %d", s.Completed, s.Failed)
+	default:
+		fmt.Fprintf(&sb, "\nТест завершен: выполнено запросов %d, неуспешно %d", s.Completed, s.Failed)
+	}
+	return sb.String()
+}
+
+// resultMark возвращает отметку успешного или неуспешного результата
+func resultMark(passed bool) string {
+	if passed {
+		return "✓"
+	}
+	return "✗"
+}
+
+// formatRate форматирует скорость в байтах в секунду без точного значения в байтах
+func formatRate(bytesPerSecond float64) string {
+	switch {
+	case bytesPerSecond < 1024:
+		return fmt.Sprintf("%.0f Б", bytesPerSecond)
+	case bytesPerSecond < 1024*1024:
+		return fmt.Sprintf("%.1f КБ", bytesPerSecond/1024)
+	default:
+		return fmt.Sprintf("%.1f МБ", bytesPerSecond/(1024*1024))
+	}
+}
+
+// --- Нагрузочный тест в интерфейсе ---
+
+// SelectLoadTarget выбирает для теста запрос, выделенный на вкладке "Сохраненные"
+func (m *AppModel) SelectLoadTarget() bool {
+	item, ok := m.GetSelectedTreeItem()
+	if !ok || item.IsFolder() {
+		return false
+	}
+	m.loadTarget = item.Path
+	return true
+}
+
+// LoadTargetName возвращает путь тестируемого запроса
+func (m *AppModel) LoadTargetName() string {
+	return m.loadTarget
+}
+
+// LoadRequest возвращает тестируемый запрос с примененным наследованием папок
+func (m *AppModel) LoadRequest() (SavedRequest, error) {
+	if m.loadTarget == "" {
+		return SavedRequest{}, fmt.Errorf("выберите запрос на вкладке \"Сохраненные\"")
+	}
+	return m.collection.FindRequest(m.loadTarget)
+}
+
+func (m *AppModel) GetLoadOptions() LoadOptions {
+	return m.loadOptions
+}
+
+func (m *AppModel) SetLoadOptions(options LoadOptions) {
+	m.loadOptions = options
+}
+
+// StartLoad начинает показ хода теста с указанным идентификатором на вкладке "Нагрузка"
+func (m *AppModel) StartLoad(stats LoadStats) {
+	m.load = stats
+	m.activeTab = TabLoad
+	m.renderLoadContent()
+}
+
+// UpdateLoad показывает очередной снимок статистики теста или его итоги
+func (m *AppModel) UpdateLoad(stats LoadStats) {
+	if stats.LoadID != m.load.LoadID || m.load.Finished {
+		return
+	}
+	m.load = stats
+	m.renderLoadContent()
+	if stats.Finished && m.activeTab != TabLoad {
+		m.notice = fmt.Sprintf("Нагрузочный тест %s завершен: %.1f запр/с, p99 %s, неуспешно %d из %d",
+			stats.Name, stats.Throughput(), formatDuration(stats.Percentile(99)), stats.Failed, stats.Completed)
+	}
+}
+
+// IsLoadRunning сообщает, выполняется ли нагрузочный тест
+func (m *AppModel) IsLoadRunning() bool {
+	return m.load.LoadID != 0 && !m.load.Finished
+}
+
+func (m *AppModel) GetLoadStats() LoadStats {
+	return m.load
+}
+
+func (m *AppModel) GetLoadVP() *viewport.Model {
+	return &m.loadVP
+}
+
+func (m *AppModel) GetLoadInput() *textinput.Model {
+	return &m.loadInput
+}
+
+// IsLoadPrompt сообщает, вводятся ли параметры нагрузочного теста
+func (m *AppModel) IsLoadPrompt() bool {
+	return m.isLoadPrompt
+}
+
+func (m *AppModel) SetIsLoadPrompt(prompt bool) {
+	m.isLoadPrompt = prompt
+}
+
+// renderLoadContent показывает ход теста в области вкладки "Нагрузка"
+func (m *AppModel) renderLoadContent() {
+	lines := strings.Split(FormatLoadStats(m.load, m.loadVP.Width), "\n")
+	colorResultMarks(lines)
+	m.loadVP.SetContent(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loadStatsWith возвращает снимок статистики с временем ответов в миллисекундах
+func loadStatsWith(ms ...int) LoadStats {
+	stats := NewLoadStats("r", "GET", "http://x", DefaultLoadOptions())
+	for _, m := range ms {
+		stats.Add(LoadSample{Latency: time.Duration(m) * time.Millisecond, Status: "200 OK", Code: 200}, nil)
+	}
+	return stats.Snapshot()
+}
+
+func TestLoadStatsPercentile(t *testing.T) {
+	hundred := make([]int, 100)
+	for i := range hundred {
+		// Время добавляется не по порядку, снимок его сортирует
+		hundred[i] = 100 - i
+	}
+	for _, tc := range []struct {
+		stats LoadStats
+		p     float64
+		want  time.Duration
+	}{
+		{loadStatsWith(hundred...), 50, 50 * time.Millisecond},
+		{loadStatsWith(hundred...), 90, 90 * time.Millisecond},
+		{loadStatsWith(hundred...), 99, 99 * time.Millisecond},
+		{loadStatsWith(hundred...), 99.5, 100 * time.Millisecond},
+		{loadStatsWith(hundred...), 100, 100 * time.Millisecond},
+		{loadStatsWith(hundred...), 0, time.Millisecond},
+		// Ближайший ранг: перцентиль - одно из измеренных значений
+		{loadStatsWith(30, 10, 20), 50, 20 * time.Millisecond},
+		{loadStatsWith(30, 10, 20), 67, 30 * time.Millisecond},
+		{loadStatsWith(30, 10, 20), 99, 30 * time.Millisecond},
+		{loadStatsWith(7), 50, 7 * time.Millisecond},
+		{loadStatsWith(), 50, 0},
+	} {
+		if got := tc.stats.Percentile(tc.p); got != tc.want {
+			t.Errorf("p%v из %d значений = %v, ожидалось %v", tc.p, len(tc.stats.Latencies), got, tc.want)
+		}
+	}
+
+	stats := loadStatsWith(10, 40, 20, 30)
+	if stats.MinLatency() != 10*time.Millisecond || stats.MaxLatency() != 40*time.Millisecond || stats.MeanLatency() != 25*time.Millisecond {
+		t.Errorf("мин %v, макс %v, среднее %v", stats.MinLatency(), stats.MaxLatency(), stats.MeanLatency())
+	}
+}
+
+func TestLoadStatsAdd(t *testing.T) {
+	stats := NewLoadStats("r", "GET", "http://x", LoadOptions{FailOn: "5xx"})
+	failRanges := stats.Options.FailRanges()
+	for _, s := range []LoadSample{
+		{Latency: time.Millisecond, Status: "200 OK", Code: 200, Size: 10},
+		{Latency: time.Millisecond, Status: "503 Service Unavailable", Code: 503, Size: 5},
+		{Latency: time.Millisecond, Status: "200 OK", Code: 200, Size: 10},
+		{Latency: time.Second, Error: `Get "http://x": dial tcp 127.0.0.1:1234: connect: connection refused`},
+		{Latency: time.Second, Error: `Get "http://x": dial tcp 127.0.0.1:5678: connect: connection refused`},
+		{Latency: time.Second, Error: "context deadline exceeded"},
+	} {
+		stats.Add(s, failRanges)
+	}
+	if stats.Completed != 6 || stats.Failed != 4 || stats.Bytes != 25 || len(stats.Latencies) != 3 {
+		t.Errorf("выполнено %d, неуспешно %d, байт %d, времен %d", stats.Completed, stats.Failed, stats.Bytes, len(stats.Latencies))
+	}
+	wantStatuses := []LoadCount{
+		{Name: "200 OK", Code: 200, Count: 2},
+		{Name: "503 Service Unavailable", Code: 503, Count: 1, Failed: true},
+	}
+	if got := stats.StatusCounts(); !reflect.DeepEqual(got, wantStatuses) {
+		t.Errorf("коды ответа = %+v", got)
+	}
+	// Ошибки с разными адресами соединений учитываются вместе
+	wantErrors := []LoadCount{
+		{Name: "connection refused", Count: 2, Failed: true},
+		{Name: "context deadline exceeded", Count: 1, Failed: true},
+	}
+	if got := stats.ErrorCounts(); !reflect.DeepEqual(got, wantErrors) {
+		t.Errorf("ошибки = %+v", got)
+	}
+}
+
+func TestLoadStatsHistogram(t *testing.T) {
+	stats := loadStatsWith(1, 1, 2, 4, 8, 16, 1000)
+	buckets := stats.Histogram(10)
+	if len(buckets) != 10 || buckets[0].From != time.Millisecond || buckets[9].To != time.Second {
+		t.Fatalf("интервалы = %+v", buckets)
+	}
+	total := 0
+	for i, b := range buckets {
+		total += b.Count
+		if i > 0 && b.From != buckets[i-1].To {
+			t.Errorf("интервал %d начинается с %v, предыдущий заканчивается на %v", i, b.From, buckets[i-1].To)
+		}
+	}
+	if total != 7 || buckets[9].Count != 1 || buckets[0].Count != 2 {
+		t.Errorf("количество по интервалам = %+v", buckets)
+	}
+
+	if same := loadStatsWith(5, 5).Histogram(10); len(same) != 1 || same[0].Count != 2 {
+		t.Errorf("одинаковое время: %+v", same)
+	}
+}
+
+func TestParseLoadOptions(t *testing.T) {
+	o, err := ParseLoadOptions("concurrency=4; requests=0; duration=30s; rps=2.5; fail_on=none")
+	want := LoadOptions{Concurrency: 4, Duration: 30 * time.Second, RPS: 2.5}
+	if err != nil || o != want {
+		t.Errorf("параметры = %+v, %v; ожидалось %+v", o, err, want)
+	}
+	if again, err := ParseLoadOptions(FormatLoadOptions(o)); err != nil || again != o {
+		t.Errorf("повторный разбор = %+v, %v", again, err)
+	}
+	for input, wantErr := range map[string]string{
+		"concurrency=0":           "concurrency",
+		"requests=-1":             "requests",
+		"duration=soon":           "duration",
+		"rps=Inf":                 "rps",
+		"fail_on=abc":             "fail_on",
+		"speed=1":                 `неизвестный параметр "speed"`,
+		"requests":                "ожидается name=value",
+		"requests=0; duration=0s": "укажите количество запросов",
+	} {
+		if _, err := ParseLoadOptions(input); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("%s: ошибка %v, ожидалось %q", input, err, wantErr)
+		}
+	}
+}
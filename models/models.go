@@ -4,15 +4,17 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
-	"github.com/charmbracelet/lipgloss"
 )
 
 // Tab представляет активную вкладку в интерфейсе
@@ -22,8 +24,15 @@ const (
 	TabRequest Tab = iota
 	TabResponse
 	TabSaved
+	TabHistory
+	TabCookies
+	TabRunner
+	TabLoad
 )
 
+// TabCount количество вкладок интерфейса
+const TabCount = 7
+
 // Section представляет различные секции интерфейса
 type Section int
 
@@ -33,6 +42,38 @@ const (
 	SectionHeaders
 	SectionBody
 	SectionParams
+	SectionAuth
+)
+
+// SectionCount количество секций вкладки "Запрос"
+const SectionCount = 6
+
+// FolderPrompt определяет, какое значение папки вводится на вкладке "Сохраненные"
+type FolderPrompt int
+
+const (
+	FolderPromptNone FolderPrompt = iota
+	FolderPromptCreate
+	FolderPromptBaseURL
+	FolderPromptHeaders
+)
+
+// SettingsPrompt определяет, какие настройки клиента редактируются
+type SettingsPrompt int
+
+const (
+	SettingsPromptNone    SettingsPrompt = iota
+	SettingsPromptRequest                // настройки запроса активной вкладки
+	SettingsPromptGlobal                 // общие настройки
+)
+
+// CookiePrompt определяет, вводится ли новая cookie или изменяется выбранная
+type CookiePrompt int
+
+const (
+	CookiePromptNone CookiePrompt = iota
+	CookiePromptCreate
+	CookiePromptEdit
 )
 
 // HTTPMethod представляет доступные HTTP методы
@@ -50,6 +91,22 @@ const (
 
 var MethodNames = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
 
+// ParseMethod возвращает HTTP метод по имени (GET, если метод неизвестен)
+func ParseMethod(name string) HTTPMethod {
+	method, _ := LookupMethod(name)
+	return method
+}
+
+// LookupMethod возвращает HTTP метод по имени и сообщает, поддерживается ли он
+func LookupMethod(name string) (HTTPMethod, bool) {
+	for i, method := range MethodNames {
+		if strings.EqualFold(method, name) {
+			return HTTPMethod(i), true
+		}
+	}
+	return MethodGET, false
+}
+
 // --- Структуры данных ---
 
 type Param struct {
@@ -64,12 +121,18 @@ type Header struct {
 
 // SavedRequest определяет структуру для сохранения запроса в JSON
 type SavedRequest struct {
-	Name    string     `json:"name"`
-	Method  HTTPMethod `json:"method"`
-	URL     string     `json:"url"`
-	Body    string     `json:"body"`
-	Headers []Header   `json:"headers"`
-	Params  []Param    `json:"params"`
+	Name       string         `json:"name"`
+	Method     HTTPMethod     `json:"method"`
+	URL        string         `json:"url"`
+	Body       string         `json:"body"`
+	BodyType   BodyType       `json:"bodyType,omitempty"`
+	Form       []FormField    `json:"form,omitempty"` // Поля тела form и multipart
+	Headers    []Header       `json:"headers"`
+	Params     []Param        `json:"params"`
+	Auth       Auth           `json:"auth,omitzero"`
+	Settings   ClientSettings `json:"settings,omitzero"`    // Настройки клиента, переопределяющие общие
+	Assertions []Assertion    `json:"assertions,omitempty"` // Проверки ответа
+	Extract    []Extraction   `json:"extract,omitempty"`    // Переменные, извлекаемые из ответа
 }
 
 // Implement list.Item interface for SavedRequest
@@ -80,45 +143,105 @@ func (sr SavedRequest) Description() string {
 func (sr SavedRequest) FilterValue() string { return sr.Name }
 
 type ResponseData struct {
-	Body       string
-	Status     string
-	Time       string
-	StatusCode int
+	RequestID   uint64 // Идентификатор отправки, по которому ответ сопоставляется с запросом
+	Body        string // Тело ответа после распаковки (не больше лимита max_body) в UTF-8
+	BodyFile    string // Временный файл с полным телом, если оно больше лимита
+	Binary      bool   // Тело не является текстом
+	Charset     string // Кодировка из Content-Type, из которой тело перекодировано в UTF-8
+	Status      string
+	Time        string
+	StatusCode  int
+	Proto       string // Версия протокола, например HTTP/2.0
+	URL         string // Итоговый адрес после перенаправлений
+	Headers     []Header
+	Cookies     []Cookie
+	Size        int64  // Размер тела, полученного по сети
+	DecodedSize int64  // Размер тела после распаковки
+	Encoding    string // Content-Encoding ответа
+	Redirects   []RedirectHop
+	Timings     Timings
+	Timestamp   time.Time // Время начала запроса
+	Request     RequestSnapshot
+	Settings    ClientSettings     // Настройки клиента, с которыми выполнен запрос
+	Assertions  []AssertionResult  // Результаты проверок ответа
+	Extracted   []ExtractionResult // Значения переменных, извлеченные из ответа
 }
 
 type ErrorData struct {
-	Message string
+	RequestID uint64
+	Message   string
+	Canceled  bool // Запрос отменен пользователем
+	Request   RequestSnapshot
 }
 
 // AppModel представляет основное состояние приложения
 type AppModel struct {
+	// Активная вкладка запроса
+	*RequestSession
+	sessions []*RequestSession
+
 	// Компоненты
-	urlInput      textinput.Model
-	bodyInput     textarea.Model
-	responseVP    viewport.Model
-	paramInput    textinput.Model
-	headerInput   textinput.Model
-	savedList     list.Model
-	saveNameInput textinput.Model
+	responseVP     viewport.Model
+	paramInput     textinput.Model
+	headerInput    textinput.Model
+	savedList      list.Model
+	historyList    list.Model
+	cookieList     list.Model
+	saveNameInput  textinput.Model
+	importInput    textinput.Model
+	folderInput    textinput.Model
+	settingsInput  textinput.Model
+	cookieInput    textinput.Model
+	responseInput  textinput.Model // поиск и фильтр на вкладке "Ответ"
+	assertionInput textinput.Model // проверки ответа запроса активной вкладки
+	extractInput   textinput.Model // правила извлечения переменных из ответа
+	runVP          viewport.Model  // ход и итоги запуска папки или коллекции
+	runInput       textinput.Model // параметры запуска
+	loadVP         viewport.Model  // ход и итоги нагрузочного теста
+	loadInput      textinput.Model // параметры нагрузочного теста
 
 	// Данные
-	params        []Param
-	headers       []Header
-	savedRequests []list.Item // []SavedRequest
-	configPath    string
+	history        []list.Item // []HistoryEntry
+	historyErr     error       // ошибка загрузки history.json
+	store          RequestStore
+	storeErr       error
+	collection     Collection
+	expanded       map[*Folder]bool // развернутые папки вкладки "Сохраненные"
+	treeShowAll    bool             // дерево показывается полностью (во время фильтрации)
+	marked         *TreeItem        // запрос, отмеченный для перемещения
+	environments   EnvironmentSet
+	envErr         error             // ошибка загрузки environments.json
+	runtimeVars    map[string]string // переменные, извлеченные из ответов без активного окружения
+	clientSettings ClientSettings    // общие настройки HTTP клиента
+	cookies        *CookieStore      // cookies окружений
+	runTarget      string            // путь запускаемой папки ("" - вся коллекция)
+	runOptions     RunOptions        // параметры последнего запуска
+	run            RunReport         // текущий или последний запуск
+	loadTarget     string            // путь запроса нагрузочного теста
+	loadOptions    LoadOptions       // параметры последнего нагрузочного теста
+	load           LoadStats         // текущий или последний нагрузочный тест
 
 	// Состояние
 	activeTab      Tab
 	activeSection  Section
-	selectedMethod HTTPMethod
-	loading        bool
-	response       string
-	status         string
-	responseTime   string
-	errorMsg       string
+	responseView   ResponseView
+	notice         string
 	inputMode      bool
 	isSaving       bool
 	isDeleting     bool
+	isClosing      bool
+	isImporting    bool
+	isAsserting    bool // редактируются проверки ответа
+	isExtracting   bool // редактируются правила извлечения переменных
+	isRunPrompt    bool // вводятся параметры запуска
+	isLoadPrompt   bool // вводятся параметры нагрузочного теста
+	folderPrompt   FolderPrompt
+	settingsPrompt SettingsPrompt
+	cookiePrompt   CookiePrompt
+	responsePrompt ResponsePrompt
+	editedCookie   *StoredCookie // cookie, изменяемая в поле cookieInput (nil - новая)
+	preview        string
+	previewTitle   string
 
 	// Размеры
 	width  int
@@ -128,15 +251,11 @@ type AppModel struct {
 // --- Инициализация ---
 
 func NewAppModel() *AppModel {
-	urlInput := textinput.New()
-	urlInput.Placeholder = "https://api.example.com/endpoint"
-	urlInput.CharLimit = 500
-
-	bodyInput := textarea.New()
-	bodyInput.Placeholder = "{\"key\": \"value\"}"
-	bodyInput.ShowLineNumbers = false
-	bodyInput.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	return NewAppModelWithStore(NewJSONStore())
+}
 
+// NewAppModelWithStore создает модель приложения с указанным хранилищем запросов
+func NewAppModelWithStore(store RequestStore) *AppModel {
 	responseVP := viewport.New(10, 10)
 
 	paramInput := textinput.New()
@@ -151,35 +270,105 @@ func NewAppModel() *AppModel {
 	saveNameInput.Placeholder = "My Awesome Request"
 	saveNameInput.CharLimit = 100
 
+	importInput := textinput.New()
+	importInput.Placeholder = "collection.json, openapi.yaml, session.har, requests.http"
+	importInput.CharLimit = 1024
+
+	folderInput := textinput.New()
+	folderInput.CharLimit = 1024
+
+	settingsInput := textinput.New()
+	settingsInput.Placeholder = "timeout=30s; redirects=true; max_redirects=10; insecure=false; proxy=socks5://127.0.0.1:1080"
+	settingsInput.CharLimit = 2048
+
 	savedList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
 	savedList.Title = "Сохраненные запросы"
 	savedList.SetShowStatusBar(false)
 
-	configPath, _ := getConfigPath()
+	historyList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	historyList.Title = "История запросов"
+	historyList.SetShowStatusBar(false)
+
+	cookieList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	cookieList.Title = "Cookies"
+	cookieList.SetShowStatusBar(false)
+	cookieList.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "новая")),
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "изменить")),
+			key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "удалить")),
+			key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "удалить домен")),
+		}
+	}
+
+	cookieInput := textinput.New()
+	cookieInput.Placeholder = "name=value; Domain=example.com; Path=/; Expires=Mon, 02 Jan 2030 15:04:05 GMT; Secure; HttpOnly"
+	cookieInput.CharLimit = 4096
+
+	responseInput := textinput.New()
+	responseInput.CharLimit = 1024
 
+	assertionInput := textinput.New()
+	assertionInput.Placeholder = AssertionPlaceholder
+	assertionInput.CharLimit = 4096
+
+	extractInput := textinput.New()
+	extractInput.Placeholder = ExtractionPlaceholder
+	extractInput.CharLimit = 4096
+
+	runInput := textinput.New()
+	runInput.Placeholder = "iterations=3; delay=500ms; stop=true; fail_on=400-599; data=users.csv"
+	runInput.CharLimit = 1024
+
+	loadInput := textinput.New()
+	loadInput.Placeholder = "concurrency=20; requests=1000; duration=30s; rps=100; fail_on=500-599"
+	loadInput.CharLimit = 1024
+
+	session := newRequestSession()
 	m := &AppModel{
-		urlInput:       urlInput,
-		bodyInput:      bodyInput,
+		RequestSession: session,
+		sessions:       []*RequestSession{session},
 		responseVP:     responseVP,
 		paramInput:     paramInput,
 		headerInput:    headerInput,
 		savedList:      savedList,
+		historyList:    historyList,
+		cookieList:     cookieList,
+		cookieInput:    cookieInput,
 		saveNameInput:  saveNameInput,
-		params:         []Param{},
-		headers:        []Header{{Key: "Content-Type", Value: "application/json"}},
-		configPath:     configPath,
+		importInput:    importInput,
+		folderInput:    folderInput,
+		settingsInput:  settingsInput,
+		responseInput:  responseInput,
+		assertionInput: assertionInput,
+		extractInput:   extractInput,
+		runVP:          viewport.New(10, 10),
+		runInput:       runInput,
+		runOptions:     DefaultRunOptions(),
+		loadVP:         viewport.New(10, 10),
+		loadInput:      loadInput,
+		loadOptions:    DefaultLoadOptions(),
+		store:          store,
 		activeTab:      TabRequest,
 		activeSection:  SectionMethod,
-		selectedMethod: MethodGET,
 	}
 
 	m.loadRequests()
+	m.loadHistory()
+	m.loadWorkspace()
+	m.loadEnvironments()
+	m.clientSettings, _ = LoadClientSettings()
+	var err error
+	if m.cookies, err = LoadCookieStore(); err != nil {
+		m.addNotice("Ошибка: cookies не загружены: " + err.Error())
+	}
+	m.refreshCookieList()
 	return m
 }
 
 // --- Логика Сохранения/Загрузки ---
 
-func getConfigPath() (string, error) {
+func getConfigDir() (string, error) {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
 		return "", err
@@ -188,80 +377,303 @@ func getConfigPath() (string, error) {
 	if err := os.MkdirAll(postuiDir, 0750); err != nil {
 		return "", err
 	}
-	return filepath.Join(postuiDir, "requests.json"), nil
+	return postuiDir, nil
+}
+
+func getConfigPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "requests.json"), nil
 }
 
 func (m *AppModel) loadRequests() error {
-	data, err := ioutil.ReadFile(m.configPath)
+	collection, err := m.store.Load()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
+		// Не перезаписываем файл, который не удалось прочитать
+		m.storeErr = err
 		return err
 	}
+	m.collection = collection
+	m.expanded = map[*Folder]bool{}
+	for _, s := range m.sessions {
+		s.requestFolder = nil
+	}
+	m.marked = nil
+	if collection.Name != "" {
+		m.savedList.Title = collection.Name
+	}
+	m.refreshSavedTree()
+	return nil
+}
 
-	var savedRequests []SavedRequest
-	if err := json.Unmarshal(data, &savedRequests); err != nil {
-		return err
+func (m *AppModel) saveRequests() error {
+	if m.storeErr != nil {
+		return m.storeErr
+	}
+	return m.store.Save(m.collection)
+}
+
+// refreshSavedTree перестраивает дерево вкладки "Сохраненные", сохраняя позицию курсора.
+// Примененный фильтр сбрасывается, так как его результаты ссылаются на старые элементы.
+func (m *AppModel) refreshSavedTree() {
+	if m.savedList.FilterState() != list.Unfiltered {
+		m.savedList.ResetFilter()
 	}
+	m.treeShowAll = false
+	index := m.savedList.Index()
+	m.savedList.SetItems(m.treeItems())
+	if count := len(m.savedList.Items()); index >= count {
+		index = count - 1
+	}
+	if index >= 0 {
+		m.savedList.Select(index)
+	}
+}
 
-	items := make([]list.Item, len(savedRequests))
-	for i, sr := range savedRequests {
-		items[i] = sr
+func (m *AppModel) treeItems() []list.Item {
+	tree := buildTree(&m.collection.Folder, m.expanded, m.treeShowAll)
+	items := make([]list.Item, len(tree))
+	for i, item := range tree {
+		items[i] = item
 	}
+	return items
+}
 
-	m.savedRequests = items
-	m.savedList.SetItems(m.savedRequests)
-	return nil
+// ShowAllSavedRequests показывает содержимое всех папок перед началом фильтрации
+func (m *AppModel) ShowAllSavedRequests() {
+	m.treeShowAll = true
+	m.savedList.SetItems(m.treeItems())
 }
 
-func (m *AppModel) saveRequests() error {
-	savedRequests := make([]SavedRequest, len(m.savedRequests))
-	for i, item := range m.savedRequests {
-		savedRequests[i] = item.(SavedRequest)
+// SyncSavedTree возвращает дерево со свернутыми папками после сброса фильтра
+func (m *AppModel) SyncSavedTree() {
+	if m.treeShowAll && m.savedList.FilterState() == list.Unfiltered {
+		m.refreshSavedTree()
+	}
+}
+
+// CurrentRequest возвращает текущий запрос из полей вкладки "Запрос"
+func (m *AppModel) CurrentRequest() SavedRequest {
+	return m.request()
+}
+
+// ResolvedRequest возвращает текущий запрос с базовым URL и заголовками,
+// унаследованными от папки, из которой он был загружен
+func (m *AppModel) ResolvedRequest() SavedRequest {
+	return m.collection.DefaultsFor(m.currentFolder()).Apply(m.CurrentRequest())
+}
+
+// currentFolder возвращает папку, из которой загружен текущий запрос (по умолчанию корень)
+func (m *AppModel) currentFolder() *Folder {
+	if m.requestFolder != nil {
+		return m.requestFolder
 	}
+	return &m.collection.Folder
+}
 
-	data, err := json.MarshalIndent(savedRequests, "", "  ")
-	if err != nil {
-		return err
+// GetRequestFolderPath возвращает путь папки текущего запроса ("" для корня коллекции)
+func (m *AppModel) GetRequestFolderPath() string {
+	path := m.collection.Folder.pathTo(m.currentFolder())
+	if len(path) == 0 {
+		return ""
 	}
-	return ioutil.WriteFile(m.configPath, data, 0644)
+	names := make([]string, 0, len(path))
+	for _, f := range path[1:] {
+		names = append(names, f.Name)
+	}
+	return strings.Join(names, "/")
 }
 
+// AddNewSavedRequest сохраняет текущий запрос в папку, из которой он был загружен
 func (m *AppModel) AddNewSavedRequest(name string) {
-	newReq := SavedRequest{
-		Name:    name,
-		Method:  m.selectedMethod,
-		URL:     m.urlInput.Value(),
-		Body:    m.bodyInput.Value(),
-		Headers: m.headers,
-		Params:  m.params,
-	}
-	m.savedRequests = append(m.savedRequests, newReq)
-	m.savedList.SetItems(m.savedRequests)
+	newReq := m.CurrentRequest()
+	newReq.Name = name
+	m.addSavedRequest(m.currentFolder(), newReq)
+	m.markSaved(name)
+	m.SaveWorkspace()
+}
+
+func (m *AppModel) addSavedRequest(folder *Folder, sr SavedRequest) {
+	folder.Requests = append(folder.Requests, sr)
+	m.refreshSavedTree()
 	m.saveRequests()
 }
 
-func (m *AppModel) LoadRequestFromSaved() {
-	if item, ok := m.savedList.SelectedItem().(SavedRequest); ok {
-		m.selectedMethod = item.Method
-		m.urlInput.SetValue(item.URL)
-		m.bodyInput.SetValue(item.Body)
-		m.headers = item.Headers
-		m.params = item.Params
-		m.activeTab = TabRequest
+// AddFolder добавляет папку (например, импортированную коллекцию) в корень коллекции
+func (m *AppModel) AddFolder(folder *Folder) {
+	m.collection.Folders = append(m.collection.Folders, folder)
+	m.refreshSavedTree()
+	m.saveRequests()
+}
+
+// CreateFolder создает пустую папку рядом с выбранным элементом
+// (внутри выбранной папки, если курсор стоит на ней)
+func (m *AppModel) CreateFolder(name string) {
+	parent := &m.collection.Folder
+	if item, ok := m.GetSelectedTreeItem(); ok {
+		parent = item.Parent
+		if item.IsFolder() {
+			parent = item.Folder
+			m.expanded[item.Folder] = true
+		}
 	}
+	parent.Folders = append(parent.Folders, &Folder{Name: name})
+	m.refreshSavedTree()
+	m.saveRequests()
 }
 
-func (m *AppModel) DeleteSelectedRequest() {
-	if len(m.savedRequests) > 0 {
-		idx := m.savedList.Index()
-		m.savedRequests = append(m.savedRequests[:idx], m.savedRequests[idx+1:]...)
-		m.savedList.SetItems(m.savedRequests)
+// GetSelectedTreeItem возвращает выбранный элемент дерева на вкладке "Сохраненные"
+func (m *AppModel) GetSelectedTreeItem() (TreeItem, bool) {
+	item, ok := m.savedList.SelectedItem().(TreeItem)
+	return item, ok
+}
+
+// GetSelectedFolder возвращает выбранную на вкладке "Сохраненные" папку
+func (m *AppModel) GetSelectedFolder() (*Folder, bool) {
+	item, ok := m.GetSelectedTreeItem()
+	if !ok || !item.IsFolder() {
+		return nil, false
+	}
+	return item.Folder, true
+}
+
+// ToggleSelectedFolder сворачивает или разворачивает выбранную папку.
+// Возвращает false, если выбран не папка.
+func (m *AppModel) ToggleSelectedFolder() bool {
+	folder, ok := m.GetSelectedFolder()
+	if !ok {
+		return false
+	}
+	if m.treeShowAll {
+		// Во время фильтрации показываются все папки
+		return true
+	}
+	m.expanded[folder] = !m.expanded[folder]
+	m.refreshSavedTree()
+	return true
+}
+
+// SetSelectedFolderBaseURL задает базовый URL выбранной папки
+func (m *AppModel) SetSelectedFolderBaseURL(baseURL string) {
+	if folder, ok := m.GetSelectedFolder(); ok {
+		folder.BaseURL = baseURL
+		m.refreshSavedTree()
 		m.saveRequests()
 	}
 }
 
+// SetSelectedFolderHeaders задает общие заголовки выбранной папки
+func (m *AppModel) SetSelectedFolderHeaders(headers []Header) {
+	if folder, ok := m.GetSelectedFolder(); ok {
+		folder.Headers = headers
+		m.refreshSavedTree()
+		m.saveRequests()
+	}
+}
+
+// MarkSelectedRequest отмечает выбранный запрос для перемещения в другую папку
+func (m *AppModel) MarkSelectedRequest() {
+	item, ok := m.GetSelectedTreeItem()
+	if !ok || item.IsFolder() {
+		return
+	}
+	m.marked = &item
+}
+
+// ClearMark снимает отметку перемещения с запроса
+func (m *AppModel) ClearMark() {
+	m.marked = nil
+}
+
+// GetMarkedRequest возвращает отмеченный для перемещения запрос
+func (m *AppModel) GetMarkedRequest() (SavedRequest, bool) {
+	if m.marked == nil {
+		return SavedRequest{}, false
+	}
+	return m.marked.Request(), true
+}
+
+// MoveMarkedRequest перемещает отмеченный запрос в выбранную папку
+// (или в папку выбранного запроса)
+func (m *AppModel) MoveMarkedRequest() {
+	item, ok := m.GetSelectedTreeItem()
+	if m.marked == nil || !ok {
+		return
+	}
+	target := item.Parent
+	if item.IsFolder() {
+		target = item.Folder
+		m.expanded[target] = true
+	}
+
+	from := m.marked
+	m.marked = nil
+	if target == from.Parent {
+		return
+	}
+	sr := from.Request()
+	from.Parent.Requests = append(from.Parent.Requests[:from.Index], from.Parent.Requests[from.Index+1:]...)
+	target.Requests = append(target.Requests, sr)
+	m.refreshSavedTree()
+	m.saveRequests()
+}
+
+// LoadRequestFromSaved загружает выбранный запрос на вкладку "Запрос".
+// Если запрос уже открыт, активируется его вкладка.
+func (m *AppModel) LoadRequestFromSaved(newTab bool) {
+	item, ok := m.GetSelectedTreeItem()
+	if !ok || item.IsFolder() {
+		return
+	}
+	sr := item.Request()
+	if s := m.findSession(item.Parent, sr.Name); s != nil {
+		m.switchSession(s)
+		m.activeTab = TabRequest
+		return
+	}
+	m.loadRequest(sr, item.Parent, newTab)
+}
+
+// ImportRequest загружает импортированный запрос в активную вкладку запроса
+func (m *AppModel) ImportRequest(sr SavedRequest) {
+	m.apply(sr)
+	m.activeTab = TabRequest
+}
+
+// GetSelectedSavedRequest возвращает выбранный на вкладке "Сохраненные" запрос
+// с унаследованными от папок значениями
+func (m *AppModel) GetSelectedSavedRequest() (SavedRequest, bool) {
+	item, ok := m.GetSelectedTreeItem()
+	if !ok || item.IsFolder() {
+		return SavedRequest{}, false
+	}
+	return m.collection.DefaultsFor(item.Parent).Apply(item.Request()), true
+}
+
+// DeleteSelectedItem удаляет выбранный запрос или папку вместе с содержимым
+func (m *AppModel) DeleteSelectedItem() {
+	item, ok := m.GetSelectedTreeItem()
+	if !ok {
+		return
+	}
+	if item.IsFolder() {
+		item.Parent.removeFolder(item.Folder)
+		delete(m.expanded, item.Folder)
+		for _, s := range m.sessions {
+			if s.requestFolder != nil && m.collection.Folder.pathTo(s.requestFolder) == nil {
+				s.requestFolder = nil
+			}
+		}
+	} else {
+		item.Parent.Requests = append(item.Parent.Requests[:item.Index], item.Parent.Requests[item.Index+1:]...)
+	}
+	m.marked = nil
+	m.refreshSavedTree()
+	m.saveRequests()
+}
+
 // --- Обновление состояния ---
 
 func (m *AppModel) UpdateDimensions(width, height int) {
@@ -276,41 +688,145 @@ func (m *AppModel) UpdateDimensions(width, height int) {
 	contentWidth := width - 4
 
 	m.responseVP.Width = contentWidth
-	m.responseVP.Height = contentHeight
+	m.responseVP.Height = contentHeight - 2 // строка подвкладок ответа
+	m.runVP.Width = contentWidth
+	m.runVP.Height = contentHeight
+	m.loadVP.Width = contentWidth
+	m.loadVP.Height = contentHeight
 	m.savedList.SetSize(contentWidth, contentHeight)
+	m.historyList.SetSize(contentWidth, contentHeight)
+	m.cookieList.SetSize(contentWidth, contentHeight)
 
-	m.urlInput.Width = contentWidth - 14
 	m.paramInput.Width = contentWidth - 14
 	m.headerInput.Width = contentWidth - 14
-	m.bodyInput.SetWidth(contentWidth - 14 - 2)
 	m.saveNameInput.Width = contentWidth - 20
+	m.importInput.Width = contentWidth - 20
+	m.folderInput.Width = contentWidth - 20
+	m.settingsInput.Width = contentWidth - 24
+	m.cookieInput.Width = contentWidth - 20
+	m.responseInput.Width = contentWidth - 20
+	m.assertionInput.Width = contentWidth - 20
+	m.extractInput.Width = contentWidth - 24
+	m.runInput.Width = contentWidth - 24
+	m.loadInput.Width = contentWidth - 24
+
+	for _, s := range m.sessions {
+		m.resizeSession(s)
+	}
+}
 
-	occupiedHeight := len(m.headers) + len(m.params) + 17
+// resizeSession подгоняет поля вкладки запроса под размер окна
+func (m *AppModel) resizeSession(s *RequestSession) {
+	contentHeight := m.height - 5
+	if contentHeight < 10 {
+		contentHeight = 10
+	}
+	contentWidth := m.width - 4
+
+	s.urlInput.Width = contentWidth - 14
+	s.authInput.Width = contentWidth - 14 - 12 // место для названия способа авторизации
+	s.bodyInput.SetWidth(contentWidth - 14 - 2)
+
+	occupiedHeight := len(s.headers) + len(s.params) + 21
 	bodyHeight := contentHeight - occupiedHeight
 	if bodyHeight < 3 {
 		bodyHeight = 3
 	}
-	m.bodyInput.SetHeight(bodyHeight)
+	s.bodyInput.SetHeight(bodyHeight)
+}
+
+// StartRequest отмечает начало отправки запроса активной вкладки с указанным идентификатором.
+// Ответы на отправленные ранее из этой вкладки запросы после этого только сохраняются в историю.
+func (m *AppModel) StartRequest(id uint64) {
+	m.loading = true
+	m.activeRequest = id
+}
+
+// GetActiveRequestID возвращает идентификатор ответа, ожидаемого активной вкладкой
+func (m *AppModel) GetActiveRequestID() uint64 {
+	return m.activeRequest
 }
 
+// SetResponseData передает ответ вкладке, отправившей запрос. Ответ на замененный
+// более новым запрос или на запрос закрытой вкладки только сохраняется в историю.
 func (m *AppModel) SetResponseData(data ResponseData) {
-	m.loading = false
-	m.response = FormatJSON(data.Body)
-	m.status = fmt.Sprintf("%s (%d)", data.Status, data.StatusCode)
-	m.responseTime = data.Time
-	m.responseVP.SetContent(m.response)
-	m.errorMsg = ""
+	if s := m.sessionForRequest(data.RequestID); s != nil {
+		s.setResponse(data)
+		m.showSessionResult(s)
+	} else {
+		// Ответ больше не ожидается ни одной вкладкой
+		RemoveBodyFile(data)
+	}
+	m.addHistoryEntry(NewHistoryEntry(data))
+	// Ответ мог установить cookies
+	m.refreshCookieList()
+	m.storeVariables(ExtractedVariables(data.Extracted))
+}
+
+// storeVariables записывает извлеченные из ответа значения в активное окружение,
+// а без окружения - в переменные сеанса, которые не сохраняются между запусками
+func (m *AppModel) storeVariables(vars []Variable) {
+	if len(vars) == 0 {
+		return
+	}
+	names := make([]string, len(vars))
+	for i, v := range vars {
+		names[i] = v.Key
+	}
+	if env := m.environments.Active; env != "" {
+		if err := m.MergeEnvironmentVariables(env, vars); err != nil {
+			m.notice = fmt.Sprintf("Ошибка сохранения переменных окружения: %v", err)
+			return
+		}
+		m.addNotice(fmt.Sprintf("Переменные окружения %s: %s", env, strings.Join(names, ", ")))
+		return
+	}
+	if m.runtimeVars == nil {
+		m.runtimeVars = map[string]string{}
+	}
+	for _, v := range vars {
+		m.runtimeVars[v.Key] = v.Value
+	}
+	m.addNotice("Переменные сеанса: " + strings.Join(names, ", "))
+}
+
+// addNotice дополняет текущее сообщение, например о полученном ответе
+func (m *AppModel) addNotice(notice string) {
+	if m.notice != "" {
+		notice = m.notice + "; " + notice
+	}
+	m.notice = notice
+}
+
+// showSessionResult показывает полученный результат, если вкладка активна,
+// иначе сообщает о нем
+func (m *AppModel) showSessionResult(s *RequestSession) {
+	if s != m.RequestSession {
+		m.notice = fmt.Sprintf("Получен ответ во вкладке %d: %s", slices.Index(m.sessions, s)+1, s.TabTitle())
+		return
+	}
+	m.updateResponseContent()
 	m.activeTab = TabResponse
 }
 
+// updateResponseContent показывает в области ответа содержимое активной подвкладки с начала
+func (m *AppModel) updateResponseContent() {
+	m.renderResponseContent()
+	m.responseVP.GotoTop()
+}
+
 func (m *AppModel) SetError(err ErrorData) {
-	m.loading = false
-	m.errorMsg = err.Message
-	m.response = ""
-	m.status = "Error"
-	m.responseTime = ""
-	m.responseVP.SetContent(err.Message)
-	m.activeTab = TabResponse
+	entry := HistoryEntry{
+		Timestamp: time.Now(),
+		Request:   err.Request,
+		Status:    "Error",
+		Error:     err.Message,
+	}
+	if s := m.sessionForRequest(err.RequestID); s != nil {
+		s.setError(err.Message)
+		m.showSessionResult(s)
+	}
+	m.addHistoryEntry(entry)
 }
 
 func (m *AppModel) GetCurrentMethod() string {
@@ -393,6 +909,30 @@ func (m *AppModel) GetSaveNameInput() *textinput.Model {
 	return &m.saveNameInput
 }
 
+func (m *AppModel) GetImportInput() *textinput.Model {
+	return &m.importInput
+}
+
+func (m *AppModel) GetFolderInput() *textinput.Model {
+	return &m.folderInput
+}
+
+func (m *AppModel) GetSettingsInput() *textinput.Model {
+	return &m.settingsInput
+}
+
+func (m *AppModel) GetAssertionInput() *textinput.Model {
+	return &m.assertionInput
+}
+
+func (m *AppModel) GetExtractInput() *textinput.Model {
+	return &m.extractInput
+}
+
+func (m *AppModel) GetCookieInput() *textinput.Model {
+	return &m.cookieInput
+}
+
 func (m *AppModel) GetActiveSection() Section {
 	return m.activeSection
 }
@@ -433,6 +973,148 @@ func (m *AppModel) SetIsDeleting(deleting bool) {
 	m.isDeleting = deleting
 }
 
+func (m *AppModel) IsClosing() bool {
+	return m.isClosing
+}
+
+func (m *AppModel) SetIsClosing(closing bool) {
+	m.isClosing = closing
+}
+
+func (m *AppModel) IsImporting() bool {
+	return m.isImporting
+}
+
+func (m *AppModel) SetIsImporting(importing bool) {
+	m.isImporting = importing
+}
+
+// IsAsserting сообщает, редактируются ли проверки ответа
+func (m *AppModel) IsAsserting() bool {
+	return m.isAsserting
+}
+
+func (m *AppModel) SetIsAsserting(asserting bool) {
+	m.isAsserting = asserting
+}
+
+// IsExtracting сообщает, редактируются ли правила извлечения переменных
+func (m *AppModel) IsExtracting() bool {
+	return m.isExtracting
+}
+
+func (m *AppModel) SetIsExtracting(extracting bool) {
+	m.isExtracting = extracting
+}
+
+func (m *AppModel) GetFolderPrompt() FolderPrompt {
+	return m.folderPrompt
+}
+
+func (m *AppModel) SetFolderPrompt(prompt FolderPrompt) {
+	m.folderPrompt = prompt
+}
+
+func (m *AppModel) GetSettingsPrompt() SettingsPrompt {
+	return m.settingsPrompt
+}
+
+func (m *AppModel) SetSettingsPrompt(prompt SettingsPrompt) {
+	m.settingsPrompt = prompt
+}
+
+func (m *AppModel) GetCookiePrompt() CookiePrompt {
+	return m.cookiePrompt
+}
+
+// SetCookiePrompt открывает или закрывает ввод cookie. При редактировании
+// запоминается выбранная cookie, чтобы заменить ее после ввода.
+func (m *AppModel) SetCookiePrompt(prompt CookiePrompt) {
+	m.cookiePrompt = prompt
+	m.editedCookie = nil
+	if c, ok := m.GetSelectedCookie(); ok && prompt == CookiePromptEdit {
+		m.editedCookie = &c
+	}
+}
+
+// GetEditedCookie возвращает cookie, изменяемую в поле ввода (nil для новой)
+func (m *AppModel) GetEditedCookie() *StoredCookie {
+	return m.editedCookie
+}
+
+// GetClientSettings возвращает общие настройки HTTP клиента
+func (m *AppModel) GetClientSettings() ClientSettings {
+	return m.clientSettings
+}
+
+// SetClientSettings заменяет общие настройки HTTP клиента и сохраняет их
+func (m *AppModel) SetClientSettings(settings ClientSettings) error {
+	m.clientSettings = settings
+	return SaveClientSettings(settings)
+}
+
+func (m *AppModel) loadEnvironments() error {
+	set, err := LoadEnvironments()
+	if err != nil {
+		// Не перезаписываем файл, который не удалось прочитать
+		m.envErr = fmt.Errorf("окружения не загружены: %w", err)
+		m.addNotice("Ошибка: " + m.envErr.Error())
+		return err
+	}
+	m.environments = set
+	return nil
+}
+
+func (m *AppModel) saveEnvironments() error {
+	if m.envErr != nil {
+		return m.envErr
+	}
+	return SaveEnvironments(m.environments)
+}
+
+// NextEnvironment переключает активное окружение и сохраняет выбор
+func (m *AppModel) NextEnvironment() {
+	m.environments.Next()
+	if err := m.saveEnvironments(); err != nil {
+		m.notice = "Выбор окружения не сохранен: " + err.Error()
+	}
+	m.refreshCookieList()
+}
+
+// MergeEnvironmentVariables добавляет переменные в окружение с указанным именем
+// (создавая его при необходимости) и сохраняет окружения
+func (m *AppModel) MergeEnvironmentVariables(name string, vars []Variable) error {
+	m.environments.MergeVariables(name, vars)
+	return m.saveEnvironments()
+}
+
+func (m *AppModel) GetActiveEnvironment() string {
+	return m.environments.Active
+}
+
+// GetActiveVariables возвращает переменные коллекции, переопределенные переменными
+// активного окружения, а без окружения - переменными сеанса
+func (m *AppModel) GetActiveVariables() map[string]string {
+	env := m.environments.ActiveVariables()
+	if m.environments.Active == "" {
+		env = m.runtimeVars
+	}
+	return MergeVariables(m.collection.Variables, env)
+}
+
+func (m *AppModel) GetPreview() string {
+	return m.preview
+}
+
+func (m *AppModel) GetPreviewTitle() string {
+	return m.previewTitle
+}
+
+func (m *AppModel) SetPreview(title, preview string) {
+	m.previewTitle = title
+	m.preview = preview
+}
+
 func (m *AppModel) GetLoading() bool {
 	return m.loading
 }
@@ -441,6 +1123,21 @@ func (m *AppModel) GetResponse() string {
 	return m.response
 }
 
+func (m *AppModel) GetResponseView() ResponseView {
+	return m.responseView
+}
+
+// SetResponseView переключает подвкладку ответа (тело, заголовки, cookies, сведения)
+func (m *AppModel) SetResponseView(view ResponseView) {
+	m.responseView = view
+	m.updateResponseContent()
+}
+
+// GetResponseData возвращает данные последнего полученного ответа
+func (m *AppModel) GetResponseData() ResponseData {
+	return m.responseData
+}
+
 func (m *AppModel) GetStatus() string {
 	return m.status
 }
@@ -453,6 +1150,15 @@ func (m *AppModel) GetErrorMsg() string {
 	return m.errorMsg
 }
 
+func (m *AppModel) GetNotice() string {
+	return m.notice
+}
+
+// SetNotice задает информационное сообщение в нижней части интерфейса
+func (m *AppModel) SetNotice(notice string) {
+	m.notice = notice
+}
+
 func (m *AppModel) GetDimensions() (int, int) {
 	return m.width, m.height
 }
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResponseView представляет подвкладку вкладки "Ответ"
+type ResponseView int
+
+const (
+	ResponseViewBody ResponseView = iota
+	ResponseViewHeaders
+	ResponseViewCookies
+	ResponseViewTests
+	ResponseViewTimings
+	ResponseViewInfo
+)
+
+// ResponseViewCount количество подвкладок вкладки "Ответ"
+const ResponseViewCount = 6
+
+// ResponseViewNames содержит названия подвкладок вкладки "Ответ"
+var ResponseViewNames = []string{"Тело", "Заголовки", "Cookies", "Проверки", "Тайминги", "Сведения"}
+
+// Cookie представляет cookie, установленную ответом через Set-Cookie
+type Cookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Domain   string `json:"domain,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	MaxAge   int    `json:"maxAge,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	SameSite string `json:"sameSite,omitempty"`
+}
+
+// RedirectHop описывает ответ с перенаправлением, по которому перешел клиент
+type RedirectHop struct {
+	Method     string   `json:"method"`
+	URL        string   `json:"url"`
+	Status     string   `json:"status"`
+	StatusCode int      `json:"statusCode"`
+	Location   string   `json:"location"`
+	Headers    []Header `json:"headers,omitempty"`
+}
+
+// FormatHeaders форматирует заголовки по одному на строке с выравниванием значений
+func FormatHeaders(headers []Header) string {
+	if len(headers) == 0 {
+		return "Заголовков нет"
+	}
+	width := 0
+	for _, h := range headers {
+		if len(h.Key) > width {
+			width = len(h.Key)
+		}
+	}
+	var sb strings.Builder
+	for _, h := range headers {
+		fmt.Fprintf(&sb, "%-*s  %s\n", width+1, h.Key+":", h.Value)
+	}
+	return sb.String()
+}
+
+// FormatCookies форматирует cookies ответа вместе с их атрибутами
+func FormatCookies(cookies []Cookie) string {
+	if len(cookies) == 0 {
+		return "Ответ не устанавливает cookies"
+	}
+	var sb strings.Builder
+	for i, c := range cookies {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "%s = %s\n", c.Name, c.Value)
+		if c.Domain != "" {
+			fmt.Fprintf(&sb, "  Domain:   %s\n", c.Domain)
+		}
+		if c.Path != "" {
+			fmt.Fprintf(&sb, "  Path:     %s\n", c.Path)
+		}
+		if c.Expires != "" {
+			fmt.Fprintf(&sb, "  Expires:  %s\n", c.Expires)
+		}
+		if c.MaxAge != 0 {
+			fmt.Fprintf(&sb, "  Max-Age:  %d\n", c.MaxAge)
+		}
+		if c.SameSite != "" {
+			fmt.Fprintf(&sb, "  SameSite: %s\n", c.SameSite)
+		}
+		var flags []string
+		if c.Secure {
+			flags = append(flags, "Secure")
+		}
+		if c.HTTPOnly {
+			flags = append(flags, "HttpOnly")
+		}
+		if len(flags) > 0 {
+			fmt.Fprintf(&sb, "  Флаги:    %s\n", strings.Join(flags, ", "))
+		}
+	}
+	return sb.String()
+}
+
+// FormatSize форматирует размер в байтах в удобочитаемом виде
+func FormatSize(size int64) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d Б", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1f КБ (%d Б)", float64(size)/1024, size)
+	default:
+		return fmt.Sprintf("%.1f МБ (%d Б)", float64(size)/(1024*1024), size)
+	}
+}
+
+// FormatResponseInfo форматирует протокол, размер, цепочку перенаправлений ответа
+// и настройки клиента, с которыми выполнен запрос
+func FormatResponseInfo(data ResponseData) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Протокол:   %s\n", data.Proto)
+	fmt.Fprintf(&sb, "Статус:     %s\n", data.Status)
+	fmt.Fprintf(&sb, "Адрес:      %s\n", data.URL)
+	fmt.Fprintf(&sb, "Время:      %s\n", data.Time)
+	fmt.Fprintf(&sb, "Размер:     %s", FormatSize(data.Size))
+	if data.Encoding != "" {
+		fmt.Fprintf(&sb, ", сжатие %s", data.Encoding)
+	}
+	sb.WriteString("\n")
+	if data.DecodedSize != data.Size {
+		fmt.Fprintf(&sb, "Распакован: %s\n", FormatSize(data.DecodedSize))
+	}
+	if data.Charset != "" {
+		fmt.Fprintf(&sb, "Кодировка:  %s (перекодировано в UTF-8)\n", data.Charset)
+	}
+	if data.Binary {
+		sb.WriteString("Содержимое: двоичные данные\n")
+	}
+	if data.BodyFile != "" {
+		fmt.Fprintf(&sb, "Тело:       в памяти первые %s, полностью во временном файле %s\n", FormatSize(int64(len(data.Body))), data.BodyFile)
+	}
+
+	sb.WriteString("\nПеренаправления: ")
+	if len(data.Redirects) == 0 {
+		sb.WriteString("нет\n")
+	} else {
+		fmt.Fprintf(&sb, "%d\n", len(data.Redirects))
+		for i, hop := range data.Redirects {
+			fmt.Fprintf(&sb, "%d. %s %s\n   %s -> %s\n", i+1, hop.Method, hop.URL, hop.Status, hop.Location)
+		}
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(FormatClientSettingsInfo(data.Settings))
+	return sb.String()
+}
+
+// FormatClientSettingsInfo форматирует настройки клиента, с которыми выполнен запрос
+func FormatClientSettingsInfo(s ClientSettings) string {
+	orDefault := func(value, fallback string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	}
+	onOff := func(b bool) string {
+		if b {
+			return "да"
+		}
+		return "нет"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Настройки клиента:\n")
+	fmt.Fprintf(&sb, "  Таймаут:          %s\n", orDefault(s.Timeout, "нет"))
+	if s.FollowRedirects != nil && !*s.FollowRedirects {
+		sb.WriteString("  Перенаправления:  не выполнять\n")
+	} else {
+		fmt.Fprintf(&sb, "  Перенаправления:  до %d\n", s.RedirectLimit())
+	}
+	fmt.Fprintf(&sb, "  Без проверки TLS: %s\n", onOff(s.Insecure != nil && *s.Insecure))
+	fmt.Fprintf(&sb, "  Мин. версия TLS:  %s\n", orDefault(s.MinTLSVersion, "по умолчанию"))
+	fmt.Fprintf(&sb, "  Сертификаты CA:   %s\n", orDefault(s.CACert, "системные"))
+	if s.ClientCert != "" {
+		fmt.Fprintf(&sb, "  Сертификат:       %s\n", s.ClientCert)
+		fmt.Fprintf(&sb, "  Ключ:             %s\n", orDefault(s.ClientKey, s.ClientCert))
+	}
+	fmt.Fprintf(&sb, "  Прокси:           %s\n", orDefault(s.Proxy, "из окружения"))
+	fmt.Fprintf(&sb, "  Cookies:          %s\n", onOff(s.CookiesEnabled()))
+	fmt.Fprintf(&sb, "  Тело в памяти:    до %s\n", orDefault(s.MaxBodySize, "10MB"))
+	return sb.String()
+}
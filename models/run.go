@@ -0,0 +1,449 @@
+package models
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+// RunOptions задает параметры запуска папки или коллекции
+type RunOptions struct {
+	Iterations    int           // количество итераций (0 - по числу строк файла данных)
+	Delay         time.Duration // пауза между запросами
+	StopOnFailure bool          // остановить запуск после первого неуспешного запроса
+	DataFile      string        // файл CSV или JSON, строки которого становятся переменными итераций
+	FailOn        string        // диапазоны кодов ответа, считающиеся ошибкой ("" - не проверять)
+}
+
+// DefaultRunOptions возвращает параметры запуска по умолчанию
+func DefaultRunOptions() RunOptions {
+	return RunOptions{FailOn: "400-599"}
+}
+
+// FormatRunOptions записывает параметры запуска в строку "name=value; name2=value2"
+func FormatRunOptions(o RunOptions) string {
+	failOn := o.FailOn
+	if failOn == "" {
+		failOn = "none"
+	}
+	parts := []string{
+		fmt.Sprintf("iterations=%d", o.Iterations),
+		"delay=" + o.Delay.String(),
+		fmt.Sprintf("stop=%t", o.StopOnFailure),
+		"fail_on=" + failOn,
+	}
+	if o.DataFile != "" {
+		parts = append(parts, "data="+o.DataFile)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ParseRunOptions разбирает параметры запуска из строки "name=value; name2=value2".
+// Незаданные параметры получают значения по умолчанию.
+func ParseRunOptions(input string) (RunOptions, error) {
+	o := DefaultRunOptions()
+	for _, pair := range strings.Split(input, ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return o, fmt.Errorf("неверный параметр %q, ожидается name=value", strings.TrimSpace(pair))
+		}
+		name, value := strings.ToLower(strings.TrimSpace(parts[0])), strings.TrimSpace(parts[1])
+		var err error
+		switch name {
+		case "iterations":
+			if o.Iterations, err = strconv.Atoi(value); err != nil || o.Iterations < 0 {
+				err = fmt.Errorf("iterations: ожидается неотрицательное число, получено %q", value)
+			}
+		case "delay":
+			if o.Delay, err = ParseDelay(value); err != nil {
+				err = fmt.Errorf("delay: %v", err)
+			}
+		case "stop":
+			if o.StopOnFailure, err = strconv.ParseBool(value); err != nil {
+				err = fmt.Errorf("stop: ожидается true или false, получено %q", value)
+			}
+		case "data":
+			o.DataFile = value
+		case "fail_on":
+			o.FailOn = value
+			if value == "none" || value == "" {
+				o.FailOn = ""
+			} else if _, err = ParseStatusRanges(value); err != nil {
+				err = fmt.Errorf("fail_on: %v", err)
+			}
+		default:
+			err = fmt.Errorf("неизвестный параметр %q", name)
+		}
+		if err != nil {
+			return o, err
+		}
+	}
+	return o, nil
+}
+
+// ParseDelay разбирает паузу: число миллисекунд или длительность Go (500ms, 2s)
+func ParseDelay(value string) (time.Duration, error) {
+	if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("неверная пауза %q, ожидается например 500ms или 2s", value)
+	}
+	return d, nil
+}
+
+// FailRanges возвращает диапазоны кодов ответа, считающиеся ошибкой
+func (o RunOptions) FailRanges() []StatusRange {
+	return failRanges(o.FailOn)
+}
+
+func failRanges(failOn string) []StatusRange {
+	if failOn == "" {
+		return nil
+	}
+	// Диапазоны проверены при разборе параметров
+	ranges, _ := ParseStatusRanges(failOn)
+	return ranges
+}
+
+// IterationCount возвращает количество итераций с учетом строк файла данных
+func (o RunOptions) IterationCount(rows int) int {
+	switch {
+	case o.Iterations > 0:
+		return o.Iterations
+	case rows > 0:
+		return rows
+	}
+	return 1
+}
+
+// LoadRunData загружает строки файла данных запуска. Файл CSV должен содержать
+// строку заголовков с именами переменных, файл JSON - массив объектов.
+func LoadRunData(path string) ([]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".json" || ext != ".csv" && strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		return parseJSONRunData(data)
+	}
+	return parseCSVRunData(data)
+}
+
+// parseCSVRunData разбирает CSV: первая строка содержит имена переменных
+func parseCSVRunData(data []byte) ([]map[string]string, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("неверный CSV: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("файл данных пуст")
+	}
+	header := records[0]
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			row[strings.TrimSpace(name)] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseJSONRunData разбирает массив объектов. Строки становятся значениями
+// переменных без кавычек, остальные значения - записью JSON.
+func parseJSONRunData(data []byte) ([]map[string]string, error) {
+	root, err := ParseJSON(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("неверный JSON: %v", err)
+	}
+	if root.Kind != JSONArray {
+		return nil, fmt.Errorf("файл данных JSON должен содержать массив объектов")
+	}
+	rows := make([]map[string]string, 0, len(root.Items))
+	for i, item := range root.Items {
+		if item.Kind != JSONObject {
+			return nil, fmt.Errorf("элемент %d не является объектом", i)
+		}
+		row := make(map[string]string, len(item.Keys))
+		for j, key := range item.Keys {
+			value := item.Items[j]
+			if value.Kind == JSONString {
+				row[key] = value.Scalar
+			} else {
+				row[key] = value.Compact()
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// RunResult содержит результат выполнения одного запроса при запуске
+type RunResult struct {
+	RunID      uint64 // Идентификатор запуска, по которому результат сопоставляется с ним
+	Iteration  int    // Номер итерации, начиная с 1
+	Name       string // Путь запроса в коллекции
+	Method     string
+	URL        string
+	Passed     bool   // Запрос выполнен, проверки пройдены и код ответа не считается ошибкой
+	Error      string // Ошибка выполнения запроса
+	Assertions []AssertionResult
+	Extracted  []ExtractionResult
+	Response   ResponseData // Данные ответа без тела
+}
+
+// RunReport содержит итоги запуска папки или коллекции
+type RunReport struct {
+	RunID      uint64
+	Name       string // Папка или коллекция
+	Started    time.Time
+	Duration   time.Duration
+	Iterations int
+	Total      int // Количество запросов во всех итерациях
+	Results    []RunResult
+	Variables  []Variable // Переменные, извлеченные из ответов (последние значения)
+	Stopped    bool       // Запуск остановлен после неуспешного запроса
+	Canceled   bool       // Запуск отменен пользователем
+	Finished   bool
+}
+
+// Failed возвращает количество неуспешных запросов
+func (r RunReport) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// AssertionCounts возвращает количество пройденных и всех проверок запуска
+func (r RunReport) AssertionCounts() (passed, total int) {
+	for _, result := range r.Results {
+		passed += CountPassed(result.Assertions)
+		total += len(result.Assertions)
+	}
+	return passed, total
+}
+
+// FormatRunResult форматирует результат запроса строкой, а причины неуспеха -
+// строками с отступом под ней
+func FormatRunResult(r RunResult, iterations int) string {
+	var sb strings.Builder
+	mark := "✓"
+	if !r.Passed {
+		mark = "✗"
+	}
+	sb.WriteString(mark + " ")
+	if iterations > 1 {
+		fmt.Fprintf(&sb, "[%d/%d] ", r.Iteration, iterations)
+	}
+	fmt.Fprintf(&sb, "%s %s", r.Method, r.Name)
+	if r.Error != "" {
+		fmt.Fprintf(&sb, "\n    ошибка: %s", r.Error)
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "  %s  %s", r.Response.Status, formatDuration(r.Response.Timings.Total))
+	if len(r.Assertions) > 0 {
+		fmt.Fprintf(&sb, "  проверки %d/%d", CountPassed(r.Assertions), len(r.Assertions))
+	}
+	failedAssertions := false
+	for _, a := range r.Assertions {
+		if a.Passed {
+			continue
+		}
+		failedAssertions = true
+		fmt.Fprintf(&sb, "\n    ✗ %s", a.Assertion)
+		switch {
+		case a.Error != "":
+			fmt.Fprintf(&sb, ": %s", a.Error)
+		case a.Actual != "":
+			fmt.Fprintf(&sb, " (получено: %s)", a.Actual)
+		}
+	}
+	if !r.Passed && !failedAssertions {
+		sb.WriteString("\n    код ответа считается ошибкой (fail_on)")
+	}
+	for _, e := range r.Extracted {
+		if e.Error != "" {
+			fmt.Fprintf(&sb, "\n    переменная %s: %s", e.Variable, e.Error)
+		}
+	}
+	return sb.String()
+}
+
+// FormatRunSummary возвращает итоги запуска
+func FormatRunSummary(r RunReport) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Итого: запросов %d из %d, успешно %d, неуспешно %d", len(r.Results), r.Total, len(r.Results)-r.Failed(), r.Failed())
+	if passed, total := r.AssertionCounts(); total > 0 {
+		fmt.Fprintf(&sb, "; проверки %d из %d", passed, total)
+	}
+	fmt.Fprintf(&sb, "; время %s", formatDuration(r.Duration))
+	switch {
+	case r.Canceled:
+		sb.WriteString("\nЗапуск отменен")
+	case r.Stopped:
+		sb.WriteString("\nЗапуск остановлен после неуспешного запроса")
+	}
+	if len(r.Variables) > 0 {
+		names := make([]string, len(r.Variables))
+		for i, v := range r.Variables {
+			names[i] = v.Key
+		}
+		sb.WriteString("\nПеременные из ответов: " + strings.Join(names, ", "))
+	}
+	return sb.String()
+}
+
+// FormatRunProgress возвращает ход запуска: заголовок, полосу выполнения,
+// результаты запросов и итоги после завершения
+func FormatRunProgress(r RunReport, width int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Запуск: %s (итераций: %d, запросов: %d)\n", r.Name, r.Iterations, r.Total)
+
+	barWidth := max(10, min(width-30, 50))
+	done := 0
+	if r.Total > 0 {
+		done = barWidth * len(r.Results) / r.Total
+	}
+	elapsed := r.Duration
+	if !r.Finished {
+		elapsed = time.Since(r.Started)
+	}
+	fmt.Fprintf(&sb, "%s%s %d/%d  ✗ %d  %s\n\n", strings.Repeat("█", done), strings.Repeat("░", barWidth-done),
+		len(r.Results), r.Total, r.Failed(), elapsed.Round(time.Millisecond))
+
+	for _, result := range r.Results {
+		sb.WriteString(FormatRunResult(result, r.Iterations) + "\n")
+	}
+	if r.Finished {
+		sb.WriteString("\n" + FormatRunSummary(r))
+	} else {
+		sb.WriteString("⏳ Выполняется... (ctrl+x: остановить)")
+	}
+	return sb.String()
+}
+
+// --- Запуск в интерфейсе ---
+
+// SelectRunTarget выбирает для запуска папку, выделенную на вкладке
+// "Сохраненные", а если выделен запрос - всю коллекцию
+func (m *AppModel) SelectRunTarget() {
+	m.runTarget = ""
+	if item, ok := m.GetSelectedTreeItem(); ok && item.IsFolder() {
+		m.runTarget = item.Path
+	}
+}
+
+// RunTargetName возвращает название запускаемой папки или коллекции
+func (m *AppModel) RunTargetName() string {
+	if m.runTarget == "" {
+		return "коллекция"
+	}
+	return m.runTarget
+}
+
+// RunRequests возвращает запросы запускаемой папки в порядке дерева
+func (m *AppModel) RunRequests() ([]SavedRequest, error) {
+	return m.collection.FolderRequests(m.runTarget)
+}
+
+func (m *AppModel) GetRunOptions() RunOptions {
+	return m.runOptions
+}
+
+func (m *AppModel) SetRunOptions(options RunOptions) {
+	m.runOptions = options
+}
+
+// StartRun начинает показ хода запуска с указанным идентификатором на вкладке "Запуск"
+func (m *AppModel) StartRun(id uint64, iterations, total int) {
+	m.run = RunReport{
+		RunID:      id,
+		Name:       m.RunTargetName(),
+		Started:    time.Now(),
+		Iterations: iterations,
+		Total:      total,
+	}
+	m.activeTab = TabRunner
+	m.renderRunContent()
+}
+
+// AddRunResult добавляет результат запроса к ходу текущего запуска
+func (m *AppModel) AddRunResult(result RunResult) {
+	if result.RunID != m.run.RunID || m.run.Finished {
+		return
+	}
+	m.run.Results = append(m.run.Results, result)
+	m.renderRunContent()
+	m.runVP.GotoBottom()
+}
+
+// FinishRun показывает итоги запуска и записывает извлеченные из ответов
+// значения в переменные так же, как при отправке одного запроса
+func (m *AppModel) FinishRun(report RunReport) {
+	if report.RunID != m.run.RunID {
+		return
+	}
+	m.run = report
+	m.renderRunContent()
+	m.runVP.GotoBottom()
+	if m.activeTab != TabRunner {
+		m.notice = fmt.Sprintf("Запуск %s завершен: успешно %d из %d", report.Name, len(report.Results)-report.Failed(), len(report.Results))
+	}
+	m.storeVariables(report.Variables)
+	// Ответы могли установить cookies
+	m.refreshCookieList()
+}
+
+// IsRunning сообщает, выполняется ли запуск
+func (m *AppModel) IsRunning() bool {
+	return m.run.RunID != 0 && !m.run.Finished
+}
+
+func (m *AppModel) GetRunReport() RunReport {
+	return m.run
+}
+
+func (m *AppModel) GetRunVP() *viewport.Model {
+	return &m.runVP
+}
+
+func (m *AppModel) GetRunInput() *textinput.Model {
+	return &m.runInput
+}
+
+// IsRunPrompt сообщает, вводятся ли параметры запуска
+func (m *AppModel) IsRunPrompt() bool {
+	return m.isRunPrompt
+}
+
+func (m *AppModel) SetIsRunPrompt(prompt bool) {
+	m.isRunPrompt = prompt
+}
+
+// renderRunContent показывает ход запуска в области вкладки "Запуск"
+func (m *AppModel) renderRunContent() {
+	lines := strings.Split(FormatRunProgress(m.run, m.runVP.Width), "\n")
+	colorResultMarks(lines)
+	m.runVP.SetContent(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,457 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxTabTitleWidth ограничивает длину заголовка вкладки запроса
+const maxTabTitleWidth = 24
+
+// RequestSession представляет открытую вкладку запроса: редактируемый запрос
+// и последний полученный на него ответ
+type RequestSession struct {
+	name          string       // имя сохраненного запроса ("" для нового)
+	saved         SavedRequest // запрос на момент загрузки или сохранения
+	requestFolder *Folder      // папка, из которой загружен запрос
+
+	urlInput       textinput.Model
+	bodyInput      textarea.Model // тело, поля формы или путь к файлу в зависимости от bodyType
+	bodyType       BodyType
+	selectedMethod HTTPMethod
+	params         []Param
+	headers        []Header
+	authType       AuthType
+	authInput      textinput.Model // параметры авторизации в формате "name=value; name2=value2"
+	settings       ClientSettings  // настройки клиента, переопределяющие общие
+	assertions     []Assertion     // проверки ответа
+	extractions    []Extraction    // переменные, извлекаемые из ответа
+
+	loading       bool
+	activeRequest uint64 // Идентификатор отправки, ответ на которую ожидается во вкладке
+	response      string
+	responseData  ResponseData
+	status        string
+	responseTime  string
+	errorMsg      string
+	bodyView      bodyViewState // поиск, фильтр и дерево тела ответа
+}
+
+func newRequestSession() *RequestSession {
+	urlInput := textinput.New()
+	urlInput.Placeholder = "https://api.example.com/endpoint"
+	urlInput.CharLimit = 8192
+
+	bodyInput := textarea.New()
+	bodyInput.Placeholder = "{\"key\": \"value\"}"
+	bodyInput.ShowLineNumbers = false
+	bodyInput.FocusedStyle.CursorLine = lipgloss.NewStyle()
+
+	authInput := textinput.New()
+	authInput.CharLimit = 2048
+
+	s := &RequestSession{
+		urlInput:       urlInput,
+		bodyInput:      bodyInput,
+		authInput:      authInput,
+		selectedMethod: MethodGET,
+		params:         []Param{},
+		headers:        []Header{{Key: "Content-Type", Value: "application/json"}},
+	}
+	s.saved = s.request()
+	return s
+}
+
+// request возвращает запрос из полей вкладки
+func (s *RequestSession) request() SavedRequest {
+	sr := SavedRequest{
+		Name:     s.name,
+		Method:   s.selectedMethod,
+		URL:      s.urlInput.Value(),
+		BodyType: s.bodyType,
+		Headers:  s.headers,
+		Params:   s.params,
+		Auth:     s.auth(),
+		Settings: s.settings,
+	}
+	if len(s.assertions) > 0 {
+		sr.Assertions = s.assertions
+	}
+	if len(s.extractions) > 0 {
+		sr.Extract = s.extractions
+	}
+	switch {
+	case s.bodyType.HasForm():
+		sr.Form = ParseFormFields(s.bodyInput.Value(), s.bodyType == BodyMultipart)
+	case s.bodyType != BodyNone:
+		sr.Body = s.bodyInput.Value()
+	}
+	return sr
+}
+
+// SetBodyType меняет тип тела запроса. Текст в поле тела сохраняется.
+func (s *RequestSession) SetBodyType(t BodyType) {
+	s.bodyType = t
+	s.bodyInput.Placeholder = BodyPlaceholder(t)
+}
+
+// GetBodyType возвращает тип тела запроса вкладки
+func (s *RequestSession) GetBodyType() BodyType {
+	return s.bodyType
+}
+
+// GetRequestBodyKind возвращает формат тела запроса для подсветки: по типу тела,
+// для Raw - по заголовку Content-Type или содержимому
+func (s *RequestSession) GetRequestBodyKind() ContentKind {
+	switch {
+	case s.bodyType == BodyJSON:
+		return ContentJSON
+	case s.bodyType.HasForm():
+		return ContentForm
+	case s.bodyType != BodyRaw:
+		return ContentText
+	}
+	return DetectContentKind(HeaderValue(s.headers, "Content-Type"), s.bodyInput.Value())
+}
+
+// auth возвращает авторизацию из полей вкладки
+func (s *RequestSession) auth() Auth {
+	if s.authType == AuthNone {
+		return Auth{}
+	}
+	return ParseAuthParams(s.authType, s.authInput.Value())
+}
+
+// SetAuthType меняет способ авторизации. Общие для способов параметры сохраняются.
+func (s *RequestSession) SetAuthType(t AuthType) {
+	auth := s.auth()
+	auth.Type = t
+	s.authType = t
+	s.authInput.Placeholder = AuthPlaceholder(t)
+	s.authInput.SetValue(FormatAuthParams(auth))
+}
+
+// GetAuthType возвращает способ авторизации запроса вкладки
+func (s *RequestSession) GetAuthType() AuthType {
+	return s.authType
+}
+
+// GetRequestSettings возвращает настройки клиента запроса вкладки
+func (s *RequestSession) GetRequestSettings() ClientSettings {
+	return s.settings
+}
+
+// SetRequestSettings заменяет настройки клиента запроса вкладки
+func (s *RequestSession) SetRequestSettings(settings ClientSettings) {
+	s.settings = settings
+}
+
+// GetAssertions возвращает проверки ответа запроса вкладки
+func (s *RequestSession) GetAssertions() []Assertion {
+	return s.assertions
+}
+
+// SetAssertions заменяет проверки ответа запроса вкладки
+func (s *RequestSession) SetAssertions(assertions []Assertion) {
+	s.assertions = assertions
+}
+
+// GetExtractions возвращает правила извлечения переменных из ответа
+func (s *RequestSession) GetExtractions() []Extraction {
+	return s.extractions
+}
+
+// SetExtractions заменяет правила извлечения переменных из ответа
+func (s *RequestSession) SetExtractions(extractions []Extraction) {
+	s.extractions = extractions
+}
+
+// GetAuthInput возвращает поле параметров авторизации
+func (s *RequestSession) GetAuthInput() *textinput.Model {
+	return &s.authInput
+}
+
+// apply заполняет поля вкладки значениями запроса. Заголовки и параметры
+// копируются, чтобы правки во вкладке не меняли сохраненную коллекцию.
+func (s *RequestSession) apply(sr SavedRequest) {
+	s.selectedMethod = sr.Method
+	s.urlInput.SetValue(sr.URL)
+	s.SetBodyType(sr.BodyType)
+	if sr.BodyType.HasForm() {
+		s.bodyInput.SetValue(FormatFormFields(sr.Form))
+	} else {
+		s.bodyInput.SetValue(sr.Body)
+	}
+	s.headers = append([]Header{}, sr.Headers...)
+	s.params = append([]Param{}, sr.Params...)
+	s.authType = sr.Auth.Type
+	s.authInput.Placeholder = AuthPlaceholder(sr.Auth.Type)
+	s.authInput.SetValue(FormatAuthParams(sr.Auth))
+	s.settings = sr.Settings
+	s.assertions = slices.Clone(sr.Assertions)
+	s.extractions = slices.Clone(sr.Extract)
+	s.requestFolder = nil
+}
+
+// markSaved запоминает текущее состояние вкладки как сохраненное
+func (s *RequestSession) markSaved(name string) {
+	s.name = name
+	s.saved = s.request()
+	s.saved.Headers = slices.Clone(s.headers)
+	s.saved.Params = slices.Clone(s.params)
+}
+
+// IsDirty сообщает, изменен ли запрос с момента загрузки или сохранения
+func (s *RequestSession) IsDirty() bool {
+	current := s.request()
+	return current.Method != s.saved.Method ||
+		current.URL != s.saved.URL ||
+		current.Body != s.saved.Body ||
+		current.BodyType != s.saved.BodyType ||
+		!slices.Equal(current.Form, s.saved.Form) ||
+		!slices.Equal(current.Headers, s.saved.Headers) ||
+		!slices.Equal(current.Params, s.saved.Params) ||
+		current.Auth != s.saved.Auth ||
+		FormatClientSettings(current.Settings) != FormatClientSettings(s.saved.Settings) ||
+		!slices.Equal(current.Assertions, s.saved.Assertions) ||
+		!slices.Equal(current.Extract, s.saved.Extract)
+}
+
+// IsLoading сообщает, ожидается ли ответ на запрос вкладки
+func (s *RequestSession) IsLoading() bool {
+	return s.loading
+}
+
+// TabTitle возвращает заголовок вкладки: имя сохраненного запроса или его URL
+func (s *RequestSession) TabTitle() string {
+	title := s.name
+	if title == "" {
+		title = s.urlInput.Value()
+	}
+	if title == "" {
+		return "Новый запрос"
+	}
+	if runes := []rune(title); len(runes) > maxTabTitleWidth {
+		title = string(runes[:maxTabTitleWidth-1]) + "…"
+	}
+	return fmt.Sprintf("%s %s", MethodNames[s.selectedMethod], title)
+}
+
+func (s *RequestSession) setResponse(data ResponseData) {
+	s.loading = false
+	if s.responseData.BodyFile != data.BodyFile {
+		RemoveBodyFile(s.responseData)
+	}
+	s.responseData = data
+	s.response = s.bodyView.setBody(data)
+	s.status = fmt.Sprintf("%s (%d)", data.Status, data.StatusCode)
+	s.responseTime = data.Time
+	s.errorMsg = ""
+}
+
+func (s *RequestSession) setError(message string) {
+	s.loading = false
+	s.errorMsg = message
+	RemoveBodyFile(s.responseData)
+	s.responseData = ResponseData{}
+	s.response = ""
+	s.status = "Error"
+	s.responseTime = ""
+	s.bodyView.refresh("")
+}
+
+// --- Управление вкладками ---
+
+// GetSessions возвращает открытые вкладки запросов
+func (m *AppModel) GetSessions() []*RequestSession {
+	return m.sessions
+}
+
+// GetSessionIndex возвращает номер активной вкладки запроса
+func (m *AppModel) GetSessionIndex() int {
+	return slices.Index(m.sessions, m.RequestSession)
+}
+
+// NewSession открывает пустую вкладку запроса
+func (m *AppModel) NewSession() {
+	m.openSession(newRequestSession())
+	m.activeTab = TabRequest
+	m.SaveWorkspace()
+}
+
+// CloseSession закрывает активную вкладку запроса. Вместо последней вкладки
+// открывается пустая.
+func (m *AppModel) CloseSession() {
+	index := m.GetSessionIndex()
+	RemoveBodyFile(m.responseData)
+	m.sessions = slices.Delete(m.sessions, index, index+1)
+	if len(m.sessions) == 0 {
+		m.sessions = []*RequestSession{newRequestSession()}
+		m.resizeSession(m.sessions[0])
+	}
+	m.switchSession(m.sessions[min(index, len(m.sessions)-1)])
+	m.SaveWorkspace()
+}
+
+// NextSession переключает вкладку запроса на delta позиций (по кругу)
+func (m *AppModel) NextSession(delta int) {
+	count := len(m.sessions)
+	index := ((m.GetSessionIndex()+delta)%count + count) % count
+	m.switchSession(m.sessions[index])
+}
+
+// openSession добавляет вкладку справа от активной и переключается на нее
+func (m *AppModel) openSession(s *RequestSession) {
+	m.resizeSession(s)
+	m.sessions = slices.Insert(m.sessions, m.GetSessionIndex()+1, s)
+	m.switchSession(s)
+}
+
+func (m *AppModel) switchSession(s *RequestSession) {
+	m.urlInput.Blur()
+	m.bodyInput.Blur()
+	m.authInput.Blur()
+	m.inputMode = false
+	m.RequestSession = s
+	m.updateResponseContent()
+}
+
+// findSession возвращает вкладку с сохраненным запросом из указанной папки
+func (m *AppModel) findSession(folder *Folder, name string) *RequestSession {
+	for _, s := range m.sessions {
+		if s.name == name && s.requestFolder == folder {
+			return s
+		}
+	}
+	return nil
+}
+
+// sessionForRequest возвращает вкладку, ожидающую ответ с указанным идентификатором
+func (m *AppModel) sessionForRequest(id uint64) *RequestSession {
+	for _, s := range m.sessions {
+		if s.loading && s.activeRequest == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// loadRequest открывает запрос во вкладке. Если в активной вкладке есть
+// несохраненные изменения или выполняется запрос, открывается новая вкладка.
+func (m *AppModel) loadRequest(sr SavedRequest, folder *Folder, newTab bool) {
+	if newTab || m.IsDirty() || m.loading {
+		m.openSession(newRequestSession())
+	}
+	m.apply(sr)
+	m.requestFolder = folder
+	m.markSaved(sr.Name)
+	m.activeTab = TabRequest
+	m.SaveWorkspace()
+}
+
+// --- Сохранение открытых вкладок ---
+
+// WorkspaceTab содержит состояние вкладки запроса между запусками
+type WorkspaceTab struct {
+	Folder  []string     `json:"folder,omitempty"` // путь папки запроса в коллекции
+	Request SavedRequest `json:"request"`
+	Saved   SavedRequest `json:"saved"`
+}
+
+// Workspace содержит набор открытых вкладок запросов
+type Workspace struct {
+	Active int            `json:"active"`
+	Tabs   []WorkspaceTab `json:"tabs"`
+}
+
+// getWorkspacePath возвращает файл открытых вкладок коллекции source: workspace.json
+// для requests.json, для файлов .http - отдельный файл в каталоге workspaces
+func getWorkspacePath(source string) (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if source == "" {
+		return filepath.Join(configDir, "workspace.json"), nil
+	}
+	sum := sha256.Sum256([]byte(source))
+	name := fmt.Sprintf("%s-%s.json", filepath.Base(source), hex.EncodeToString(sum[:8]))
+	return filepath.Join(configDir, "workspaces", name), nil
+}
+
+// LoadWorkspace загружает открытые при последнем запуске вкладки коллекции source
+func LoadWorkspace(source string) (Workspace, error) {
+	path, err := getWorkspacePath(source)
+	if err != nil {
+		return Workspace{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Workspace{}, nil
+		}
+		return Workspace{}, err
+	}
+	var ws Workspace
+	err = json.Unmarshal(data, &ws)
+	return ws, err
+}
+
+// SaveWorkspace сохраняет открытые вкладки коллекции source
+func SaveWorkspace(source string, ws Workspace) error {
+	path, err := getWorkspacePath(source)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(ws, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// loadWorkspace восстанавливает открытые вкладки. Папки ищутся по пути,
+// вкладки удаленных папок привязываются к корню коллекции.
+func (m *AppModel) loadWorkspace() error {
+	ws, err := LoadWorkspace(m.store.Source())
+	if err != nil || len(ws.Tabs) == 0 {
+		return err
+	}
+	m.sessions = make([]*RequestSession, len(ws.Tabs))
+	for i, tab := range ws.Tabs {
+		s := newRequestSession()
+		s.apply(tab.Request)
+		s.name = tab.Request.Name
+		s.saved = tab.Saved
+		s.requestFolder = m.collection.Folder.findPath(tab.Folder)
+		m.sessions[i] = s
+	}
+	m.RequestSession = m.sessions[min(max(ws.Active, 0), len(m.sessions)-1)]
+	return nil
+}
+
+// SaveWorkspace сохраняет открытые вкладки для восстановления при следующем запуске
+func (m *AppModel) SaveWorkspace() error {
+	ws := Workspace{Active: m.GetSessionIndex(), Tabs: make([]WorkspaceTab, len(m.sessions))}
+	for i, s := range m.sessions {
+		ws.Tabs[i] = WorkspaceTab{Request: s.request(), Saved: s.saved}
+		if path := m.collection.Folder.pathTo(s.requestFolder); len(path) > 1 {
+			for _, f := range path[1:] {
+				ws.Tabs[i].Folder = append(ws.Tabs[i].Folder, f.Name)
+			}
+		}
+	}
+	return SaveWorkspace(m.store.Source(), ws)
+}
@@ -0,0 +1,249 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxRedirects - количество перенаправлений, если оно не задано
+const DefaultMaxRedirects = 10
+
+// ClientSettings содержит настройки HTTP клиента. Пустые поля не переопределяют
+// значения уровня выше: настройки по умолчанию < общие настройки < настройки запроса.
+type ClientSettings struct {
+	Timeout         string `json:"timeout,omitempty"` // длительность в формате Go, например 30s или 1m30s
+	FollowRedirects *bool  `json:"followRedirects,omitempty"`
+	MaxRedirects    *int   `json:"maxRedirects,omitempty"` // 0 - перенаправления запрещены
+	Insecure        *bool  `json:"insecure,omitempty"`     // не проверять сертификат сервера
+	CACert          string `json:"caCert,omitempty"`       // файл PEM с дополнительными корневыми сертификатами
+	ClientCert      string `json:"clientCert,omitempty"`
+	ClientKey       string `json:"clientKey,omitempty"`
+	MinTLSVersion   string `json:"minTlsVersion,omitempty"` // 1.0, 1.1, 1.2 или 1.3
+	Proxy           string `json:"proxy,omitempty"`         // http://, https://, socks5:// или socks5h://
+	Cookies         *bool  `json:"cookies,omitempty"`       // отправлять и сохранять cookies окружения
+	MaxBodySize     string `json:"maxBodySize,omitempty"`   // размер тела ответа в памяти, например 10MB
+}
+
+// DefaultClientSettings возвращает настройки клиента по умолчанию
+func DefaultClientSettings() ClientSettings {
+	follow, insecure, cookies := true, false, true
+	maxRedirects := DefaultMaxRedirects
+	return ClientSettings{
+		Timeout:         "30s",
+		FollowRedirects: &follow,
+		MaxRedirects:    &maxRedirects,
+		Insecure:        &insecure,
+		Cookies:         &cookies,
+	}
+}
+
+// RedirectLimit возвращает максимальное количество перенаправлений
+func (s ClientSettings) RedirectLimit() int {
+	if s.MaxRedirects == nil {
+		return DefaultMaxRedirects
+	}
+	return *s.MaxRedirects
+}
+
+// CookiesEnabled сообщает, используются ли cookies окружения (по умолчанию да)
+func (s ClientSettings) CookiesEnabled() bool {
+	return s.Cookies == nil || *s.Cookies
+}
+
+// Merge возвращает настройки, в которых заданные в override поля заменяют текущие
+func (s ClientSettings) Merge(override ClientSettings) ClientSettings {
+	for _, field := range [][2]*string{
+		{&s.Timeout, &override.Timeout},
+		{&s.CACert, &override.CACert},
+		{&s.ClientCert, &override.ClientCert},
+		{&s.ClientKey, &override.ClientKey},
+		{&s.MinTLSVersion, &override.MinTLSVersion},
+		{&s.Proxy, &override.Proxy},
+		{&s.MaxBodySize, &override.MaxBodySize},
+	} {
+		if *field[1] != "" {
+			*field[0] = *field[1]
+		}
+	}
+	if override.FollowRedirects != nil {
+		s.FollowRedirects = override.FollowRedirects
+	}
+	if override.MaxRedirects != nil {
+		s.MaxRedirects = override.MaxRedirects
+	}
+	if override.Insecure != nil {
+		s.Insecure = override.Insecure
+	}
+	if override.Cookies != nil {
+		s.Cookies = override.Cookies
+	}
+	return s
+}
+
+// Expand возвращает настройки с подставленными значениями переменных
+func (s ClientSettings) Expand(vars map[string]string) ClientSettings {
+	for _, value := range []*string{&s.Timeout, &s.CACert, &s.ClientCert, &s.ClientKey, &s.MinTLSVersion, &s.Proxy, &s.MaxBodySize} {
+		*value = ExpandVariables(*value, vars)
+	}
+	return s
+}
+
+// TimeoutDuration возвращает таймаут запроса (0 - без ограничения)
+func (s ClientSettings) TimeoutDuration() (time.Duration, error) {
+	if s.Timeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(s.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("неверный таймаут %q", s.Timeout)
+	}
+	return timeout, nil
+}
+
+// MaxBodyBytes возвращает размер тела ответа, который хранится в памяти
+func (s ClientSettings) MaxBodyBytes() (int64, error) {
+	if s.MaxBodySize == "" {
+		return DefaultMaxBodySize, nil
+	}
+	return ParseSize(s.MaxBodySize)
+}
+
+// FormatClientSettings записывает заданные настройки в строку "name=value; name2=value2"
+func FormatClientSettings(s ClientSettings) string {
+	var pairs []string
+	add := func(name, value string) {
+		if value != "" {
+			pairs = append(pairs, name+"="+value)
+		}
+	}
+	add("timeout", s.Timeout)
+	if s.FollowRedirects != nil {
+		add("redirects", strconv.FormatBool(*s.FollowRedirects))
+	}
+	if s.MaxRedirects != nil {
+		add("max_redirects", strconv.Itoa(*s.MaxRedirects))
+	}
+	if s.Insecure != nil {
+		add("insecure", strconv.FormatBool(*s.Insecure))
+	}
+	add("ca", s.CACert)
+	add("cert", s.ClientCert)
+	add("key", s.ClientKey)
+	add("tls_min", s.MinTLSVersion)
+	add("proxy", s.Proxy)
+	if s.Cookies != nil {
+		add("cookies", strconv.FormatBool(*s.Cookies))
+	}
+	add("max_body", s.MaxBodySize)
+	return strings.Join(pairs, "; ")
+}
+
+// ParseClientSettings разбирает строку "name=value; name2=value2" в настройки клиента
+func ParseClientSettings(input string) (ClientSettings, error) {
+	var s ClientSettings
+	parseBool := func(name, value string) (*bool, error) {
+		switch strings.ToLower(value) {
+		case "true", "on", "yes", "1":
+			b := true
+			return &b, nil
+		case "false", "off", "no", "0":
+			b := false
+			return &b, nil
+		}
+		return nil, fmt.Errorf("%s: ожидается true или false, получено %q", name, value)
+	}
+
+	for _, pair := range strings.Split(input, ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return s, fmt.Errorf("неверный параметр %q, ожидается name=value", strings.TrimSpace(pair))
+		}
+		name, value := strings.ToLower(strings.TrimSpace(parts[0])), strings.TrimSpace(parts[1])
+		var err error
+		switch name {
+		case "timeout":
+			s.Timeout = value
+			_, err = s.TimeoutDuration()
+		case "redirects":
+			s.FollowRedirects, err = parseBool(name, value)
+		case "max_redirects":
+			n, convErr := strconv.Atoi(value)
+			if convErr != nil || n < 0 {
+				err = fmt.Errorf("max_redirects: ожидается неотрицательное число, получено %q", value)
+			}
+			s.MaxRedirects = &n
+		case "insecure":
+			s.Insecure, err = parseBool(name, value)
+		case "ca":
+			s.CACert = value
+		case "cert":
+			s.ClientCert = value
+		case "key":
+			s.ClientKey = value
+		case "tls_min":
+			s.MinTLSVersion = value
+		case "proxy":
+			s.Proxy = value
+		case "cookies":
+			s.Cookies, err = parseBool(name, value)
+		case "max_body":
+			s.MaxBodySize = value
+			_, err = s.MaxBodyBytes()
+		default:
+			err = fmt.Errorf("неизвестный параметр %q", name)
+		}
+		if err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// --- Общие настройки ---
+
+func getSettingsPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "settings.json"), nil
+}
+
+// LoadClientSettings загружает общие настройки HTTP клиента
+func LoadClientSettings() (ClientSettings, error) {
+	path, err := getSettingsPath()
+	if err != nil {
+		return ClientSettings{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ClientSettings{}, nil
+		}
+		return ClientSettings{}, err
+	}
+	var s ClientSettings
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// SaveClientSettings сохраняет общие настройки HTTP клиента
+func SaveClientSettings(s ClientSettings) error {
+	path, err := getSettingsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+)
+
+// Collection представляет дерево сохраненных запросов с общими переменными.
+// Корневая папка коллекции содержит запросы верхнего уровня и вложенные папки.
+type Collection struct {
+	Folder
+	Variables []Variable `json:"variables,omitempty"`
+}
+
+// RequestStore загружает и сохраняет коллекцию запросов
+type RequestStore interface {
+	Load() (Collection, error)
+	Save(Collection) error
+	// Source возвращает путь файла коллекции, по которому разделяется состояние
+	// открытых вкладок ("" - коллекция requests.json в каталоге конфигурации)
+	Source() string
+}
+
+// JSONStore хранит запросы в файле requests.json
+type JSONStore struct {
+	path string
+}
+
+// NewJSONStore создает хранилище запросов в файле requests.json в каталоге конфигурации
+func NewJSONStore() *JSONStore {
+	configPath, _ := getConfigPath()
+	return &JSONStore{path: configPath}
+}
+
+// Load читает коллекцию. Файл старого формата (плоский массив запросов)
+// загружается как коллекция без папок и перезаписывается при следующем сохранении.
+func (s *JSONStore) Load() (Collection, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Collection{}, nil
+		}
+		return Collection{}, err
+	}
+
+	var collection Collection
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &collection.Requests)
+	} else if len(trimmed) > 0 {
+		err = json.Unmarshal(trimmed, &collection)
+	}
+	return collection, err
+}
+
+func (s *JSONStore) Source() string {
+	return ""
+}
+
+func (s *JSONStore) Save(c Collection) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Timings содержит длительность этапов выполнения запроса.
+// При перенаправлениях этапы относятся к последнему запросу цепочки.
+type Timings struct {
+	DNS      time.Duration // Разрешение имени
+	Connect  time.Duration // Установка TCP соединения
+	TLS      time.Duration // TLS рукопожатие
+	Send     time.Duration // Отправка запроса
+	TTFB     time.Duration // Ожидание первого байта ответа после отправки запроса
+	Download time.Duration // Получение тела ответа
+	Total    time.Duration // Общее время, включая перенаправления
+	Reused   bool          // Соединение взято из пула, DNS/Connect/TLS не выполнялись
+}
+
+// timingsJSON представляет этапы в миллисекундах для истории и вывода CLI
+type timingsJSON struct {
+	DNS      float64 `json:"dns"`
+	Connect  float64 `json:"connect"`
+	TLS      float64 `json:"tls"`
+	Send     float64 `json:"send"`
+	TTFB     float64 `json:"ttfb"`
+	Download float64 `json:"download"`
+	Total    float64 `json:"total"`
+	Reused   bool    `json:"reused,omitempty"`
+}
+
+func (t Timings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(timingsJSON{
+		DNS:      Milliseconds(t.DNS),
+		Connect:  Milliseconds(t.Connect),
+		TLS:      Milliseconds(t.TLS),
+		Send:     Milliseconds(t.Send),
+		TTFB:     Milliseconds(t.TTFB),
+		Download: Milliseconds(t.Download),
+		Total:    Milliseconds(t.Total),
+		Reused:   t.Reused,
+	})
+}
+
+func (t *Timings) UnmarshalJSON(data []byte) error {
+	var raw timingsJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	fromMillis := func(ms float64) time.Duration { return time.Duration(ms * float64(time.Millisecond)) }
+	*t = Timings{
+		DNS:      fromMillis(raw.DNS),
+		Connect:  fromMillis(raw.Connect),
+		TLS:      fromMillis(raw.TLS),
+		Send:     fromMillis(raw.Send),
+		TTFB:     fromMillis(raw.TTFB),
+		Download: fromMillis(raw.Download),
+		Total:    fromMillis(raw.Total),
+		Reused:   raw.Reused,
+	}
+	return nil
+}
+
+// Milliseconds возвращает длительность в миллисекундах с точностью до микросекунды
+func Milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// timingPhase описывает этап запроса для вывода
+type timingPhase struct {
+	Name     string
+	Duration time.Duration
+}
+
+func (t Timings) phases() []timingPhase {
+	return []timingPhase{
+		{"DNS", t.DNS},
+		{"Connect", t.Connect},
+		{"TLS", t.TLS},
+		{"Send", t.Send},
+		{"TTFB", t.TTFB},
+		{"Download", t.Download},
+	}
+}
+
+// Summary возвращает этапы запроса одной строкой
+func (t Timings) Summary() string {
+	parts := make([]string, 0, 6)
+	for _, phase := range t.phases() {
+		parts = append(parts, fmt.Sprintf("%s %s", phase.Name, formatDuration(phase.Duration)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatWaterfall рисует этапы запроса в виде диаграммы: каждый этап начинается
+// там, где закончился предыдущий, длина полосы пропорциональна длительности
+func FormatWaterfall(t Timings, width int) string {
+	if width < 10 {
+		width = 10
+	}
+	total := t.Total
+	if total <= 0 {
+		return "Нет данных о времени выполнения"
+	}
+
+	var sb strings.Builder
+	var offset time.Duration
+	for _, phase := range t.phases() {
+		start := int(int64(width) * int64(offset) / int64(total))
+		length := int(int64(width) * int64(phase.Duration) / int64(total))
+		if phase.Duration > 0 && length == 0 {
+			length = 1
+		}
+		if start+length > width {
+			start = width - length
+		}
+		bar := strings.Repeat(" ", start) + strings.Repeat("█", length) + strings.Repeat(" ", width-start-length)
+		fmt.Fprintf(&sb, "%-9s │%s│ %s\n", phase.Name, bar, formatDuration(phase.Duration))
+		offset += phase.Duration
+	}
+	fmt.Fprintf(&sb, "%-9s  %s  %s\n", "Итого", strings.Repeat(" ", width), formatDuration(total))
+	if t.Reused {
+		sb.WriteString("\nСоединение использовано повторно: DNS, Connect и TLS не выполнялись\n")
+	}
+	return sb.String()
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond).String()
+	}
+	return d.Round(100 * time.Microsecond).String()
+}
@@ -5,7 +5,9 @@ import (
 	"strings"
 
 	"github.com/KharpukhaevV/postui/models"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // UIRenderer обрабатывает рендеринг интерфейса
@@ -65,13 +67,11 @@ func (r *UIRenderer) Render(model *models.AppModel) string {
 	}
 
 	var currentView string
-	switch model.GetActiveTab() {
-	case models.TabRequest:
-		currentView = r.renderRequestView(model)
-	case models.TabResponse:
-		currentView = r.renderResponseView(model)
-	case models.TabSaved:
-		currentView = r.renderSavedView(model)
+	switch {
+	case model.GetPreview() != "":
+		currentView = r.renderPreviewView(model)
+	default:
+		currentView = r.renderTabView(model)
 	}
 
 	header := r.renderHeader(model)
@@ -79,7 +79,7 @@ func (r *UIRenderer) Render(model *models.AppModel) string {
 
 	finalView := lipgloss.JoinVertical(lipgloss.Left,
 		header,
-		"", // Отступ
+		r.renderSessionTabs(model),
 		currentView,
 		footer,
 	)
@@ -87,19 +87,65 @@ func (r *UIRenderer) Render(model *models.AppModel) string {
 	return r.styles.docStyle.Render(finalView)
 }
 
+// renderTabView рендерит содержимое активной вкладки
+func (r *UIRenderer) renderTabView(model *models.AppModel) string {
+	var currentView string
+	switch model.GetActiveTab() {
+	case models.TabRequest:
+		currentView = r.renderRequestView(model)
+	case models.TabResponse:
+		currentView = r.renderResponseView(model)
+	case models.TabSaved:
+		currentView = r.renderSavedView(model)
+	case models.TabHistory:
+		currentView = r.renderHistoryView(model)
+	case models.TabCookies:
+		currentView = r.renderCookiesView(model)
+	case models.TabRunner:
+		currentView = r.renderRunnerView(model)
+	case models.TabLoad:
+		currentView = r.renderLoadView(model)
+	}
+	return currentView
+}
+
 // renderHeader рендерит заголовок и вкладки на одной строке
 func (r *UIRenderer) renderHeader(model *models.AppModel) string {
 	width, _ := model.GetDimensions()
 	title := r.styles.titleStyle.Render("REST Client TUI")
 	tabs := r.renderTabs(model)
+	env := r.renderEnvironment(model)
 
-	spacerWidth := width - lipgloss.Width(title) - lipgloss.Width(tabs) - r.styles.docStyle.GetHorizontalFrameSize()
+	spacerWidth := width - lipgloss.Width(title) - lipgloss.Width(env) - lipgloss.Width(tabs) - r.styles.docStyle.GetHorizontalFrameSize()
 	if spacerWidth < 0 {
 		spacerWidth = 0
 	}
 	spacer := lipgloss.NewStyle().Width(spacerWidth).Render("")
 
-	return lipgloss.JoinHorizontal(lipgloss.Left, title, spacer, tabs)
+	return lipgloss.JoinHorizontal(lipgloss.Left, title, env, spacer, tabs)
+}
+
+// renderEnvironment рендерит имя активного окружения, папку текущего запроса,
+// наличие своих настроек, количество проверок ответа и извлекаемых переменных
+func (r *UIRenderer) renderEnvironment(model *models.AppModel) string {
+	env := model.GetActiveEnvironment()
+	if env == "" {
+		env = "нет"
+	}
+	view := r.styles.helpTextStyle.Render("  Окружение: ") + r.styles.promptStyle.Render(env)
+	if folder := model.GetRequestFolderPath(); folder != "" {
+		view += r.styles.helpTextStyle.Render("  Папка: ") + r.styles.promptStyle.Render(folder)
+	}
+	if models.FormatClientSettings(model.GetRequestSettings()) != "" {
+		view += r.styles.helpTextStyle.Render("  Настройки: ") + r.styles.promptStyle.Render("свои")
+	}
+	if count := len(model.GetAssertions()); count > 0 {
+		view += r.styles.helpTextStyle.Render("  Проверки: ") + r.styles.promptStyle.Render(fmt.Sprint(count))
+	}
+	if count := len(model.GetExtractions()); count > 0 {
+		view += r.styles.helpTextStyle.Render("  Переменные из ответа: ") + r.styles.promptStyle.Render(fmt.Sprint(count))
+	}
+	return view
 }
 
 // renderFooter рендерит нижнюю часть интерфейса
@@ -108,13 +154,105 @@ func (r *UIRenderer) renderFooter(model *models.AppModel) string {
 	if model.IsSaving() {
 		return r.styles.promptStyle.Render("Сохранить как: ") + model.GetSaveNameInput().View()
 	}
+	if model.IsImporting() {
+		return r.styles.promptStyle.Render("Импорт из файла: ") + model.GetImportInput().View()
+	}
 	if model.IsDeleting() {
-		return r.styles.errorStyle.Render(fmt.Sprintf("Удалить '%s'? (y/n)", model.GetSavedList().SelectedItem().(models.SavedRequest).Title()))
+		return r.styles.errorStyle.Render(r.deletePrompt(model))
+	}
+	if model.IsClosing() {
+		return r.styles.errorStyle.Render(fmt.Sprintf("Закрыть вкладку '%s' с несохраненными изменениями? (y/n)", model.TabTitle()))
+	}
+	if prompt := model.GetFolderPrompt(); prompt != models.FolderPromptNone {
+		labels := map[models.FolderPrompt]string{
+			models.FolderPromptCreate:  "Новая папка: ",
+			models.FolderPromptBaseURL: "Базовый URL папки: ",
+			models.FolderPromptHeaders: "Заголовки папки: ",
+		}
+		return r.styles.promptStyle.Render(labels[prompt]) + model.GetFolderInput().View()
+	}
+	if prompt := model.GetCookiePrompt(); prompt != models.CookiePromptNone {
+		label := "Новая cookie: "
+		if prompt == models.CookiePromptEdit {
+			label = "Cookie: "
+		}
+		if model.GetNotice() != "" {
+			return r.styles.errorStyle.Render(model.GetNotice()+" ") + model.GetCookieInput().View()
+		}
+		return r.styles.promptStyle.Render(label) + model.GetCookieInput().View()
+	}
+	if prompt := model.GetResponsePrompt(); prompt != models.ResponsePromptNone {
+		label := "Поиск: "
+		switch prompt {
+		case models.ResponsePromptFilter:
+			label = "Фильтр ($ JSONPath, . jq): "
+		case models.ResponsePromptSave:
+			label = "Сохранить тело в файл: "
+		}
+		if model.GetNotice() != "" {
+			return r.styles.errorStyle.Render(model.GetNotice()+" ") + model.GetResponseInput().View()
+		}
+		return r.styles.promptStyle.Render(label) + model.GetResponseInput().View()
+	}
+	if model.IsAsserting() {
+		if model.GetNotice() != "" {
+			return r.styles.errorStyle.Render(model.GetNotice()+" ") + model.GetAssertionInput().View()
+		}
+		return r.styles.promptStyle.Render("Проверки ответа: ") + model.GetAssertionInput().View()
+	}
+	if model.IsRunPrompt() {
+		if model.GetNotice() != "" {
+			return r.styles.errorStyle.Render(model.GetNotice()+" ") + model.GetRunInput().View()
+		}
+		return r.styles.promptStyle.Render("Запуск ("+model.RunTargetName()+"): ") + model.GetRunInput().View()
+	}
+	if model.IsLoadPrompt() {
+		if model.GetNotice() != "" {
+			return r.styles.errorStyle.Render(model.GetNotice()+" ") + model.GetLoadInput().View()
+		}
+		return r.styles.promptStyle.Render("Нагрузка ("+model.LoadTargetName()+"): ") + model.GetLoadInput().View()
+	}
+	if model.IsExtracting() {
+		if model.GetNotice() != "" {
+			return r.styles.errorStyle.Render(model.GetNotice()+" ") + model.GetExtractInput().View()
+		}
+		return r.styles.promptStyle.Render("Переменные из ответа: ") + model.GetExtractInput().View()
+	}
+	if prompt := model.GetSettingsPrompt(); prompt != models.SettingsPromptNone {
+		label := "Настройки запроса: "
+		if prompt == models.SettingsPromptGlobal {
+			label = "Общие настройки: "
+		}
+		// Ошибка разбора показывается вместо подписи до следующего нажатия
+		if model.GetNotice() != "" {
+			return r.styles.errorStyle.Render(model.GetNotice()+" ") + model.GetSettingsInput().View()
+		}
+		return r.styles.promptStyle.Render(label) + model.GetSettingsInput().View()
+	}
+
+	if model.GetNotice() != "" {
+		return r.styles.promptStyle.Render(model.GetNotice())
+	}
+	if marked, ok := model.GetMarkedRequest(); ok && model.GetActiveTab() == models.TabSaved {
+		return r.styles.promptStyle.Render(fmt.Sprintf("Перемещение '%s': выберите папку и нажмите p (esc - отмена)", marked.Name))
+	}
+
+	if model.GetActiveTab() == models.TabRunner {
+		if model.IsRunning() {
+			return r.styles.helpTextStyle.Render("⏳ Запуск выполняется | j/k: прокрутка | ctrl+x: остановить")
+		}
+		return r.styles.helpTextStyle.Render("R: запустить снова | J: отчеты JUnit/JSON | j/k: прокрутка | ←/h/l/→: вкладки | q: выход")
+	}
+	if model.GetActiveTab() == models.TabLoad {
+		if model.IsLoadRunning() {
+			return r.styles.helpTextStyle.Render("⏳ Нагрузочный тест выполняется | j/k: прокрутка | ctrl+x: остановить")
+		}
+		return r.styles.helpTextStyle.Render("L: запустить снова | J: отчет JSON | j/k: прокрутка | ←/h/l/→: вкладки | q: выход")
 	}
 
 	// Статус выполнения запроса
 	if model.GetLoading() {
-		return "⏳ Отправка запроса..."
+		return "⏳ Отправка запроса... (ctrl+x: отменить)"
 	}
 	if model.GetErrorMsg() != "" {
 		return r.styles.errorStyle.Render("Ошибка: " + model.GetErrorMsg())
@@ -127,11 +265,28 @@ func (r *UIRenderer) renderFooter(model *models.AppModel) string {
 			r.styles.labelStyle.Render("Время:"),
 			lipgloss.NewStyle().Width(15).Render(model.GetResponseTime()),
 		)
+		if results := model.GetResponseData().Assertions; len(results) > 0 {
+			passed := models.CountPassed(results)
+			summary := r.styles.successStyle.Render(fmt.Sprintf("✓ %d/%d", passed, len(results)))
+			if passed < len(results) {
+				summary = r.styles.errorStyle.Render(fmt.Sprintf("✗ %d/%d", passed, len(results)))
+			}
+			statusInfo = lipgloss.JoinHorizontal(lipgloss.Top, statusInfo, r.styles.labelStyle.Render("Проверки:"), summary)
+		}
 		return lipgloss.JoinHorizontal(lipgloss.Left, successMsg, "  ", statusInfo)
 	}
 
 	// Подсказка по умолчанию
-	return r.styles.helpTextStyle.Render("←/h/l/→: вкладки | j/k: навигация | i: ввод | enter: выбрать/отправить | q: выход")
+	return r.styles.helpTextStyle.Render("←/h/l/→: вкладки | j/k: навигация | i: ввод | enter: выбрать/отправить | [/]: вкладки запросов | ctrl+t/ctrl+w: открыть/закрыть | o/O: настройки запроса/общие | T/X: проверки/переменные | R/L: запуск папки/нагрузка | e: окружение | p: предпросмотр | q: выход")
+}
+
+// deletePrompt возвращает вопрос подтверждения удаления выбранного запроса или папки
+func (r *UIRenderer) deletePrompt(model *models.AppModel) string {
+	item, _ := model.GetSelectedTreeItem()
+	if item.IsFolder() {
+		return fmt.Sprintf("Удалить папку '%s' и все запросы в ней (%d)? (y/n)", item.Folder.Name, item.Folder.Count())
+	}
+	return fmt.Sprintf("Удалить '%s'? (y/n)", item.Request().Name)
 }
 
 // renderTabs рендерит панель вкладок
@@ -139,6 +294,10 @@ func (r *UIRenderer) renderTabs(model *models.AppModel) string {
 	requestTab := r.styles.tabStyle.Render("Запрос")
 	responseTab := r.styles.tabStyle.Render("Ответ")
 	savedTab := r.styles.tabStyle.Render("Сохраненные")
+	historyTab := r.styles.tabStyle.Render("История")
+	cookiesTab := r.styles.tabStyle.Render("Cookies")
+	runnerTab := r.styles.tabStyle.Render("Запуск")
+	loadTab := r.styles.tabStyle.Render("Нагрузка")
 
 	switch model.GetActiveTab() {
 	case models.TabRequest:
@@ -147,9 +306,39 @@ func (r *UIRenderer) renderTabs(model *models.AppModel) string {
 		responseTab = r.styles.activeTabStyle.Render("Ответ")
 	case models.TabSaved:
 		savedTab = r.styles.activeTabStyle.Render("Сохраненные")
+	case models.TabHistory:
+		historyTab = r.styles.activeTabStyle.Render("История")
+	case models.TabCookies:
+		cookiesTab = r.styles.activeTabStyle.Render("Cookies")
+	case models.TabRunner:
+		runnerTab = r.styles.activeTabStyle.Render("Запуск")
+	case models.TabLoad:
+		loadTab = r.styles.activeTabStyle.Render("Нагрузка")
 	}
 
-	return lipgloss.JoinHorizontal(lipgloss.Left, requestTab, responseTab, savedTab)
+	return lipgloss.JoinHorizontal(lipgloss.Left, requestTab, responseTab, savedTab, historyTab, cookiesTab, runnerTab, loadTab)
+}
+
+// renderSessionTabs рендерит вкладки открытых запросов. Измененные запросы
+// отмечаются звездочкой, выполняющиеся - часами.
+func (r *UIRenderer) renderSessionTabs(model *models.AppModel) string {
+	active := model.GetSessionIndex()
+	tabs := make([]string, len(model.GetSessions()))
+	for i, s := range model.GetSessions() {
+		title := fmt.Sprintf("%d: %s", i+1, s.TabTitle())
+		if s.IsDirty() {
+			title += " *"
+		}
+		if s.IsLoading() {
+			title += " ⏳"
+		}
+		if i == active {
+			tabs[i] = r.styles.activeSectionStyle.Render("[" + title + "]")
+		} else {
+			tabs[i] = r.styles.helpTextStyle.Render(" " + title + " ")
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Left, tabs...)
 }
 
 // --- Рендеринг содержимого вкладок ---
@@ -161,17 +350,99 @@ func (r *UIRenderer) renderRequestView(model *models.AppModel) string {
 		r.styles.sectionStyle.Render(r.renderHeadersSection(model)),
 		r.styles.sectionStyle.Render(r.renderBodySection(model)),
 		r.styles.sectionStyle.Render(r.renderParamsSection(model)),
+		r.styles.sectionStyle.Render(r.renderAuthSection(model)),
 	)
 }
 
 func (r *UIRenderer) renderResponseView(model *models.AppModel) string {
-	return model.GetResponseVP().View()
+	return lipgloss.JoinVertical(lipgloss.Left,
+		r.styles.sectionStyle.Render(r.renderResponseViewTabs(model)),
+		model.GetResponseVP().View(),
+	)
+}
+
+// renderResponseViewTabs рендерит подвкладки ответа с количеством заголовков,
+// cookies и пройденных проверок
+func (r *UIRenderer) renderResponseViewTabs(model *models.AppModel) string {
+	data := model.GetResponseData()
+	tabs := make([]string, models.ResponseViewCount)
+	for i, name := range models.ResponseViewNames {
+		switch models.ResponseView(i) {
+		case models.ResponseViewHeaders:
+			name = fmt.Sprintf("%s (%d)", name, len(data.Headers))
+		case models.ResponseViewCookies:
+			name = fmt.Sprintf("%s (%d)", name, len(data.Cookies))
+		case models.ResponseViewTests:
+			if len(data.Assertions) > 0 {
+				name = fmt.Sprintf("%s (%d/%d)", name, models.CountPassed(data.Assertions), len(data.Assertions))
+			}
+		}
+		if models.ResponseView(i) == model.GetResponseView() {
+			tabs[i] = r.styles.activeTabStyle.Render(name)
+		} else {
+			tabs[i] = r.styles.tabStyle.Render(name)
+		}
+	}
+	hint := "  tab: переключить | /: поиск | f: фильтр | t: дерево | r: исходный | s: сохранить"
+	switch {
+	case model.IsTreeView():
+		hint = "  enter/space: свернуть | -/+: свернуть/развернуть все | /: поиск | f: фильтр | t: текст"
+	case model.IsRawView():
+		hint = "  tab: переключить | /: поиск | f: фильтр | t: дерево | r: форматировать | s: сохранить"
+	}
+	if model.GetResponseView() == models.ResponseViewBody && !model.IsRawView() && data.Body != "" {
+		hint += " | " + model.GetResponseBodyKind().Name()
+	}
+	if current, total := model.SearchStatus(); model.GetSearch() != "" {
+		hint += fmt.Sprintf(" | поиск %q: %d/%d (n/N)", model.GetSearch(), current, total)
+	}
+	if filter := model.GetBodyFilter(); filter != "" {
+		hint += " | фильтр: " + filter
+	}
+	if path := model.GetTreePath(); path != "" {
+		hint += " | " + path
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Left, append(tabs, r.styles.helpTextStyle.Render(hint))...)
 }
 
 func (r *UIRenderer) renderSavedView(model *models.AppModel) string {
 	return model.GetSavedList().View()
 }
 
+func (r *UIRenderer) renderHistoryView(model *models.AppModel) string {
+	return model.GetHistoryList().View()
+}
+
+func (r *UIRenderer) renderCookiesView(model *models.AppModel) string {
+	return model.GetCookieList().View()
+}
+
+// renderRunnerView рендерит ход и итоги запуска папки или коллекции
+func (r *UIRenderer) renderRunnerView(model *models.AppModel) string {
+	if model.GetRunReport().RunID == 0 {
+		return r.styles.helpTextStyle.Render("Запусков не было. Выберите папку на вкладке \"Сохраненные\" и нажмите R.")
+	}
+	return model.GetRunVP().View()
+}
+
+func (r *UIRenderer) renderLoadView(model *models.AppModel) string {
+	if model.GetLoadStats().LoadID == 0 {
+		return r.styles.helpTextStyle.Render("Нагрузочных тестов не было. Выберите запрос на вкладке \"Сохраненные\" и нажмите L.")
+	}
+	return model.GetLoadVP().View()
+}
+
+func (r *UIRenderer) renderPreviewView(model *models.AppModel) string {
+	title := r.styles.activeSectionStyle.Render(model.GetPreviewTitle())
+	hint := r.styles.helpTextStyle.Render("e: сменить окружение | любая клавиша: закрыть")
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		"",
+		r.styles.inputStyle.Render(model.GetPreview()),
+		hint,
+	)
+}
+
 // --- Рендеринг секций для вкладки "Запрос" ---
 
 func (r *UIRenderer) renderMethodSection(model *models.AppModel) string {
@@ -229,15 +500,35 @@ func (r *UIRenderer) renderBodySection(model *models.AppModel) string {
 	if model.GetActiveSection() == models.SectionBody {
 		label = r.styles.activeSectionStyle.Render("[4] Тело:")
 	}
+	// Тип тела показывается под названием секции и переключается клавишами h/l
+	label += "\n◀ " + model.GetBodyType().Name() + " ▶"
 	input := model.GetBodyInput()
 	view := input.View()
 	style := r.styles.inputStyle.Width(input.Width()).Height(input.Height())
 	if model.GetActiveSection() == models.SectionBody && model.GetInputMode() {
 		style = r.styles.activeInputStyle.Width(input.Width()).Height(input.Height())
+	} else if input.Value() != "" {
+		// Вне редактирования тело показывается с подсветкой синтаксиса
+		view = r.renderBodyPreview(input, model.GetRequestBodyKind())
 	}
 	return lipgloss.JoinHorizontal(lipgloss.Top, r.styles.labelStyle.Render(label), style.Render(view))
 }
 
+// renderBodyPreview показывает начало тела запроса с подсветкой в размерах поля ввода
+func (r *UIRenderer) renderBodyPreview(input *textarea.Model, kind models.ContentKind) string {
+	lines := strings.Split(models.HighlightBody(kind, input.Value()), "\n")
+	prompt := input.BlurredStyle.Prompt.Render(input.Prompt)
+	view := make([]string, input.Height())
+	for i := range view {
+		line := ""
+		if i < len(lines) {
+			line = ansi.Truncate(lines[i], input.Width(), "…")
+		}
+		view[i] = prompt + line
+	}
+	return strings.Join(view, "\n")
+}
+
 func (r *UIRenderer) renderParamsSection(model *models.AppModel) string {
 	label := "[5] Параметры:"
 	if model.GetActiveSection() == models.SectionParams {
@@ -256,3 +547,22 @@ func (r *UIRenderer) renderParamsSection(model *models.AppModel) string {
 	}
 	return lipgloss.JoinHorizontal(lipgloss.Top, r.styles.labelStyle.Render(label), style.Render(sb.String()))
 }
+
+func (r *UIRenderer) renderAuthSection(model *models.AppModel) string {
+	label := "[6] Авторизация:"
+	if model.GetActiveSection() == models.SectionAuth {
+		label = r.styles.activeSectionStyle.Render("[6] Авторизация:")
+	}
+	authType := model.GetAuthType()
+	view := lipgloss.NewStyle().Width(12).Render("◀ " + authType.Name() + " ▶")
+	if authType != models.AuthNone {
+		view += model.GetAuthInput().View()
+	}
+
+	input := model.GetAuthInput()
+	style := r.styles.inputStyle.Width(input.Width + 12)
+	if model.GetActiveSection() == models.SectionAuth && model.GetInputMode() {
+		style = r.styles.activeInputStyle.Width(input.Width + 12)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, r.styles.labelStyle.Render(label), style.Render(view))
+}